@@ -3,6 +3,7 @@ module github.com/jenkins-x-labs/helmboot
 require (
 	github.com/banzaicloud/bank-vaults v0.0.0-20190508130850-5673d28c46bd
 	github.com/cli/cli v0.6.2
+	github.com/fatih/color v1.7.0
 	github.com/go-yaml/yaml v2.1.0+incompatible
 	github.com/google/go-cmp v0.3.0
 	github.com/google/uuid v1.1.1
@@ -13,9 +14,11 @@ require (
 	github.com/jenkins-x/jx v0.0.0-20200406060952-65b6c7cada5e
 	github.com/jetstack/cert-manager v0.5.2
 	github.com/knative/serving v0.7.0
+	github.com/mattn/go-isatty v0.0.12
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/petergtz/pegomock v2.7.0+incompatible
 	github.com/pkg/errors v0.8.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/spf13/cobra v0.0.6
 	github.com/stretchr/testify v1.4.0
 	github.com/tektoncd/pipeline v0.8.0
@@ -0,0 +1,75 @@
+package bootconfig
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// FileName the default name of the optional configuration file in the working directory
+	FileName = ".helmboot.yaml"
+)
+
+// Config the optional configuration file used to default the command line arguments so that
+// teams can check in a reproducible boot configuration
+type Config struct {
+	// GitURL the git URL of the boot configuration
+	GitURL string `json:"gitUrl,omitempty"`
+
+	// GitRef the git ref of the boot configuration
+	GitRef string `json:"gitRef,omitempty"`
+
+	// VersionsRepo the git URL of the versions repository
+	VersionsRepo string `json:"versionsRepo,omitempty"`
+
+	// VersionsRef the git ref of the versions repository
+	VersionsRef string `json:"versionsRef,omitempty"`
+
+	// SecretKind the kind of secret manager to use
+	SecretKind string `json:"secretKind,omitempty"`
+
+	// Chart the chart used to install the boot Job
+	Chart string `json:"chart,omitempty"`
+
+	// BatchMode the default of the batch-mode flag
+	BatchMode *bool `json:"batchMode,omitempty"`
+
+	// Verbose the default of the verbose flag
+	Verbose *bool `json:"verbose,omitempty"`
+
+	// Quiet the default of the quiet flag
+	Quiet *bool `json:"quiet,omitempty"`
+}
+
+// LoadConfig loads the configuration from the given file name or if blank from the default file in the given directory.
+// If an explicit file name is given it must exist otherwise an empty configuration is returned if there is no default file
+func LoadConfig(fileName string, dir string) (*Config, string, error) {
+	answer := &Config{}
+	explicit := fileName != ""
+	if !explicit {
+		fileName = filepath.Join(dir, FileName)
+	}
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return answer, fileName, errors.Wrapf(err, "failed to check if file exists %s", fileName)
+	}
+	if !exists {
+		if explicit {
+			return answer, fileName, errors.Errorf("config file %s does not exist", fileName)
+		}
+		return answer, "", nil
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return answer, fileName, errors.Wrapf(err, "failed to load file %s", fileName)
+	}
+	err = yaml.Unmarshal(data, answer)
+	if err != nil {
+		return answer, fileName, errors.Wrapf(err, "failed to unmarshal YAML file %s", fileName)
+	}
+	return answer, fileName, nil
+}
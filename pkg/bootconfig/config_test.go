@@ -0,0 +1,37 @@
+package bootconfig_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/bootconfig"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	cfg, fileName, err := bootconfig.LoadConfig("", "test_data")
+	require.NoError(t, err, "failed to load config")
+
+	assert.Equal(t, filepath.Join("test_data", bootconfig.FileName), fileName, "config file name")
+	assert.Equal(t, "https://github.com/myorg/environment-mycluster-dev.git", cfg.GitURL, "GitURL")
+	assert.Equal(t, "v1.2.3", cfg.GitRef, "GitRef")
+	assert.Equal(t, "https://github.com/myorg/jenkins-x-versions.git", cfg.VersionsRepo, "VersionsRepo")
+	assert.Equal(t, "v2.0.0", cfg.VersionsRef, "VersionsRef")
+	assert.Equal(t, "gsm", cfg.SecretKind, "SecretKind")
+	require.NotNil(t, cfg.BatchMode, "BatchMode")
+	assert.True(t, *cfg.BatchMode, "BatchMode")
+	require.NotNil(t, cfg.Verbose, "Verbose")
+	assert.True(t, *cfg.Verbose, "Verbose")
+	assert.Nil(t, cfg.Quiet, "Quiet")
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	cfg, fileName, err := bootconfig.LoadConfig("", "does-not-exist")
+	require.NoError(t, err, "should not fail if there is no default config file")
+	require.NotNil(t, cfg, "should have returned an empty config")
+	assert.Equal(t, "", fileName, "should not have found a config file")
+
+	_, _, err = bootconfig.LoadConfig(filepath.Join("does-not-exist", bootconfig.FileName), "")
+	require.Error(t, err, "should have failed for an explicit missing config file")
+}
@@ -0,0 +1,136 @@
+package clienthelpers
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// AuditEventsKey the key in the audit ConfigMap which contains the events as lines of JSON
+	AuditEventsKey = "events"
+
+	// maxAuditAttempts the number of times we try to append an event if there are concurrent modifications
+	maxAuditAttempts = 10
+)
+
+// AuditEvent an entry in the audit trail of boot runs
+type AuditEvent struct {
+	Timestamp time.Time `json:"timestamp"`
+	Actor     string    `json:"actor,omitempty"`
+	GitURL    string    `json:"gitUrl,omitempty"`
+	GitRef    string    `json:"gitRef,omitempty"`
+	GitCommit string    `json:"gitCommit,omitempty"`
+	Outcome   string    `json:"outcome"`
+	Message   string    `json:"message,omitempty"`
+}
+
+// AppendAuditEvent appends the event to the given ConfigMap creating it if it does not exist.
+//
+// Concurrent modifications are detected via the resourceVersion of the ConfigMap in which case the event is appended
+// to the latest version so that parallel boots do not lose each others events
+func AppendAuditEvent(kubeClient kubernetes.Interface, ns string, name string, event *AuditEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit event to JSON")
+	}
+	line := string(data)
+
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	for i := 0; i < maxAuditAttempts; i++ {
+		cm, err := configMaps.Get(name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to get ConfigMap %s in namespace %s", name, ns)
+			}
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: ns,
+				},
+				Data: map[string]string{
+					AuditEventsKey: line + "\n",
+				},
+			}
+			_, err = configMaps.Create(cm)
+			if apierrors.IsAlreadyExists(err) {
+				continue
+			}
+			if err != nil {
+				return errors.Wrapf(err, "failed to create ConfigMap %s in namespace %s", name, ns)
+			}
+			return nil
+		}
+
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		events := cm.Data[AuditEventsKey]
+		if events != "" && !strings.HasSuffix(events, "\n") {
+			events += "\n"
+		}
+		cm.Data[AuditEventsKey] = events + line + "\n"
+		_, err = configMaps.Update(cm)
+		if apierrors.IsConflict(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to update ConfigMap %s in namespace %s", name, ns)
+		}
+		return nil
+	}
+	return errors.Errorf("failed to append the audit event to ConfigMap %s in namespace %s after %d attempts due to concurrent modifications", name, ns, maxAuditAttempts)
+}
+
+// BootedRef returns the commit which was booted if it was resolved otherwise the git ref
+func (e *AuditEvent) BootedRef() string {
+	if e.GitCommit != "" {
+		return e.GitCommit
+	}
+	return e.GitRef
+}
+
+// FindRollbackEvent returns the most recent successful event whose booted commit differs from the commit of the latest
+// event so that a bad boot can be rolled back to the previously booted commit. Events without a commit are compared
+// by their git ref. Returns nil if there is no such event
+func FindRollbackEvent(events []AuditEvent) *AuditEvent {
+	if len(events) == 0 {
+		return nil
+	}
+	currentRef := events[len(events)-1].BootedRef()
+	for i := len(events) - 2; i >= 0; i-- {
+		event := events[i]
+		bootedRef := event.BootedRef()
+		if event.Outcome == "succeeded" && bootedRef != "" && bootedRef != currentRef {
+			return &event
+		}
+	}
+	return nil
+}
+
+// LoadAuditEvents loads the events from the given ConfigMap
+func LoadAuditEvents(cm *corev1.ConfigMap) ([]AuditEvent, error) {
+	var answer []AuditEvent
+	if cm == nil || cm.Data == nil {
+		return answer, nil
+	}
+	for _, line := range strings.Split(cm.Data[AuditEventsKey], "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		event := AuditEvent{}
+		err := json.Unmarshal([]byte(line), &event)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to unmarshal audit event %s", line)
+		}
+		answer = append(answer, event)
+	}
+	return answer, nil
+}
@@ -0,0 +1,86 @@
+package clienthelpers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestAppendAuditEvent(t *testing.T) {
+	ns := "jx"
+	name := "helmboot-audit"
+	kubeClient := fake.NewSimpleClientset()
+
+	// lets simulate a concurrent modification on the first update
+	conflicts := 1
+	kubeClient.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts > 0 {
+			conflicts--
+			return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, name, nil)
+		}
+		return false, nil, nil
+	})
+
+	for _, outcome := range []string{"failed", "succeeded"} {
+		event := &clienthelpers.AuditEvent{
+			Timestamp: time.Now(),
+			Actor:     "someuser",
+			GitURL:    "https://github.com/myorg/env-mycluster-dev.git",
+			GitRef:    "master",
+			Outcome:   outcome,
+		}
+		err := clienthelpers.AppendAuditEvent(kubeClient, ns, name, event)
+		require.NoError(t, err, "failed to append audit event %s", outcome)
+	}
+	assert.Equal(t, 0, conflicts, "should have retried the conflicting update")
+
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(name, metav1.GetOptions{})
+	require.NoError(t, err, "failed to get ConfigMap %s", name)
+
+	events, err := clienthelpers.LoadAuditEvents(cm)
+	require.NoError(t, err, "failed to load audit events")
+	require.Len(t, events, 2, "audit events")
+	assert.Equal(t, "failed", events[0].Outcome, "first event outcome")
+	assert.Equal(t, "succeeded", events[1].Outcome, "second event outcome")
+	assert.Equal(t, "someuser", events[1].Actor, "second event actor")
+}
+
+func TestFindRollbackEvent(t *testing.T) {
+	assert.Nil(t, clienthelpers.FindRollbackEvent(nil), "no events")
+
+	events := []clienthelpers.AuditEvent{
+		{GitRef: "v1.0.0", Outcome: "succeeded"},
+		{GitRef: "v1.1.0", Outcome: "succeeded"},
+		{GitRef: "v1.2.0", Outcome: "failed"},
+		{GitRef: "v1.3.0", Outcome: "succeeded"},
+		{GitRef: "v1.3.0", Outcome: "failed"},
+	}
+	event := clienthelpers.FindRollbackEvent(events)
+	require.NotNil(t, event, "should have found a rollback event")
+	assert.Equal(t, "v1.1.0", event.GitRef, "rollback git ref")
+
+	assert.Nil(t, clienthelpers.FindRollbackEvent(events[:1]), "should not roll back with only one event")
+	assert.Nil(t, clienthelpers.FindRollbackEvent([]clienthelpers.AuditEvent{
+		{GitRef: "v1.0.0", Outcome: "failed"},
+		{GitRef: "v1.1.0", Outcome: "failed"},
+	}), "should not roll back to a failed event")
+
+	// a branch is rolled back to the commit it pointed at rather than its current HEAD
+	events = []clienthelpers.AuditEvent{
+		{GitRef: "master", GitCommit: "1111111111111111111111111111111111111111", Outcome: "succeeded"},
+		{GitRef: "master", GitCommit: "2222222222222222222222222222222222222222", Outcome: "succeeded"},
+		{GitRef: "master", GitCommit: "3333333333333333333333333333333333333333", Outcome: "failed"},
+	}
+	event = clienthelpers.FindRollbackEvent(events)
+	require.NotNil(t, event, "should have found a rollback event for the same branch")
+	assert.Equal(t, "2222222222222222222222222222222222222222", event.BootedRef(), "rollback commit")
+}
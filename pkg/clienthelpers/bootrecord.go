@@ -0,0 +1,88 @@
+package clienthelpers
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// BootRecordConfigMap the name of the ConfigMap which records the last successful boot
+	BootRecordConfigMap = "helmboot-last-boot"
+)
+
+// BootRecord the git source of the last successful boot
+type BootRecord struct {
+	GitURL    string
+	GitRef    string
+	GitCommit string
+	Timestamp time.Time
+}
+
+// Matches returns true if the record is for the given git URL and commit. Any credentials in the URL are ignored
+func (r *BootRecord) Matches(gitURL string, gitCommit string) bool {
+	return r.GitCommit != "" && r.GitCommit == gitCommit && r.GitURL == gitURL
+}
+
+// SaveBootRecord saves the record of the last successful boot in the given namespace
+func SaveBootRecord(kubeClient kubernetes.Interface, ns string, record *BootRecord) error {
+	data := map[string]string{
+		"gitUrl":    record.GitURL,
+		"gitRef":    record.GitRef,
+		"gitCommit": record.GitCommit,
+		"timestamp": record.Timestamp.UTC().Format(time.RFC3339),
+	}
+	configMaps := kubeClient.CoreV1().ConfigMaps(ns)
+	cm, err := configMaps.Get(BootRecordConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get ConfigMap %s in namespace %s", BootRecordConfigMap, ns)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      BootRecordConfigMap,
+				Namespace: ns,
+			},
+			Data: data,
+		}
+		_, err = configMaps.Create(cm)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create ConfigMap %s in namespace %s", BootRecordConfigMap, ns)
+		}
+		return nil
+	}
+	cm.Data = data
+	_, err = configMaps.Update(cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update ConfigMap %s in namespace %s", BootRecordConfigMap, ns)
+	}
+	return nil
+}
+
+// LoadBootRecord loads the record of the last successful boot in the given namespace or nil if there is none
+func LoadBootRecord(kubeClient kubernetes.Interface, ns string) (*BootRecord, error) {
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(BootRecordConfigMap, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get ConfigMap %s in namespace %s", BootRecordConfigMap, ns)
+	}
+	record := &BootRecord{
+		GitURL:    cm.Data["gitUrl"],
+		GitRef:    cm.Data["gitRef"],
+		GitCommit: cm.Data["gitCommit"],
+	}
+	timestamp := cm.Data["timestamp"]
+	if timestamp != "" {
+		record.Timestamp, err = time.Parse(time.RFC3339, timestamp)
+		if err != nil {
+			return record, errors.Wrapf(err, "failed to parse the timestamp %s of ConfigMap %s", timestamp, BootRecordConfigMap)
+		}
+	}
+	return record, nil
+}
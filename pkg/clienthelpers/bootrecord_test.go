@@ -0,0 +1,40 @@
+package clienthelpers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSaveAndLoadBootRecord(t *testing.T) {
+	ns := "jx"
+	kubeClient := fake.NewSimpleClientset()
+
+	record, err := clienthelpers.LoadBootRecord(kubeClient, ns)
+	require.NoError(t, err, "should not fail if there is no boot record")
+	assert.Nil(t, record, "should not have a boot record yet")
+
+	gitURL := "https://github.com/myorg/env-mycluster-dev.git"
+	for _, commit := range []string{"1111111111111111111111111111111111111111", "2222222222222222222222222222222222222222"} {
+		err = clienthelpers.SaveBootRecord(kubeClient, ns, &clienthelpers.BootRecord{
+			GitURL:    gitURL,
+			GitRef:    "master",
+			GitCommit: commit,
+			Timestamp: time.Now(),
+		})
+		require.NoError(t, err, "failed to save the boot record for commit %s", commit)
+	}
+
+	record, err = clienthelpers.LoadBootRecord(kubeClient, ns)
+	require.NoError(t, err, "failed to load the boot record")
+	require.NotNil(t, record, "should have loaded the boot record")
+	assert.Equal(t, "master", record.GitRef, "git ref")
+	assert.True(t, record.Matches(gitURL, "2222222222222222222222222222222222222222"), "should match the latest commit")
+	assert.False(t, record.Matches(gitURL, "1111111111111111111111111111111111111111"), "should not match an older commit")
+	assert.False(t, record.Matches("https://github.com/myorg/another.git", "2222222222222222222222222222222222222222"), "should not match another repository")
+	assert.False(t, record.Timestamp.IsZero(), "should have loaded the timestamp")
+}
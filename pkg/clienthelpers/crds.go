@@ -0,0 +1,76 @@
+package clienthelpers
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/pkg/errors"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultBootCRDs the Jenkins X core CRDs the boot Job depends on
+var DefaultBootCRDs = []string{
+	"environments.jenkins.io",
+	"pipelineactivities.jenkins.io",
+	"releases.jenkins.io",
+	"sourcerepositories.jenkins.io",
+	"teams.jenkins.io",
+	"users.jenkins.io",
+}
+
+// IsCRDEstablished returns true if the CRD has the Established condition
+func IsCRDEstablished(crd *apiextensionsv1beta1.CustomResourceDefinition) bool {
+	for _, c := range crd.Status.Conditions {
+		if c.Type == apiextensionsv1beta1.Established && c.Status == apiextensionsv1beta1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// PendingCRDs returns the sorted names of the CRDs which exist but are not yet established.
+// CRDs which do not exist are ignored as they are created by the boot Job itself
+func PendingCRDs(client apiextensionsclientset.Interface, names []string) ([]string, error) {
+	var answer []string
+	crds := client.ApiextensionsV1beta1().CustomResourceDefinitions()
+	for _, name := range names {
+		crd, err := crds.Get(name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				log.Logger().Debugf("CRD %s does not exist yet so not waiting for it", name)
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get CRD %s", name)
+		}
+		if !IsCRDEstablished(crd) {
+			answer = append(answer, name)
+		}
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+// WaitForCRDsEstablished waits for any of the given CRDs which exist to be established polling at the given interval
+// until the timeout
+func WaitForCRDsEstablished(client apiextensionsclientset.Interface, names []string, timeout time.Duration, pollInterval time.Duration) error {
+	end := time.Now().Add(timeout)
+	for {
+		pending, err := PendingCRDs(client, names)
+		if err != nil {
+			return err
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		if time.Now().After(end) {
+			return errors.Errorf("timed out after %s waiting for the CRDs to be established: %s", timeout.String(), strings.Join(pending, ", "))
+		}
+		log.Logger().Infof("waiting for the CRDs to be established: %s", strings.Join(pending, ", "))
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,40 @@
+package clienthelpers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiextensionsv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWaitForCRDsEstablished(t *testing.T) {
+	established := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "environments.jenkins.io"},
+		Status: apiextensionsv1beta1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1beta1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1beta1.Established, Status: apiextensionsv1beta1.ConditionTrue},
+			},
+		},
+	}
+	pending := &apiextensionsv1beta1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "sourcerepositories.jenkins.io"},
+	}
+	client := fake.NewSimpleClientset(established, pending)
+	names := []string{"environments.jenkins.io", "sourcerepositories.jenkins.io", "users.jenkins.io"}
+
+	actual, err := clienthelpers.PendingCRDs(client, names)
+	require.NoError(t, err, "failed to find pending CRDs")
+	assert.Equal(t, []string{"sourcerepositories.jenkins.io"}, actual, "should ignore established and missing CRDs")
+
+	err = clienthelpers.WaitForCRDsEstablished(client, names, 10*time.Millisecond, time.Millisecond)
+	require.Error(t, err, "should have timed out")
+	assert.Contains(t, err.Error(), "sourcerepositories.jenkins.io", "the error should name the pending CRD")
+
+	err = clienthelpers.WaitForCRDsEstablished(client, names[:1], 10*time.Millisecond, time.Millisecond)
+	assert.NoError(t, err, "should not wait for established CRDs")
+}
@@ -0,0 +1,68 @@
+package clienthelpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// BootEventComponent the source component of the boot progress Events
+	BootEventComponent = "helmboot"
+
+	// BootJobName the name of the boot Job the boot progress Events are recorded against
+	BootJobName = "jx-boot"
+)
+
+// CreateBootEvent creates a Kubernetes Event against the boot Job so that the progress of the boot can be
+// viewed via 'kubectl get events'. The Job does not need to exist yet
+func CreateBootEvent(client kubernetes.Interface, ns string, eventType string, reason string, message string) (*corev1.Event, error) {
+	now := metav1.NewTime(time.Now())
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", BootJobName, now.UnixNano()),
+			Namespace: ns,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "batch/v1",
+			Kind:       "Job",
+			Name:       BootJobName,
+			Namespace:  ns,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Count:          1,
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Source: corev1.EventSource{
+			Component: BootEventComponent,
+		},
+	}
+	answer, err := client.CoreV1().Events(ns).Create(event)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create Event %s in namespace %s", event.Name, ns)
+	}
+	return answer, nil
+}
+
+// BootEventMessage returns the message of a boot progress Event including the cluster name, if known, and git ref
+func BootEventMessage(phase string, clusterName string, gitURL string, gitRef string) string {
+	var parts []string
+	parts = append(parts, phase)
+	if clusterName != "" {
+		parts = append(parts, "for cluster "+clusterName)
+	}
+	if gitURL != "" {
+		parts = append(parts, "from "+gitURL)
+	}
+	if gitRef != "" {
+		parts = append(parts, "at git ref "+gitRef)
+	}
+	return strings.Join(parts, " ")
+}
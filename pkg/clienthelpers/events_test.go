@@ -0,0 +1,34 @@
+package clienthelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateBootEvent(t *testing.T) {
+	ns := "jx"
+	kubeClient := fake.NewSimpleClientset()
+
+	message := clienthelpers.BootEventMessage("launching the boot Job", "mycluster", "https://github.com/myorg/env-dev.git", "v1.2.3")
+	assert.Equal(t, "launching the boot Job for cluster mycluster from https://github.com/myorg/env-dev.git at git ref v1.2.3", message, "message")
+	assert.Equal(t, "cloning the boot configuration at git ref master", clienthelpers.BootEventMessage("cloning the boot configuration", "", "", "master"), "message without a cluster")
+
+	_, err := clienthelpers.CreateBootEvent(kubeClient, ns, corev1.EventTypeNormal, "Launching", message)
+	require.NoError(t, err, "failed to create the event")
+
+	events, err := kubeClient.CoreV1().Events(ns).List(metav1.ListOptions{})
+	require.NoError(t, err, "failed to list events")
+	require.Len(t, events.Items, 1, "events")
+	event := events.Items[0]
+	assert.Equal(t, "Job", event.InvolvedObject.Kind, "involved object kind")
+	assert.Equal(t, clienthelpers.BootJobName, event.InvolvedObject.Name, "involved object name")
+	assert.Equal(t, "Launching", event.Reason, "reason")
+	assert.Equal(t, message, event.Message, "message")
+	assert.Equal(t, clienthelpers.BootEventComponent, event.Source.Component, "source component")
+}
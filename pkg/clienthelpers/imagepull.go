@@ -0,0 +1,104 @@
+package clienthelpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// imagePullFailureReasons the container waiting reasons which mean the image cannot be pulled
+var imagePullFailureReasons = map[string]bool{
+	"ErrImagePull":     true,
+	"ImagePullBackOff": true,
+	"InvalidImageName": true,
+}
+
+// VerifyImagePullSecrets verifies each of the named image pull Secrets exists in the namespace and contains
+// docker registry credentials
+func VerifyImagePullSecrets(client kubernetes.Interface, ns string, names []string) error {
+	for _, name := range names {
+		secret, err := client.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return errors.Errorf("the image pull Secret %s does not exist in namespace %s so the boot image could not be pulled. Create it via 'kubectl create secret docker-registry' or specify --create-image-pull-secret", name, ns)
+			}
+			return errors.Wrapf(err, "failed to get the image pull Secret %s in namespace %s", name, ns)
+		}
+		if secret.Type != corev1.SecretTypeDockerConfigJson && secret.Type != corev1.SecretTypeDockercfg {
+			return errors.Errorf("the image pull Secret %s in namespace %s is of type %s but must be of type %s", name, ns, secret.Type, corev1.SecretTypeDockerConfigJson)
+		}
+	}
+	return nil
+}
+
+// CreateImagePullSecret creates or updates a docker registry Secret with the credentials for the registry server
+func CreateImagePullSecret(client kubernetes.Interface, ns string, name string, server string, username string, password string) error {
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	config := map[string]interface{}{
+		"auths": map[string]interface{}{
+			server: map[string]string{
+				"username": username,
+				"password": password,
+				"auth":     auth,
+			},
+		},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the docker config")
+	}
+
+	secretInterface := client.CoreV1().Secrets(ns)
+	secret, err := secretInterface.Get(name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get Secret %s in namespace %s", name, ns)
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: ns,
+			},
+			Type: corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: data,
+			},
+		}
+		_, err = secretInterface.Create(secret)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create Secret %s in namespace %s", name, ns)
+		}
+		return nil
+	}
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		return errors.Errorf("cannot update the Secret %s in namespace %s as it is of type %s rather than %s", name, ns, secret.Type, corev1.SecretTypeDockerConfigJson)
+	}
+	secret.Data = map[string][]byte{
+		corev1.DockerConfigJsonKey: data,
+	}
+	_, err = secretInterface.Update(secret)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update Secret %s in namespace %s", name, ns)
+	}
+	return nil
+}
+
+// ImagePullFailure returns a description of why a container image of the pod cannot be pulled or a blank string if
+// the images are not failing to pull
+func ImagePullFailure(pod *corev1.Pod) string {
+	statuses := append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...)
+	statuses = append(statuses, pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		waiting := s.State.Waiting
+		if waiting != nil && imagePullFailureReasons[waiting.Reason] {
+			return fmt.Sprintf("container %s cannot pull image %s: %s %s", s.Name, s.Image, waiting.Reason, waiting.Message)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package clienthelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestImagePullSecrets(t *testing.T) {
+	ns := "jx"
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "opaque",
+			Namespace: ns,
+		},
+		Type: corev1.SecretTypeOpaque,
+	})
+
+	err := clienthelpers.VerifyImagePullSecrets(kubeClient, ns, []string{"registry-creds"})
+	require.Error(t, err, "should have failed as the Secret does not exist")
+	assert.Contains(t, err.Error(), "does not exist", "error message")
+
+	err = clienthelpers.VerifyImagePullSecrets(kubeClient, ns, []string{"opaque"})
+	require.Error(t, err, "should have failed as the Secret is not a docker registry Secret")
+
+	err = clienthelpers.CreateImagePullSecret(kubeClient, ns, "registry-creds", "myregistry.example.com", "bot", "s3cret")
+	require.NoError(t, err, "failed to create the image pull Secret")
+	err = clienthelpers.CreateImagePullSecret(kubeClient, ns, "registry-creds", "myregistry.example.com", "bot", "changed")
+	require.NoError(t, err, "failed to update the image pull Secret")
+
+	err = clienthelpers.VerifyImagePullSecrets(kubeClient, ns, []string{"registry-creds"})
+	require.NoError(t, err, "should have verified the image pull Secret")
+
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get("registry-creds", metav1.GetOptions{})
+	require.NoError(t, err, "failed to get the image pull Secret")
+	assert.Equal(t, corev1.SecretTypeDockerConfigJson, secret.Type, "Secret type")
+	assert.JSONEq(t, `{"auths":{"myregistry.example.com":{"username":"bot","password":"changed","auth":"Ym90OmNoYW5nZWQ="}}}`, string(secret.Data[corev1.DockerConfigJsonKey]), "docker config")
+
+	err = clienthelpers.CreateImagePullSecret(kubeClient, ns, "opaque", "myregistry.example.com", "bot", "s3cret")
+	require.Error(t, err, "should not have replaced an opaque Secret")
+}
+
+func TestImagePullFailure(t *testing.T) {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "boot",
+					Image: "myregistry.example.com/boot:1.0.0",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{
+							Reason:  "ImagePullBackOff",
+							Message: "Back-off pulling image",
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, "container boot cannot pull image myregistry.example.com/boot:1.0.0: ImagePullBackOff Back-off pulling image", clienthelpers.ImagePullFailure(pod))
+
+	pod.Status.ContainerStatuses[0].State.Waiting.Reason = "ContainerCreating"
+	assert.Equal(t, "", clienthelpers.ImagePullFailure(pod))
+}
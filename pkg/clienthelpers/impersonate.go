@@ -0,0 +1,144 @@
+package clienthelpers
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/jxfactory"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	tektonclient "github.com/tektoncd/pipeline/pkg/client/clientset/versioned"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const serviceAccountUserPrefix = "system:serviceaccount:"
+
+// impersonatingFactory a factory which impersonates a user and groups for all Kubernetes API calls like 'kubectl --as'
+type impersonatingFactory struct {
+	jxfactory.Factory
+	user   string
+	groups []string
+}
+
+// WithImpersonation returns a factory whose clients impersonate the given user and groups. If neither are specified
+// the factory is returned unchanged
+func WithImpersonation(f jxfactory.Factory, user string, groups []string) jxfactory.Factory {
+	if user == "" && len(groups) == 0 {
+		return f
+	}
+	return &impersonatingFactory{Factory: f, user: user, groups: groups}
+}
+
+// AddImpersonationFlags adds the --as and --as-group flags to the command binding them to the given user and groups
+func AddImpersonationFlags(cmd *cobra.Command, user *string, groups *[]string) {
+	cmd.Flags().StringVarP(user, "as", "", "", "the user to impersonate for all Kubernetes API calls so they are audited as that identity. A service account is of the form system:serviceaccount:namespace:name")
+	cmd.Flags().StringArrayVarP(groups, "as-group", "", nil, "a group to impersonate for all Kubernetes API calls. Requires --as. Can be specified multiple times")
+}
+
+// ValidateImpersonation validates the user and groups to impersonate. A service account user must be of the
+// form system:serviceaccount:namespace:name
+func ValidateImpersonation(user string, groups []string) error {
+	if user == "" {
+		if len(groups) > 0 {
+			return util.InvalidOptionf("as-group", strings.Join(groups, ","), "a user to impersonate must be specified via --as when impersonating groups")
+		}
+		return nil
+	}
+	if strings.TrimSpace(user) != user {
+		return util.InvalidOptionf("as", user, "the user must not have leading or trailing whitespace")
+	}
+	if strings.HasPrefix(user, serviceAccountUserPrefix) {
+		parts := strings.Split(strings.TrimPrefix(user, serviceAccountUserPrefix), ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return util.InvalidOptionf("as", user, "a service account must be of the form %snamespace:name", serviceAccountUserPrefix)
+		}
+	}
+	for _, g := range groups {
+		if strings.TrimSpace(g) == "" || strings.TrimSpace(g) != g {
+			return util.InvalidOptionf("as-group", g, "the group must not be blank or have leading or trailing whitespace")
+		}
+	}
+	return nil
+}
+
+// CreateKubeConfig creates the REST configuration with the impersonation headers
+func (f *impersonatingFactory) CreateKubeConfig() (*rest.Config, error) {
+	err := ValidateImpersonation(f.user, f.groups)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := f.Factory.CreateKubeConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg = rest.CopyConfig(cfg)
+	cfg.Impersonate = rest.ImpersonationConfig{
+		UserName: f.user,
+		Groups:   f.groups,
+	}
+	return cfg, nil
+}
+
+// CreateKubeClient creates the kubernetes client impersonating the user and groups
+func (f *impersonatingFactory) CreateKubeClient() (kubernetes.Interface, string, error) {
+	cfg, ns, err := f.configAndNamespace()
+	if err != nil {
+		return nil, ns, err
+	}
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, ns, errors.Wrap(err, "failed to create the kubernetes client")
+	}
+	return client, ns, nil
+}
+
+// CreateJXClient creates the Jenkins X client impersonating the user and groups
+func (f *impersonatingFactory) CreateJXClient() (versioned.Interface, string, error) {
+	cfg, ns, err := f.configAndNamespace()
+	if err != nil {
+		return nil, ns, err
+	}
+	client, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		return nil, ns, errors.Wrap(err, "failed to create the Jenkins X client")
+	}
+	return client, ns, nil
+}
+
+// CreateTektonClient creates the tekton client impersonating the user and groups
+func (f *impersonatingFactory) CreateTektonClient() (tektonclient.Interface, string, error) {
+	cfg, ns, err := f.configAndNamespace()
+	if err != nil {
+		return nil, ns, err
+	}
+	client, err := tektonclient.NewForConfig(cfg)
+	if err != nil {
+		return nil, ns, errors.Wrap(err, "failed to create the tekton client")
+	}
+	return client, ns, nil
+}
+
+// WithBearerToken preserves the impersonation when using a bearer token
+func (f *impersonatingFactory) WithBearerToken(token string) jxfactory.Factory {
+	return WithImpersonation(f.Factory.WithBearerToken(token), f.user, f.groups)
+}
+
+// ImpersonateUser replaces the impersonated user keeping the groups
+func (f *impersonatingFactory) ImpersonateUser(user string) jxfactory.Factory {
+	return WithImpersonation(f.Factory, user, f.groups)
+}
+
+// configAndNamespace returns the impersonating REST configuration and the current namespace
+func (f *impersonatingFactory) configAndNamespace() (*rest.Config, string, error) {
+	cfg, err := f.CreateKubeConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	_, ns, err := f.Factory.CreateKubeClient()
+	if err != nil {
+		return nil, "", err
+	}
+	return cfg, ns, nil
+}
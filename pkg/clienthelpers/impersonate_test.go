@@ -0,0 +1,57 @@
+package clienthelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/fakes/fakejxfactory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithImpersonation(t *testing.T) {
+	f := fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	assert.Equal(t, f, clienthelpers.WithImpersonation(f, "", nil), "should not wrap the factory without a user or groups")
+
+	imf := clienthelpers.WithImpersonation(f, "jane@example.com", []string{"platform-admins"})
+	cfg, err := imf.CreateKubeConfig()
+	require.NoError(t, err, "failed to create the kube config")
+	assert.Equal(t, "jane@example.com", cfg.Impersonate.UserName, "impersonated user")
+	assert.Equal(t, []string{"platform-admins"}, cfg.Impersonate.Groups, "impersonated groups")
+
+	_, ns, err := imf.CreateKubeClient()
+	require.NoError(t, err, "failed to create the kube client")
+	assert.Equal(t, "jx", ns, "kube client namespace")
+
+	_, ns, err = clienthelpers.WithJXNamespace(imf, "jx-system").CreateJXClient()
+	require.NoError(t, err, "failed to create the JX client")
+	assert.Equal(t, "jx-system", ns, "JX client namespace")
+
+	_, _, err = clienthelpers.WithImpersonation(f, "", []string{"platform-admins"}).CreateKubeClient()
+	assert.Error(t, err, "should fail to create a client for an invalid identity")
+}
+
+func TestValidateImpersonation(t *testing.T) {
+	testCases := []struct {
+		user   string
+		groups []string
+		valid  bool
+	}{
+		{valid: true},
+		{user: "jane@example.com", valid: true},
+		{user: "system:serviceaccount:jx:helmboot", groups: []string{"system:serviceaccounts"}, valid: true},
+		{groups: []string{"platform-admins"}, valid: false},
+		{user: " jane", valid: false},
+		{user: "system:serviceaccount:jx", valid: false},
+		{user: "system:serviceaccount::helmboot", valid: false},
+		{user: "jane", groups: []string{""}, valid: false},
+	}
+	for _, tc := range testCases {
+		err := clienthelpers.ValidateImpersonation(tc.user, tc.groups)
+		if tc.valid {
+			assert.NoError(t, err, "for user %q groups %v", tc.user, tc.groups)
+		} else {
+			assert.Error(t, err, "for user %q groups %v", tc.user, tc.groups)
+		}
+	}
+}
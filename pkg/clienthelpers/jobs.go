@@ -0,0 +1,51 @@
+package clienthelpers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// IsForbidden returns true if the error was caused by the current identity not having the required RBAC permissions
+func IsForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	return apierrors.IsForbidden(errors.Cause(err)) || strings.Contains(strings.ToLower(err.Error()), "forbidden")
+}
+
+// IsJobComplete returns true if the Job has completed successfully
+func IsJobComplete(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// WaitForJobComplete polls the status of the Job until it completes successfully or the verify function returns an
+// error such as when the Job has failed. This only needs permission to get the Job so can be used when the logs of its
+// pods cannot be viewed
+func WaitForJobComplete(client kubernetes.Interface, ns string, name string, pollInterval time.Duration, verify func() error) error {
+	for {
+		job, err := client.BatchV1().Jobs(ns).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to get Job %s in namespace %s", name, ns)
+		}
+		if IsJobComplete(job) {
+			return nil
+		}
+		err = verify()
+		if err != nil {
+			return err
+		}
+		time.Sleep(pollInterval)
+	}
+}
@@ -0,0 +1,75 @@
+package clienthelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestIsForbidden(t *testing.T) {
+	forbidden := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "jx-boot-abc", errors.New("no RBAC"))
+
+	assert.False(t, clienthelpers.IsForbidden(nil), "nil error")
+	assert.True(t, clienthelpers.IsForbidden(forbidden), "forbidden error")
+	assert.True(t, clienthelpers.IsForbidden(errors.Wrap(forbidden, "failed to list pods")), "wrapped forbidden error")
+	assert.True(t, clienthelpers.IsForbidden(errors.New(`pods "jx-boot-abc" is forbidden: cannot get resource "pods/log"`)), "forbidden message")
+	assert.False(t, clienthelpers.IsForbidden(apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "jx-boot-abc")), "not found error")
+}
+
+func TestWaitForJobComplete(t *testing.T) {
+	ns := "jx"
+	newJob := func(conditionType batchv1.JobConditionType) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "jx-boot", Namespace: ns},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Type: conditionType, Status: corev1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	// a complete Job returns without verifying it
+	client := fake.NewSimpleClientset(newJob(batchv1.JobComplete))
+	err := clienthelpers.WaitForJobComplete(client, ns, "jx-boot", 0, func() error {
+		return errors.New("should not have verified a complete Job")
+	})
+	assert.NoError(t, err, "should have waited for the complete Job")
+
+	// a failed Job returns the error of the verify function
+	client = fake.NewSimpleClientset(newJob(batchv1.JobFailed))
+	err = clienthelpers.WaitForJobComplete(client, ns, "jx-boot", 0, func() error {
+		return errors.New("the boot Job has failed")
+	})
+	require.Error(t, err, "should have failed for the failed Job")
+	assert.Equal(t, "the boot Job has failed", err.Error(), "error message")
+
+	// the Job is polled until it completes
+	client = fake.NewSimpleClientset(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "jx-boot", Namespace: ns}})
+	polls := 0
+	err = clienthelpers.WaitForJobComplete(client, ns, "jx-boot", 0, func() error {
+		polls++
+		if polls == 2 {
+			_, err := client.BatchV1().Jobs(ns).Update(newJob(batchv1.JobComplete))
+			return err
+		}
+		return nil
+	})
+	assert.NoError(t, err, "should have waited for the Job to complete")
+	assert.Equal(t, 2, polls, "number of polls before the Job completed")
+
+	client = fake.NewSimpleClientset()
+	err = clienthelpers.WaitForJobComplete(client, ns, "jx-boot", 0, func() error {
+		return nil
+	})
+	assert.Error(t, err, "should have failed without a Job")
+}
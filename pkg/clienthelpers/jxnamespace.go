@@ -0,0 +1,37 @@
+package clienthelpers
+
+import (
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/jxfactory"
+)
+
+// jxNamespaceFactory a factory which uses a fixed namespace for the Jenkins X resources such as the dev Environment
+type jxNamespaceFactory struct {
+	jxfactory.Factory
+	namespace string
+}
+
+// WithJXNamespace returns a factory which uses the given namespace for the Jenkins X client while the kubernetes
+// client keeps using the current namespace. If the namespace is blank the factory is returned unchanged
+func WithJXNamespace(f jxfactory.Factory, ns string) jxfactory.Factory {
+	if ns == "" {
+		return f
+	}
+	return &jxNamespaceFactory{Factory: f, namespace: ns}
+}
+
+// CreateJXClient creates the Jenkins X client using the fixed namespace
+func (f *jxNamespaceFactory) CreateJXClient() (versioned.Interface, string, error) {
+	jxClient, _, err := f.Factory.CreateJXClient()
+	return jxClient, f.namespace, err
+}
+
+// WithBearerToken preserves the fixed namespace when using a bearer token
+func (f *jxNamespaceFactory) WithBearerToken(token string) jxfactory.Factory {
+	return WithJXNamespace(f.Factory.WithBearerToken(token), f.namespace)
+}
+
+// ImpersonateUser preserves the fixed namespace when impersonating a user
+func (f *jxNamespaceFactory) ImpersonateUser(user string) jxfactory.Factory {
+	return WithJXNamespace(f.Factory.ImpersonateUser(user), f.namespace)
+}
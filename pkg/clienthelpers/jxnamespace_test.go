@@ -0,0 +1,28 @@
+package clienthelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/fakes/fakejxfactory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithJXNamespace(t *testing.T) {
+	f := fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "myapps")
+	assert.Equal(t, f, clienthelpers.WithJXNamespace(f, ""), "should not wrap the factory without a namespace")
+
+	jxf := clienthelpers.WithJXNamespace(f, "jx")
+	_, ns, err := jxf.CreateJXClient()
+	require.NoError(t, err, "failed to create the JX client")
+	assert.Equal(t, "jx", ns, "JX client namespace")
+
+	_, ns, err = jxf.CreateKubeClient()
+	require.NoError(t, err, "failed to create the kube client")
+	assert.Equal(t, "myapps", ns, "kube client namespace")
+
+	_, ns, err = jxf.ImpersonateUser("someone").CreateJXClient()
+	require.NoError(t, err, "failed to create the impersonated JX client")
+	assert.Equal(t, "jx", ns, "impersonated JX client namespace")
+}
@@ -0,0 +1,189 @@
+package clienthelpers
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	runLogFilePrefix = "boot-"
+	runLogFileSuffix = ".log"
+)
+
+// FollowLogOptions returns the options to follow the logs of the container starting from the given duration ago.
+// If the duration is zero the logs are followed from the start of the container
+func FollowLogOptions(containerName string, since time.Duration) *corev1.PodLogOptions {
+	opts := &corev1.PodLogOptions{
+		Container: containerName,
+		Follow:    true,
+	}
+	if since > 0 {
+		seconds := int64(math.Ceil(since.Seconds()))
+		opts.SinceSeconds = &seconds
+	}
+	return opts
+}
+
+// FollowPodLogs streams the logs of the container to the writer starting from the given duration ago until the
+// container terminates
+func FollowPodLogs(podInterface typedcorev1.PodInterface, pod string, containerName string, since time.Duration, out io.Writer) error {
+	stream, err := podInterface.GetLogs(pod, FollowLogOptions(containerName, since)).Stream()
+	if err != nil {
+		return errors.Wrapf(err, "failed to stream the logs of pod %s", pod)
+	}
+	defer stream.Close()
+
+	_, err = io.Copy(out, stream)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read the logs of pod %s", pod)
+	}
+	return nil
+}
+
+// RunLogFileName returns the name of the log file in the directory for the boot run with the given ID
+func RunLogFileName(dir string, runID string) string {
+	return filepath.Join(dir, fmt.Sprintf("%s%s%s", runLogFilePrefix, runID, runLogFileSuffix))
+}
+
+// RunLogIDs returns the sorted IDs of the boot runs which have a log file in the directory
+func RunLogIDs(dir string) ([]string, error) {
+	fileNames, err := filepath.Glob(filepath.Join(dir, runLogFilePrefix+"*"+runLogFileSuffix))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find the log files in %s", dir)
+	}
+	var answer []string
+	for _, fileName := range fileNames {
+		name := filepath.Base(fileName)
+		answer = append(answer, strings.TrimSuffix(strings.TrimPrefix(name, runLogFilePrefix), runLogFileSuffix))
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+// RunLogBucketKey returns the key in the logs bucket of the logs of the boot run with the given ID
+func RunLogBucketKey(clusterName string, runID string) string {
+	return fmt.Sprintf("%s/%s/boot.log", clusterName, runID)
+}
+
+// ValidateLogsBucket returns an error if the --logs-bucket URL is not a Google Cloud Storage or S3 bucket URL
+func ValidateLogsBucket(bucketURL string) error {
+	if bucketURL != "" && !strings.HasPrefix(bucketURL, "gs://") && !strings.HasPrefix(bucketURL, "s3://") {
+		return util.InvalidOptionf("logs-bucket", bucketURL, "the bucket URL must start with gs:// or s3://")
+	}
+	return nil
+}
+
+// bucketNotFoundMessages the error messages of the gocloud blob buckets used by jx when an object does not exist
+var bucketNotFoundMessages = []string{"code=NotFound", "NoSuchKey", "object doesn't exist", "storage: object not exist"}
+
+// IsBucketNotFound returns true if the error from reading a bucket URL is because the object does not exist rather
+// than a failure to access the bucket
+func IsBucketNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	message := err.Error()
+	for _, text := range bucketNotFoundMessages {
+		if strings.Contains(message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnknownRunIDError returns the error for a boot run whose logs could not be found listing the known run IDs
+func UnknownRunIDError(runID string, knownRunIDs []string) error {
+	if len(knownRunIDs) == 0 {
+		return errors.Errorf("no logs could be found for the boot run %s and there are no known boot runs", runID)
+	}
+	return errors.Errorf("no logs could be found for the boot run %s. The known boot runs are: %s", runID, strings.Join(knownRunIDs, ", "))
+}
+
+// LogSeparator returns the marker written to a log file each time the logs of a pod are attached to
+func LogSeparator(pod string, t time.Time) string {
+	return fmt.Sprintf("\n===== attached to the logs of pod %s at %s =====\n", pod, t.UTC().Format(time.RFC3339))
+}
+
+// OpenRunLogFile opens the log file of a boot run for appending creating it and its directory if required. A
+// separator naming the pod and time is written first so that reattaching to a new pod continues the same file
+func OpenRunLogFile(fileName string, pod string, t time.Time) (*os.File, error) {
+	dir := filepath.Dir(fileName)
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the log directory %s", dir)
+	}
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, util.DefaultFileWritePermissions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open the log file %s", fileName)
+	}
+	_, err = f.WriteString(LogSeparator(pod, t))
+	if err != nil {
+		f.Close()
+		return nil, errors.Wrapf(err, "failed to write to the log file %s", fileName)
+	}
+	return f, nil
+}
+
+// LogLimitMarker returns the marker written once a log file reaches its maximum size
+func LogLimitMarker(maxBytes int64) string {
+	return fmt.Sprintf("\n... truncated the log output as it reached the limit of %d bytes ...\n", maxBytes)
+}
+
+// limitWriter writes up to a maximum number of bytes followed by a marker and discards the rest
+type limitWriter struct {
+	out       io.Writer
+	maxBytes  int64
+	remaining int64
+	truncated bool
+}
+
+// NewLimitWriter returns a writer which writes at most maxBytes in total to the output including the bytes already
+// written, then writes a marker and discards any further output. Discarded output is still reported as written so
+// that an io.MultiWriter keeps writing to its other writers. A limit of zero means no limit
+func NewLimitWriter(out io.Writer, maxBytes int64, written int64) io.Writer {
+	if maxBytes <= 0 {
+		return out
+	}
+	return &limitWriter{
+		out:       out,
+		maxBytes:  maxBytes,
+		remaining: maxBytes - written,
+		truncated: written >= maxBytes,
+	}
+}
+
+func (w *limitWriter) Write(p []byte) (int, error) {
+	if w.truncated {
+		return len(p), nil
+	}
+	data := p
+	if int64(len(data)) > w.remaining {
+		data = data[:w.remaining]
+	}
+	if len(data) > 0 {
+		_, err := w.out.Write(data)
+		if err != nil {
+			return 0, err
+		}
+		w.remaining -= int64(len(data))
+	}
+	if len(data) < len(p) {
+		w.truncated = true
+		_, err := io.WriteString(w.out, LogLimitMarker(w.maxBytes))
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
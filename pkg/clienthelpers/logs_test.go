@@ -0,0 +1,118 @@
+package clienthelpers_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFollowLogOptions(t *testing.T) {
+	opts := clienthelpers.FollowLogOptions("boot", 0)
+	assert.Equal(t, "boot", opts.Container, "container")
+	assert.True(t, opts.Follow, "should follow the logs")
+	assert.Nil(t, opts.SinceSeconds, "should stream from the start without a since duration")
+
+	opts = clienthelpers.FollowLogOptions("boot", 10*time.Minute)
+	require.NotNil(t, opts.SinceSeconds, "since seconds")
+	assert.Equal(t, int64(600), *opts.SinceSeconds, "since seconds")
+
+	opts = clienthelpers.FollowLogOptions("boot", 1500*time.Millisecond)
+	require.NotNil(t, opts.SinceSeconds, "since seconds")
+	assert.Equal(t, int64(2), *opts.SinceSeconds, "should round up to whole seconds")
+}
+
+func TestOpenRunLogFile(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "helmboot-logs-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(tmpDir)
+
+	fileName := clienthelpers.RunLogFileName(filepath.Join(tmpDir, "logs"), "20200101-120000")
+	assert.Equal(t, filepath.Join(tmpDir, "logs", "boot-20200101-120000.log"), fileName, "log file name")
+
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i, pod := range []string{"jx-boot-abc", "jx-boot-def"} {
+		f, err := clienthelpers.OpenRunLogFile(fileName, pod, start.Add(time.Duration(i)*time.Minute))
+		require.NoError(t, err, "failed to open the log file for pod %s", pod)
+		_, err = f.WriteString("logs of " + pod + "\n")
+		require.NoError(t, err, "failed to write the logs of pod %s", pod)
+		require.NoError(t, f.Close(), "failed to close the log file")
+	}
+
+	data, err := ioutil.ReadFile(fileName)
+	require.NoError(t, err, "failed to read the log file")
+	expected := clienthelpers.LogSeparator("jx-boot-abc", start) + "logs of jx-boot-abc\n" +
+		clienthelpers.LogSeparator("jx-boot-def", start.Add(time.Minute)) + "logs of jx-boot-def\n"
+	assert.Equal(t, expected, string(data), "should have appended the logs of each pod")
+	assert.Contains(t, string(data), "===== attached to the logs of pod jx-boot-def at 2020-01-01T12:01:00Z =====", "separator")
+
+	f, err := clienthelpers.OpenRunLogFile(clienthelpers.RunLogFileName(filepath.Join(tmpDir, "logs"), "20191231-090000"), "jx-boot-xyz", start)
+	require.NoError(t, err, "failed to open the log file of another run")
+	require.NoError(t, f.Close(), "failed to close the log file")
+	runIDs, err := clienthelpers.RunLogIDs(filepath.Join(tmpDir, "logs"))
+	require.NoError(t, err, "failed to find the run IDs")
+	assert.Equal(t, []string{"20191231-090000", "20200101-120000"}, runIDs, "run IDs")
+}
+
+func TestUnknownRunIDError(t *testing.T) {
+	assert.Equal(t, "mycluster/20200101-120000/boot.log", clienthelpers.RunLogBucketKey("mycluster", "20200101-120000"), "bucket key")
+
+	err := clienthelpers.UnknownRunIDError("20200101-120000", []string{"20191231-090000", "20200102-080000"})
+	assert.EqualError(t, err, "no logs could be found for the boot run 20200101-120000. The known boot runs are: 20191231-090000, 20200102-080000")
+
+	err = clienthelpers.UnknownRunIDError("20200101-120000", nil)
+	assert.EqualError(t, err, "no logs could be found for the boot run 20200101-120000 and there are no known boot runs")
+}
+
+func TestValidateLogsBucket(t *testing.T) {
+	for _, bucketURL := range []string{"", "gs://mybucket", "s3://mybucket/boot-logs"} {
+		assert.NoError(t, clienthelpers.ValidateLogsBucket(bucketURL), "bucket URL %s", bucketURL)
+	}
+	for _, bucketURL := range []string{"mybucket", "https://storage.googleapis.com/mybucket", "file:///tmp/logs"} {
+		err := clienthelpers.ValidateLogsBucket(bucketURL)
+		require.Error(t, err, "should have rejected the bucket URL %s", bucketURL)
+		assert.Contains(t, err.Error(), "logs-bucket", "error message for %s", bucketURL)
+	}
+}
+
+func TestIsBucketNotFound(t *testing.T) {
+	assert.False(t, clienthelpers.IsBucketNotFound(nil), "nil error")
+	assert.True(t, clienthelpers.IsBucketNotFound(errors.New(`blob (key "mycluster/20200101-120000/boot.log") (code=NotFound): storage: object doesn't exist`)), "missing GCS object")
+	assert.True(t, clienthelpers.IsBucketNotFound(errors.New("NoSuchKey: The specified key does not exist")), "missing S3 object")
+	assert.False(t, clienthelpers.IsBucketNotFound(errors.New("blob (code=PermissionDenied): googleapi: Error 403: access denied")), "access denied")
+}
+
+func TestLimitWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	console := &bytes.Buffer{}
+	w := io.MultiWriter(console, clienthelpers.NewLimitWriter(buf, 10, 4))
+	for _, text := range []string{"abc", "defgh", "ijk"} {
+		n, err := w.Write([]byte(text))
+		require.NoError(t, err, "failed to write %s", text)
+		assert.Equal(t, len(text), n, "bytes written for %s", text)
+	}
+	assert.Equal(t, "abcdefghijk", console.String(), "the other writers should get all the output")
+	assert.Equal(t, "abcdef"+clienthelpers.LogLimitMarker(10), buf.String(), "the output should be truncated with a marker")
+
+	// a file which already reached the limit is not written to again
+	buf.Reset()
+	w = clienthelpers.NewLimitWriter(buf, 10, 12)
+	_, err := w.Write([]byte("more"))
+	require.NoError(t, err, "failed to write")
+	assert.Equal(t, "", buf.String(), "should discard output once the limit is reached")
+
+	// no limit
+	buf.Reset()
+	w = clienthelpers.NewLimitWriter(buf, 0, 100)
+	_, err = w.Write([]byte("everything"))
+	require.NoError(t, err, "failed to write")
+	assert.Equal(t, "everything", buf.String(), "should not limit the output")
+}
@@ -0,0 +1,22 @@
+package clienthelpers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NewestPod returns the most recently created pod whatever its phase or nil if there are no pods
+func NewestPod(pods []corev1.Pod) *corev1.Pod {
+	var answer *corev1.Pod
+	for i := range pods {
+		pod := &pods[i]
+		if answer == nil || answer.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			answer = pod
+		}
+	}
+	return answer
+}
+
+// IsPodStarting returns true if the pod has not started running its containers yet
+func IsPodStarting(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodPending || pod.Status.Phase == corev1.PodUnknown
+}
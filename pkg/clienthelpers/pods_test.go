@@ -0,0 +1,41 @@
+package clienthelpers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewestPod(t *testing.T) {
+	assert.Nil(t, clienthelpers.NewestPod(nil), "should not find a pod in an empty list")
+
+	now := time.Now()
+	newPod := func(name string, age time.Duration, phase corev1.PodPhase) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	// the retried pod is still pending so the old failed pod must not be chosen
+	pods := []corev1.Pod{
+		newPod("jx-boot-old", 10*time.Minute, corev1.PodFailed),
+		newPod("jx-boot-new", time.Minute, corev1.PodPending),
+		newPod("jx-boot-older", 20*time.Minute, corev1.PodSucceeded),
+	}
+	pod := clienthelpers.NewestPod(pods)
+	require.NotNil(t, pod, "should find a pod")
+	assert.Equal(t, "jx-boot-new", pod.Name, "newest pod")
+	assert.True(t, clienthelpers.IsPodStarting(pod), "the newest pod should be starting")
+
+	pods[1].Status.Phase = corev1.PodRunning
+	assert.False(t, clienthelpers.IsPodStarting(clienthelpers.NewestPod(pods)), "the newest pod should be running")
+}
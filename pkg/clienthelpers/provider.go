@@ -0,0 +1,56 @@
+package clienthelpers
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/cloud"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// providerIDPrefixes the prefixes of the node provider IDs of each cloud provider
+var providerIDPrefixes = map[string]string{
+	"gce://":   cloud.GKE,
+	"aws://":   cloud.EKS,
+	"azure://": cloud.AKS,
+}
+
+// providerNodeLabels the node labels which are specific to each cloud provider
+var providerNodeLabels = map[string]string{
+	"cloud.google.com/gke-nodepool": cloud.GKE,
+	"eks.amazonaws.com/nodegroup":   cloud.EKS,
+	"kubernetes.azure.com/cluster":  cloud.AKS,
+	"minikube.k8s.io/name":          cloud.MINIKUBE,
+}
+
+// DetectProvider detects the cloud provider of the cluster from the provider ID and labels of its nodes
+// returning a blank string if it could not be detected
+func DetectProvider(kubeClient kubernetes.Interface) (string, error) {
+	nodes, err := kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list nodes")
+	}
+	for i := range nodes.Items {
+		provider := nodeProvider(&nodes.Items[i])
+		if provider != "" {
+			return provider, nil
+		}
+	}
+	return "", nil
+}
+
+func nodeProvider(node *corev1.Node) string {
+	for prefix, provider := range providerIDPrefixes {
+		if strings.HasPrefix(node.Spec.ProviderID, prefix) {
+			return provider
+		}
+	}
+	for label, provider := range providerNodeLabels {
+		if _, ok := node.Labels[label]; ok {
+			return provider
+		}
+	}
+	return ""
+}
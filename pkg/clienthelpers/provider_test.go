@@ -0,0 +1,44 @@
+package clienthelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x/jx/pkg/cloud"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestDetectProvider(t *testing.T) {
+	testCases := []struct {
+		node     corev1.Node
+		expected string
+	}{
+		{
+			node:     corev1.Node{Spec: corev1.NodeSpec{ProviderID: "gce://myproject/europe-west1-b/gke-mycluster-default-pool-1234"}},
+			expected: cloud.GKE,
+		},
+		{
+			node:     corev1.Node{Spec: corev1.NodeSpec{ProviderID: "aws:///us-east-1a/i-0123456789"}},
+			expected: cloud.EKS,
+		},
+		{
+			node:     corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"kubernetes.azure.com/cluster": "mycluster"}}},
+			expected: cloud.AKS,
+		},
+		{
+			node:     corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"kubernetes.io/hostname": "mynode"}}},
+			expected: "",
+		},
+	}
+	for _, tc := range testCases {
+		tc.node.Name = "mynode"
+		kubeClient := fake.NewSimpleClientset(&tc.node)
+		actual, err := clienthelpers.DetectProvider(kubeClient)
+		require.NoError(t, err, "failed to detect provider")
+		assert.Equal(t, tc.expected, actual, "provider for node %#v", tc.node)
+	}
+}
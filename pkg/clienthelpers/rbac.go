@@ -0,0 +1,56 @@
+package clienthelpers
+
+import (
+	"github.com/pkg/errors"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ResourcePermission a verb on a kind of resource which we need permission to perform
+type ResourcePermission struct {
+	Verb     string
+	Group    string
+	Resource string
+}
+
+// String returns a description of the permission
+func (p ResourcePermission) String() string {
+	if p.Group == "" {
+		return p.Verb + " " + p.Resource
+	}
+	return p.Verb + " " + p.Resource + "." + p.Group
+}
+
+// CanI returns true if the current identity is allowed the given permission in the namespace
+func CanI(kubeClient kubernetes.Interface, ns string, permission ResourcePermission) (bool, error) {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: ns,
+				Verb:      permission.Verb,
+				Group:     permission.Group,
+				Resource:  permission.Resource,
+			},
+		},
+	}
+	result, err := kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(review)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to check if we can %s in namespace %s", permission.String(), ns)
+	}
+	return result.Status.Allowed, nil
+}
+
+// MissingPermissions returns the permissions the current identity is not allowed in the namespace
+func MissingPermissions(kubeClient kubernetes.Interface, ns string, permissions []ResourcePermission) ([]ResourcePermission, error) {
+	var answer []ResourcePermission
+	for _, p := range permissions {
+		allowed, err := CanI(kubeClient, ns, p)
+		if err != nil {
+			return answer, err
+		}
+		if !allowed {
+			answer = append(answer, p)
+		}
+	}
+	return answer, nil
+}
@@ -0,0 +1,32 @@
+package clienthelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestMissingPermissions(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource != "secrets"
+		return true, review, nil
+	})
+
+	permissions := []clienthelpers.ResourcePermission{
+		{Verb: "create", Group: "batch", Resource: "jobs"},
+		{Verb: "get", Resource: "secrets"},
+	}
+	missing, err := clienthelpers.MissingPermissions(kubeClient, "jx", permissions)
+	require.NoError(t, err, "failed to check permissions")
+	require.Len(t, missing, 1, "missing permissions")
+	assert.Equal(t, "get secrets", missing[0].String(), "missing permission")
+	assert.Equal(t, "create jobs.batch", permissions[0].String(), "permission description")
+}
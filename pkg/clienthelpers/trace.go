@@ -0,0 +1,111 @@
+package clienthelpers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/pkg/jxfactory"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// tracingFactory a factory whose Kubernetes and Jenkins X clients write each API call to the trace
+type tracingFactory struct {
+	jxfactory.Factory
+}
+
+// tracingRoundTripper writes each request and its outcome to the trace
+type tracingRoundTripper struct {
+	next http.RoundTripper
+}
+
+// WithTracing returns a factory whose clients write each API call to the trace. If tracing is not enabled the factory
+// is returned unchanged
+func WithTracing(f jxfactory.Factory) jxfactory.Factory {
+	if !common.TraceEnabled() {
+		return f
+	}
+	return &tracingFactory{Factory: f}
+}
+
+// NewTracingRoundTripper returns a round tripper which writes each request and its outcome to the trace
+func NewTracingRoundTripper(next http.RoundTripper) http.RoundTripper {
+	return &tracingRoundTripper{next: next}
+}
+
+// RoundTrip performs the request writing it to the trace
+func (t *tracingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		common.Tracef(common.TraceAPI, "%s %s failed after %s: %s", req.Method, req.URL.String(), duration.String(), err.Error())
+		return resp, err
+	}
+	common.Tracef(common.TraceAPI, "%s %s returned %d in %s", req.Method, req.URL.String(), resp.StatusCode, duration.String())
+	return resp, nil
+}
+
+// CreateKubeConfig creates the REST configuration which traces each API call
+func (f *tracingFactory) CreateKubeConfig() (*rest.Config, error) {
+	cfg, err := f.Factory.CreateKubeConfig()
+	if err != nil {
+		return nil, err
+	}
+	cfg = rest.CopyConfig(cfg)
+	wrap := cfg.WrapTransport
+	cfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if wrap != nil {
+			rt = wrap(rt)
+		}
+		return NewTracingRoundTripper(rt)
+	}
+	return cfg, nil
+}
+
+// CreateKubeClient creates the kubernetes client which traces each API call
+func (f *tracingFactory) CreateKubeClient() (kubernetes.Interface, string, error) {
+	cfg, err := f.CreateKubeConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	_, ns, err := f.Factory.CreateKubeClient()
+	if err != nil {
+		return nil, ns, err
+	}
+	client, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, ns, errors.Wrap(err, "failed to create the kubernetes client")
+	}
+	return client, ns, nil
+}
+
+// CreateJXClient creates the Jenkins X client which traces each API call
+func (f *tracingFactory) CreateJXClient() (versioned.Interface, string, error) {
+	cfg, err := f.CreateKubeConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	_, ns, err := f.Factory.CreateJXClient()
+	if err != nil {
+		return nil, ns, err
+	}
+	client, err := versioned.NewForConfig(cfg)
+	if err != nil {
+		return nil, ns, errors.Wrap(err, "failed to create the Jenkins X client")
+	}
+	return client, ns, nil
+}
+
+// WithBearerToken preserves the tracing when using a bearer token
+func (f *tracingFactory) WithBearerToken(token string) jxfactory.Factory {
+	return WithTracing(f.Factory.WithBearerToken(token))
+}
+
+// ImpersonateUser preserves the tracing when impersonating a user
+func (f *tracingFactory) ImpersonateUser(user string) jxfactory.Factory {
+	return WithTracing(f.Factory.ImpersonateUser(user))
+}
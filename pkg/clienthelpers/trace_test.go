@@ -0,0 +1,46 @@
+package clienthelpers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/fakes/fakejxfactory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingRoundTripper(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "test-trace-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(tmpDir)
+	fileName := filepath.Join(tmpDir, "trace.jsonl")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	f := fakejxfactory.NewFakeFactory()
+	assert.Equal(t, f, clienthelpers.WithTracing(f), "should not wrap the factory when not tracing")
+
+	stopTrace, err := common.StartTrace(fileName)
+	require.NoError(t, err, "failed to start the trace")
+	assert.NotEqual(t, f, clienthelpers.WithTracing(f), "should wrap the factory when tracing")
+
+	client := &http.Client{Transport: clienthelpers.NewTracingRoundTripper(http.DefaultTransport)}
+	resp, err := client.Get(server.URL + "/api/v1/namespaces/jx/secrets/jx-boot-secrets")
+	require.NoError(t, err, "failed to make request")
+	resp.Body.Close()
+	stopTrace()
+
+	data, err := ioutil.ReadFile(fileName)
+	require.NoError(t, err, "failed to read the trace file %s", fileName)
+	assert.Contains(t, string(data), `"category":"api"`, "trace category")
+	assert.Contains(t, string(data), "GET "+server.URL+"/api/v1/namespaces/jx/secrets/jx-boot-secrets returned 404", "trace message")
+}
@@ -0,0 +1,122 @@
+package completion
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x/jx/pkg/cloud"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	completionLong = templates.LongDesc(`
+		Outputs the shell completion script for the given shell.
+
+		Completion of the values of flags such as the secret manager kind and provider is supported for bash.
+`)
+
+	completionExample = templates.Examples(`
+		# load the bash completion in the current shell
+		source <(%s completion bash)
+
+		# load the zsh completion in the current shell
+		source <(%s completion zsh)
+	`)
+
+	// ShellValues the shells we can generate completion scripts for
+	ShellValues = []string{"bash", "zsh", "powershell"}
+)
+
+// NewCmdCompletion creates the new command
+func NewCmdCompletion() *cobra.Command {
+	command := &cobra.Command{
+		Use:       "completion [bash|zsh|powershell]",
+		Short:     "Outputs the shell completion script for the given shell",
+		Long:      completionLong,
+		Example:   fmt.Sprintf(completionExample, common.BinaryName, common.BinaryName),
+		ValidArgs: ShellValues,
+		Run: func(command *cobra.Command, args []string) {
+			err := Run(command.Root(), args)
+			helper.CheckErr(err)
+		},
+	}
+	return command
+}
+
+// Run writes the completion script for the shell in the arguments to stdout
+func Run(root *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing argument: the shell. Possible values: %s", strings.Join(ShellValues, ", "))
+	}
+	if len(args) > 1 {
+		return fmt.Errorf("too many arguments. Expected only the shell")
+	}
+	shell := args[0]
+	switch shell {
+	case "bash":
+		AddFlagValueCompletions(root)
+		return root.GenBashCompletion(os.Stdout)
+	case "zsh":
+		return root.GenZshCompletion(os.Stdout)
+	case "powershell":
+		return root.GenPowerShellCompletion(os.Stdout)
+	case "fish":
+		return fmt.Errorf("fish completion is not supported yet. Possible values: %s", strings.Join(ShellValues, ", "))
+	default:
+		return util.InvalidArg(shell, ShellValues)
+	}
+}
+
+// flagValueCompletions the bash completion function for the values of well known flags
+var flagValueCompletions = []struct {
+	Flag     string
+	Function string
+	Values   []string
+}{
+	{Flag: "kind", Function: "__helmboot_secret_kinds", Values: secretmgr.KindValues},
+	{Flag: "to", Function: "__helmboot_secret_kinds", Values: secretmgr.KindValues},
+	{Flag: "provider", Function: "__helmboot_providers", Values: cloud.KubernetesProviders},
+	{Flag: "secret", Function: "__helmboot_secret_storage", Values: config.SecretStorageTypeValues},
+}
+
+// AddFlagValueCompletions adds bash completion of the values of the well known flags on all the commands
+func AddFlagValueCompletions(root *cobra.Command) {
+	functions := map[string]string{}
+	for _, c := range flagValueCompletions {
+		functions[c.Function] = fmt.Sprintf(`
+%s()
+{
+    COMPREPLY=( $( compgen -W "%s" -- "$cur" ) )
+}
+`, c.Function, strings.Join(c.Values, " "))
+	}
+	for _, c := range flagValueCompletions {
+		if !strings.Contains(root.BashCompletionFunction, c.Function+"()") {
+			root.BashCompletionFunction += functions[c.Function]
+		}
+	}
+	markFlagValues(root)
+}
+
+// markFlagValues marks the well known flags of the command and its children to use the completion functions
+func markFlagValues(cmd *cobra.Command) {
+	for _, c := range flagValueCompletions {
+		if cmd.Flags().Lookup(c.Flag) != nil {
+			err := cmd.MarkFlagCustom(c.Flag, c.Function)
+			if err != nil {
+				log.Logger().Debugf("failed to add completion for flag %s of command %s: %s", c.Flag, cmd.Name(), err.Error())
+			}
+		}
+	}
+	for _, child := range cmd.Commands() {
+		markFlagValues(child)
+	}
+}
@@ -0,0 +1,36 @@
+package completion_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/cmd/completion"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddFlagValueCompletions(t *testing.T) {
+	root := &cobra.Command{Use: "helmboot"}
+	child := &cobra.Command{Use: "import", Run: func(*cobra.Command, []string) {}}
+	child.Flags().String("kind", "", "the secret manager kind")
+	root.AddCommand(child)
+
+	completion.AddFlagValueCompletions(root)
+	completion.AddFlagValueCompletions(root)
+
+	assert.Equal(t, []string{"__helmboot_secret_kinds"}, child.Flags().Lookup("kind").Annotations[cobra.BashCompCustom], "kind flag annotation")
+
+	buf := &bytes.Buffer{}
+	err := root.GenBashCompletion(buf)
+	require.NoError(t, err, "failed to generate bash completion")
+	script := buf.String()
+	assert.Contains(t, script, "__helmboot_secret_kinds()", "should define the secret kind completion function")
+	assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("__helmboot_providers()")), "should only define the providers function once")
+}
+
+func TestCompletionInvalidShell(t *testing.T) {
+	root := &cobra.Command{Use: "helmboot"}
+	assert.Error(t, completion.Run(root, nil), "should fail without a shell")
+	assert.Error(t, completion.Run(root, []string{"tcsh"}), "should fail for an unknown shell")
+}
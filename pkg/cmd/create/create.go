@@ -130,7 +130,7 @@ func (o *CreateOptions) gitCloneIfRequired(gitter gits.Gitter) (string, error) {
 
 	log.Logger().Debugf("cloning %s to directory %s", util.ColorInfo(gitURL), util.ColorInfo(dir))
 
-	err = gitter.Clone(gitURL, dir)
+	err = githelpers.CloneWithProgress(gitter, gitURL, dir)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to clone repository %s to directory: %s", gitURL, dir)
 	}
@@ -30,6 +30,7 @@ type Options struct {
 	CreateHelmfileOptions helmfile.CreateHelmfileOptions
 	KindResolver          factory.KindResolver
 	Gitter                gits.Gitter
+	Uninstall             common.UninstallOptions
 	Dir                   string
 	BatchMode             bool
 }
@@ -63,7 +64,9 @@ func NewCmdDestroy() *cobra.Command {
 		},
 	}
 	command.Flags().StringVarP(&options.KindResolver.GitURL, "git-url", "u", "", "override the Git clone URL for the JX Boot source to start from, ignoring the versions stream. Normally specified with git-ref as well")
+	options.KindResolver.AddImpersonationFlags(command)
 	command.Flags().BoolVarP(&options.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input")
+	options.Uninstall.AddFlags(command)
 
 	return command
 }
@@ -75,9 +78,12 @@ func (o *Options) Run() error {
 		o.CreateHelmfileOptions.CommonOptions = opts.NewCommonOptionsWithTerm(f, os.Stdin, os.Stdout, os.Stderr)
 		o.CreateHelmfileOptions.CommonOptions.BatchMode = o.BatchMode
 	}
+	err := o.Uninstall.Validate()
+	if err != nil {
+		return err
+	}
 	gitURL := o.KindResolver.GitURL
 	if gitURL == "" {
-		var err error
 		gitURL, err = o.KindResolver.LoadBootRunGitURLFromSecret()
 		if err != nil {
 			return errors.Wrap(err, "failed to find Git URL")
@@ -129,9 +135,9 @@ func (o *Options) Run() error {
 		return err
 	}
 
-	err = o.runCommand(".", env, "helm", "delete", "jx-boot")
+	err = o.Uninstall.Uninstall(".", env, o.Uninstall.ReleaseName)
 	if err != nil {
-		log.Logger().Debugf("failed to remove the jx-boot chart: %s", err.Error())
+		return err
 	}
 
 	err = o.removeSecrets(secretmgr.BootGitURLSecret, secretmgr.LocalSecret)
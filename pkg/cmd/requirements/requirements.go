@@ -0,0 +1,24 @@
+package requirements
+
+import (
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/spf13/cobra"
+)
+
+// NewCmdRequirements creates the new command
+func NewCmdRequirements() *cobra.Command {
+	command := &cobra.Command{
+		Use:     "requirements",
+		Short:   "commands for working with the jx-requirements.yml file",
+		Aliases: []string{"req", "requirement"},
+		Run: func(command *cobra.Command, args []string) {
+			err := command.Help()
+			if err != nil {
+				log.Logger().Errorf(err.Error())
+			}
+		},
+	}
+	command.AddCommand(common.SplitCommand(NewCmdInit()))
+	return command
+}
@@ -0,0 +1,195 @@
+package requirements
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x/jx/pkg/cloud"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initLong = templates.LongDesc(`
+		Creates a new jx-requirements.yml file by prompting for the main values such as the cluster name and provider
+`)
+
+	initExample = templates.Examples(`
+		# creates a jx-requirements.yml file in the current directory prompting for the values
+		%s requirements init
+
+		# creates a jx-requirements.yml file without prompting
+		%s requirements init --batch-mode --cluster mycluster --provider gke --project myproject --zone europe-west1-b --env-git-owner myorg
+	`)
+)
+
+// InitOptions the options for creating a requirements file
+type InitOptions struct {
+	Dir           string
+	ClusterName   string
+	Provider      string
+	ProjectID     string
+	Region        string
+	Zone          string
+	GitOwner      string
+	SecretStorage string
+	Overwrite     bool
+	BatchMode     bool
+	IOFileHandles *util.IOFileHandles
+}
+
+// NewCmdInit creates a command object for the command
+func NewCmdInit() (*cobra.Command, *InitOptions) {
+	o := &InitOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "init",
+		Short:   "Creates a new jx-requirements.yml file",
+		Long:    initLong,
+		Example: fmt.Sprintf(initExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "", ".", "the directory to create the jx-requirements.yml file in")
+	cmd.Flags().StringVarP(&o.ClusterName, "cluster", "c", "", "configures the cluster name")
+	cmd.Flags().StringVarP(&o.Provider, "provider", "p", "", "configures the kubernetes provider.  Supported providers: "+cloud.KubernetesProviderOptions())
+	cmd.Flags().StringVarP(&o.ProjectID, "project", "", "", "configures the Google Project ID")
+	cmd.Flags().StringVarP(&o.Region, "region", "r", "", "configures the cloud region")
+	cmd.Flags().StringVarP(&o.Zone, "zone", "z", "", "configures the cloud zone")
+	cmd.Flags().StringVarP(&o.GitOwner, "env-git-owner", "", "", "the git owner (organisation or user) used to own the git repositories for the environments")
+	cmd.Flags().StringVarP(&o.SecretStorage, "secret", "", "", "configures the secret storage kind. Possible values: "+strings.Join(config.SecretStorageTypeValues, ", "))
+	cmd.Flags().BoolVarP(&o.Overwrite, "overwrite", "", false, "overwrites any existing jx-requirements.yml file")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Enables batch mode which avoids prompting for user input")
+	return cmd, o
+}
+
+// Run implements the command
+func (o *InitOptions) Run() error {
+	fileName := filepath.Join(o.Dir, config.RequirementsConfigFileName)
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if file exists %s", fileName)
+	}
+	if exists && !o.Overwrite {
+		return errors.Errorf("the requirements file %s already exists. Please specify --overwrite to replace it", fileName)
+	}
+
+	if !o.BatchMode {
+		err = o.pickValues()
+		if err != nil {
+			return err
+		}
+	}
+	err = o.Validate()
+	if err != nil {
+		return err
+	}
+
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.ClusterName = o.ClusterName
+	requirements.Cluster.Provider = o.Provider
+	requirements.Cluster.ProjectID = o.ProjectID
+	requirements.Cluster.Region = o.Region
+	requirements.Cluster.Zone = o.Zone
+	requirements.Cluster.EnvironmentGitOwner = o.GitOwner
+	if o.SecretStorage != "" {
+		requirements.SecretStorage = config.SecretStorageType(o.SecretStorage)
+	}
+
+	err = requirements.SaveConfig(fileName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save %s", fileName)
+	}
+	log.Logger().Infof("created requirements file %s", util.ColorInfo(fileName))
+	return nil
+}
+
+// Validate verifies the values are valid including any provider specific values
+func (o *InitOptions) Validate() error {
+	if o.ClusterName == "" {
+		return util.MissingOption("cluster")
+	}
+	if o.Provider == "" {
+		return util.MissingOption("provider")
+	}
+	if util.StringArrayIndex(cloud.KubernetesProviders, o.Provider) < 0 {
+		return util.InvalidOption("provider", o.Provider, cloud.KubernetesProviders)
+	}
+	if o.GitOwner == "" {
+		return util.MissingOption("env-git-owner")
+	}
+	if o.SecretStorage != "" && util.StringArrayIndex(config.SecretStorageTypeValues, o.SecretStorage) < 0 {
+		return util.InvalidOption("secret", o.SecretStorage, config.SecretStorageTypeValues)
+	}
+	switch o.Provider {
+	case cloud.GKE:
+		if o.ProjectID == "" {
+			return util.MissingOption("project")
+		}
+		if o.Zone == "" && o.Region == "" {
+			return errors.Errorf("the %s provider requires either the zone or region to be specified via --zone or --region", o.Provider)
+		}
+	case cloud.EKS, cloud.AWS:
+		if o.Region == "" {
+			return util.MissingOption("region")
+		}
+	}
+	if o.SecretStorage == string(config.SecretStorageTypeGSM) && o.Provider != cloud.GKE {
+		return util.InvalidOptionf("secret", o.SecretStorage, "google secret manager (GSM) is only supported on the %s provider", cloud.GKE)
+	}
+	return nil
+}
+
+// pickValues prompts the user for any values which have not been specified
+func (o *InitOptions) pickValues() error {
+	handles := common.GetIOFileHandles(o.IOFileHandles)
+	var err error
+	o.ClusterName, err = util.PickValue("cluster name:", o.ClusterName, true, "the name of the kubernetes cluster", handles)
+	if err != nil {
+		return err
+	}
+	o.Provider, err = util.PickNameWithDefault(cloud.KubernetesProviders, "kubernetes provider:", o.Provider, "the kind of kubernetes cluster you are using", handles)
+	if err != nil {
+		return err
+	}
+	switch o.Provider {
+	case cloud.GKE:
+		o.ProjectID, err = util.PickValue("google project ID:", o.ProjectID, true, "the google cloud project containing the cluster", handles)
+		if err != nil {
+			return err
+		}
+		o.Zone, err = util.PickValue("zone:", o.Zone, o.Region == "", "the zone of the cluster. Leave blank for a regional cluster", handles)
+		if err != nil {
+			return err
+		}
+		if o.Zone == "" {
+			o.Region, err = util.PickValue("region:", o.Region, true, "the region of the cluster", handles)
+			if err != nil {
+				return err
+			}
+		}
+	case cloud.EKS, cloud.AWS:
+		o.Region, err = util.PickValue("region:", o.Region, true, "the AWS region of the cluster", handles)
+		if err != nil {
+			return err
+		}
+	}
+	o.GitOwner, err = util.PickValue("git owner (user/organization) for the environment repositories:", o.GitOwner, true, "", handles)
+	if err != nil {
+		return err
+	}
+	o.SecretStorage, err = util.PickNameWithDefault(config.SecretStorageTypeValues, "secret storage:", o.SecretStorage, "where the boot secrets are stored", handles)
+	if err != nil {
+		return err
+	}
+	return nil
+}
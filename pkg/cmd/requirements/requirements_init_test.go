@@ -0,0 +1,58 @@
+package requirements_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/cmd/requirements"
+	"github.com/jenkins-x/jx/pkg/cloud"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequirementsInitBatchMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-requirements-init-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(dir)
+
+	_, o := requirements.NewCmdInit()
+	o.Dir = dir
+	o.BatchMode = true
+	o.ClusterName = "mycluster"
+	o.Provider = cloud.GKE
+	o.GitOwner = "myorg"
+
+	err = o.Run()
+	require.Error(t, err, "should have failed without a project for gke")
+
+	o.ProjectID = "myproject"
+	o.Zone = "europe-west1-b"
+	o.SecretStorage = string(config.SecretStorageTypeVault)
+	err = o.Run()
+	require.NoError(t, err, "failed to create the requirements")
+
+	r, fileName, err := config.LoadRequirementsConfig(dir)
+	require.NoError(t, err, "failed to load the requirements from %s", dir)
+	t.Logf("created requirements file %s", fileName)
+	assert.Equal(t, "mycluster", r.Cluster.ClusterName, "clusterName")
+	assert.Equal(t, cloud.GKE, r.Cluster.Provider, "provider")
+	assert.Equal(t, "myproject", r.Cluster.ProjectID, "project")
+	assert.Equal(t, "europe-west1-b", r.Cluster.Zone, "zone")
+	assert.Equal(t, "myorg", r.Cluster.EnvironmentGitOwner, "environmentGitOwner")
+	assert.Equal(t, config.SecretStorageTypeVault, r.SecretStorage, "secretStorage")
+
+	err = o.Run()
+	require.Error(t, err, "should not overwrite an existing requirements file")
+
+	o.Overwrite = true
+	o.Provider = cloud.EKS
+	o.SecretStorage = ""
+	err = o.Run()
+	require.Error(t, err, "should have failed without a region for eks")
+
+	o.Region = "us-east-1"
+	err = o.Run()
+	require.NoError(t, err, "failed to overwrite the requirements")
+}
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"github.com/jenkins-x-labs/helmboot/pkg/cmd/completion"
 	"github.com/jenkins-x-labs/helmboot/pkg/cmd/create"
 	"github.com/jenkins-x-labs/helmboot/pkg/cmd/destroy"
+	"github.com/jenkins-x-labs/helmboot/pkg/cmd/requirements"
 	"github.com/jenkins-x-labs/helmboot/pkg/cmd/run"
 	"github.com/jenkins-x-labs/helmboot/pkg/cmd/secrets"
 	"github.com/jenkins-x-labs/helmboot/pkg/cmd/show"
@@ -26,14 +28,21 @@ func Main() *cobra.Command {
 			}
 		},
 	}
+	common.AddColorFlags(cmd)
+	common.AddUserAgentFlags(cmd)
+	cobra.OnInitialize(common.ConfigureColor, common.ConfigureUserAgent)
+
 	cmd.AddCommand(run.NewCmdRun())
 	cmd.AddCommand(secrets.NewCmdSecrets())
+	cmd.AddCommand(completion.NewCmdCompletion())
 	cmd.AddCommand(step.NewCmdStep())
 	cmd.AddCommand(destroy.NewCmdDestroy())
+	cmd.AddCommand(requirements.NewCmdRequirements())
 
 	cmd.AddCommand(common.SplitCommand(create.NewCmdCreate()))
 	cmd.AddCommand(common.SplitCommand(upgrade.NewCmdUpgrade()))
 	cmd.AddCommand(verify.NewCmdVerify())
+	cmd.AddCommand(common.SplitCommand(run.NewCmdDoctor()))
 	cmd.AddCommand(common.SplitCommand(show.NewCmdShow()))
 	return cmd
 }
@@ -0,0 +1,22 @@
+package cmd_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/cmd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunRequirementsFlag(t *testing.T) {
+	root := cmd.Main()
+	runCmd, _, err := root.Find([]string{"run"})
+	require.NoError(t, err, "failed to find the run command")
+
+	err = runCmd.ParseFlags([]string{"-r", "a.yml", "-r", "b.yml"})
+	require.NoError(t, err, "failed to parse the run flags")
+
+	files, err := runCmd.Flags().GetStringArray("requirements")
+	require.NoError(t, err, "failed to get the requirements flag")
+	assert.Equal(t, []string{"a.yml", "b.yml"}, files, "requirements files")
+}
@@ -0,0 +1,40 @@
+package run
+
+import (
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/config"
+)
+
+// verifyAllowedGitHostOptions verifies the git URLs specified on the command line are on the allowed git hosts
+func (o *RunOptions) verifyAllowedGitHostOptions() error {
+	var err error
+	o.allowedGitHosts, err = githelpers.ParseAllowedGitHosts(o.AllowedGitHosts)
+	if err != nil || len(o.allowedGitHosts) == 0 {
+		return err
+	}
+	gitURLs := o.gitURLMirrors
+	if len(gitURLs) == 0 {
+		gitURLs = []string{o.GitURL}
+	}
+	for _, gitURL := range gitURLs {
+		err = githelpers.VerifyAllowedGitHost("git URL", gitURL, o.allowedGitHosts)
+		if err != nil {
+			return err
+		}
+	}
+	err = githelpers.VerifyAllowedGitHost("requirements git URL", o.RequirementsGit, o.allowedGitHosts)
+	if err != nil {
+		return err
+	}
+	return githelpers.VerifyAllowedGitHost("versions repository", o.VersionStreamURL, o.allowedGitHosts)
+}
+
+// verifyAllowedGitHosts verifies the resolved boot git URL and the version stream of the requirements are on the
+// allowed git hosts as they may have come from the cluster rather than the command line
+func (o *RunOptions) verifyAllowedGitHosts(requirements *config.RequirementsConfig, gitURL string) error {
+	err := githelpers.VerifyAllowedGitHost("git URL", gitURL, o.allowedGitHosts)
+	if err != nil {
+		return err
+	}
+	return githelpers.VerifyAllowedGitHost("version stream", requirements.VersionStream.URL, o.allowedGitHosts)
+}
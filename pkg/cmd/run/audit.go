@@ -0,0 +1,88 @@
+package run
+
+import (
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+)
+
+// auditBootJob appends an event to the audit ConfigMap if one is configured. Any failures are logged
+// rather than returned so that auditing never masks the outcome of the boot Job
+func (o *RunOptions) auditBootJob(bootErr error) {
+	if o.AuditConfigMap == "" {
+		return
+	}
+	event := &clienthelpers.AuditEvent{
+		Timestamp: time.Now().UTC(),
+		Actor:     currentActor(),
+		GitURL:    githelpers.RedactURLs(o.GitURL),
+		GitRef:    o.bootGitRef(),
+		GitCommit: o.bootGitCommit,
+		Outcome:   "succeeded",
+	}
+	if bootErr != nil {
+		event.Outcome = "failed"
+		event.Message = githelpers.RedactURLs(bootErr.Error())
+	}
+
+	kubeClient, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		log.Logger().Warnf("failed to create kube client to record the audit event: %s", err.Error())
+		return
+	}
+	err = clienthelpers.AppendAuditEvent(kubeClient, ns, o.AuditConfigMap, event)
+	if err != nil {
+		log.Logger().Warnf("failed to record the audit event: %s", err.Error())
+		return
+	}
+	log.Logger().Debugf("recorded the audit event in ConfigMap %s in namespace %s", util.ColorInfo(o.AuditConfigMap), ns)
+}
+
+// emitBootEvent creates a Kubernetes Event for a phase of the boot if enabled and this is not a dry run. Any failure is
+// logged rather than failing the boot
+func (o *RunOptions) emitBootEvent(eventType string, reason string, phase string) {
+	if !o.EmitEvents || o.DryRun {
+		return
+	}
+	kubeClient, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		log.Logger().Warnf("failed to create kube client to record the %s Event: %s", reason, err.Error())
+		return
+	}
+	message := clienthelpers.BootEventMessage(phase, o.clusterName, githelpers.StripCredentials(o.GitURL), o.bootGitRef())
+	_, err = clienthelpers.CreateBootEvent(kubeClient, ns, eventType, reason, message)
+	if err != nil {
+		log.Logger().Warnf("failed to record the %s Event: %s", reason, err.Error())
+	}
+}
+
+// pushMetrics pushes the boot metrics to the Prometheus Pushgateway if one is configured. Any failure is logged
+// rather than failing the boot
+func (o *RunOptions) pushMetrics(bootErr error) {
+	if o.Pushgateway == "" {
+		return
+	}
+	labels := map[string]string{"git_ref": o.bootGitRef()}
+	text := common.BootMetricsText(o.timer, bootErr == nil, labels)
+	err := common.PushMetrics(o.Pushgateway, common.MetricsJobName, map[string]string{"cluster": o.clusterName}, text)
+	if err != nil {
+		log.Logger().Warnf("failed to push the boot metrics: %s", err.Error())
+		return
+	}
+	log.Logger().Debugf("pushed the boot metrics to %s", util.ColorInfo(o.Pushgateway))
+}
+
+// currentActor returns the name of the user running the command
+func currentActor() string {
+	u, err := user.Current()
+	if err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
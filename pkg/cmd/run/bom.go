@@ -0,0 +1,136 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	bomLong = templates.LongDesc(`
+		Outputs the bill of materials of the charts, images and versions the boot Job would install without running it so they can be audited
+`)
+
+	bomExample = templates.Examples(`
+		# prints the bill of materials as YAML
+		%s run bom
+
+		# writes the bill of materials as JSON to a file
+		%s run bom --format json --out /tmp/bom.json
+	`)
+
+	bomFormats = []string{"yaml", "json"}
+)
+
+// BOMOptions the options for creating the bill of materials
+type BOMOptions struct {
+	RunOptions
+	OutFile string
+	Format  string
+}
+
+// NewCmdBOM creates a command object for the command
+func NewCmdBOM() (*cobra.Command, *BOMOptions) {
+	o := &BOMOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "bom",
+		Short:   "Outputs the charts, images and versions the boot Job would install",
+		Long:    bomLong,
+		Example: fmt.Sprintf(bomExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory to look for the requirements")
+	cmd.Flags().StringVarP(&o.GitURL, "git-url", "u", "", "the git URL of the boot configuration. If not specified it is discovered from the dev Environment or the current git clone")
+	cmd.Flags().StringVarP(&o.RequirementsGit, "requirements-git-url", "", "", "the git URL of a repository containing the jx-requirements.yml to use rather than the requirements of the boot configuration")
+	cmd.Flags().StringVarP(&o.RequirementsRef, "requirements-git-ref", "", "", "the git ref of the --requirements-git-url repository. If not specified the default branch is used")
+	cmd.Flags().StringVarP(&o.ChartName, "chart", "c", defaultChartName, "the chart name to use to install the boot Job")
+	cmd.Flags().StringVarP(&o.BootJob.Image, "boot-image", "", "", "overrides the image repository of the boot Job")
+	cmd.Flags().StringVarP(&o.BootJob.ImageTag, "boot-image-tag", "", "", "overrides the image tag of the boot Job")
+	cmd.Flags().StringVarP(&o.Format, "format", "", "yaml", "the output format. Possible values: yaml, json")
+	cmd.Flags().StringVarP(&o.OutFile, "out", "o", "", "the file to write the bill of materials to. If not specified it is written to stdout")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input")
+	o.Proxy.AddFlags(cmd)
+	o.TLS.AddFlags(cmd)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *BOMOptions) Run() error {
+	if util.StringArrayIndex(bomFormats, o.Format) < 0 {
+		return util.InvalidOption("format", o.Format, bomFormats)
+	}
+	err := o.BootJob.Validate()
+	if err != nil {
+		return err
+	}
+	err = o.Proxy.Apply()
+	if err != nil {
+		return err
+	}
+	err = o.TLS.Apply()
+	if err != nil {
+		return err
+	}
+	o.KindResolver.Dir = o.Dir
+	o.KindResolver.Gitter = o.Git()
+
+	err = o.detectGitURL()
+	if err != nil {
+		return err
+	}
+	requirements, _, err := o.findRequirementsAndGitURL()
+	if err != nil {
+		return err
+	}
+	resolver, err := o.cloneVersionStream(requirements)
+	if err != nil {
+		return err
+	}
+	version := ""
+	if !o.isLocalChart() {
+		version, err = o.stableChartVersion(requirements, resolver)
+		if err != nil {
+			return err
+		}
+	}
+	bom, err := reqhelpers.CreateBillOfMaterials(requirements, resolver.VersionsDir, o.ChartName, version, &o.BootJob)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	if o.Format == "json" {
+		data, err = json.MarshalIndent(bom, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = yaml.Marshal(bom)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal the bill of materials to %s", o.Format)
+	}
+	if o.OutFile == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	err = ioutil.WriteFile(o.OutFile, data, util.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", o.OutFile)
+	}
+	log.Logger().Infof("saved the bill of materials to %s", util.ColorInfo(o.OutFile))
+	return nil
+}
@@ -0,0 +1,98 @@
+package run
+
+import (
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// resolveGitCommit resolves the commit of the git ref being booted so it can be recorded once the boot succeeds.
+// A failure is only returned if the commit is required to check if the boot is up to date
+func (o *RunOptions) resolveGitCommit(gitURL string) error {
+	o.bootGitURL = githelpers.StripCredentials(gitURL)
+	o.bootGitCommit = ""
+
+	// lets prefer the git URL with any credentials added for a private repository
+	remoteURL := gitURL
+	if githelpers.StripCredentials(o.GitURL) == o.bootGitURL {
+		remoteURL = o.GitURL
+	}
+	ref := o.bootGitRef()
+	commit, err := githelpers.RemoteCommitSHA(remoteURL, ref)
+	if err != nil {
+		if o.IfChanged {
+			return errors.Wrapf(githelpers.RedactError(err), "failed to find the commit of git ref %s to check if the boot is up to date", ref)
+		}
+		log.Logger().Debugf("failed to find the commit of git ref %s: %s", ref, githelpers.RedactError(err).Error())
+		return nil
+	}
+	o.bootGitCommit = commit
+	return nil
+}
+
+// isUpToDate returns true if the last successful boot was of the same git URL and commit and the dev Environment is healthy
+func (o *RunOptions) isUpToDate() (bool, error) {
+	kubeClient, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create kube client")
+	}
+	record, err := clienthelpers.LoadBootRecord(kubeClient, ns)
+	if err != nil {
+		return false, err
+	}
+	if record == nil {
+		log.Logger().Infof("no previous successful boot is recorded so booting")
+		return false, nil
+	}
+	if !record.Matches(o.bootGitURL, o.bootGitCommit) {
+		log.Logger().Infof("the last successful boot was of commit %s of %s so booting", record.GitCommit, githelpers.RedactURLs(record.GitURL))
+		return false, nil
+	}
+
+	jxClient, jxNS, err := o.KindResolver.GetFactory().CreateJXClient()
+	if err != nil {
+		return false, errors.Wrap(err, "failed to create JX client")
+	}
+	dev, err := kube.GetDevEnvironment(jxClient, jxNS)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, errors.Wrapf(err, "failed to find the dev Environment in namespace %s", jxNS)
+	}
+	if dev == nil {
+		log.Logger().Infof("no dev Environment found in namespace %s so booting", jxNS)
+		return false, nil
+	}
+	err = o.verifyJobNotFailed(kubeClient, ns)
+	if err != nil {
+		log.Logger().Infof("the last boot Job is not healthy so booting: %s", err.Error())
+		return false, nil
+	}
+	return true, nil
+}
+
+// saveBootRecord records the git source of the successful boot so that later runs can check if they are up to date.
+// Any failure is logged rather than returned as the boot itself has succeeded
+func (o *RunOptions) saveBootRecord() {
+	kubeClient, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		log.Logger().Warnf("failed to create kube client to record the boot: %s", err.Error())
+		return
+	}
+	record := &clienthelpers.BootRecord{
+		GitURL:    o.bootGitURL,
+		GitRef:    o.bootGitRef(),
+		GitCommit: o.bootGitCommit,
+		Timestamp: time.Now(),
+	}
+	err = clienthelpers.SaveBootRecord(kubeClient, ns, record)
+	if err != nil {
+		log.Logger().Warnf("failed to record the boot: %s", err.Error())
+		return
+	}
+	log.Logger().Debugf("recorded the boot in ConfigMap %s in namespace %s", util.ColorInfo(clienthelpers.BootRecordConfigMap), ns)
+}
@@ -0,0 +1,205 @@
+package run
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	doctorLong = templates.LongDesc(`
+		Diagnoses an existing Jenkins X installation by checking the dev Environment, the boot secrets, the last run of the boot Job and the development git repository
+`)
+
+	doctorExample = templates.Examples(`
+		# diagnoses the installation in the current cluster
+		%s doctor
+
+		# outputs the findings as JSON for scripting
+		%s doctor -o json
+	`)
+)
+
+// DoctorOptions the options for diagnosing an installation
+type DoctorOptions struct {
+	RunOptions
+	Output string
+	Out    io.Writer
+}
+
+// DoctorFinding the result of a single check made by the doctor command
+type DoctorFinding struct {
+	Check       string `json:"check"`
+	Status      string `json:"status"`
+	Critical    bool   `json:"critical"`
+	Message     string `json:"message"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+// NewCmdDoctor creates a command object for the command
+func NewCmdDoctor() (*cobra.Command, *DoctorOptions) {
+	o := &DoctorOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "doctor",
+		Short:   "Diagnoses a broken Jenkins X installation",
+		Long:    doctorLong,
+		Example: fmt.Sprintf(doctorExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory to look for the requirements")
+	cmd.Flags().StringVarP(&o.GitURL, "git-url", "u", "", "the git URL of the boot configuration. If not specified it is discovered from the dev Environment or the current git clone")
+	cmd.Flags().StringVarP(&o.KindResolver.Kind, "secrets", "s", "", "the kind of secret manager to check. If not specified it is detected from the cluster")
+	cmd.Flags().StringVarP(&o.KindResolver.JXNamespace, "jx-namespace", "", "", "the namespace to find the dev Environment in if it is not the current namespace")
+	o.KindResolver.AddImpersonationFlags(cmd)
+	common.AddOutputFlag(cmd, &o.Output)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *DoctorOptions) Run() error {
+	if o.Out == nil {
+		o.Out = os.Stdout
+	}
+	if o.Output == "" {
+		o.Output = common.OutputFormatTable
+	}
+	err := common.ValidateOutputFormat(o.Output)
+	if err != nil {
+		return err
+	}
+	o.KindResolver.Dir = o.Dir
+	o.KindResolver.Gitter = o.Git()
+
+	var findings []DoctorFinding
+	findings = append(findings, o.checkDevEnvironment())
+	findings = append(findings, o.checkSecrets())
+	findings = append(findings, o.checkBootJob())
+	findings = append(findings, o.checkGitURL())
+
+	critical := 0
+	table := common.NewTable("STATUS", "CHECK", "MESSAGE", "REMEDIATION")
+	for i := range findings {
+		f := &findings[i]
+		f.Status = "OK"
+		status := util.ColorInfo(f.Status)
+		if f.Critical {
+			f.Status = "FAIL"
+			status = util.ColorError(f.Status)
+			critical++
+		} else if f.Remediation != "" {
+			f.Status = "WARN"
+			status = util.ColorWarning(f.Status)
+		}
+		table.AddRow(status, f.Check, f.Message, f.Remediation)
+	}
+	err = common.WriteOutput(o.Out, o.Output, findings, table)
+	if err != nil {
+		return err
+	}
+	if critical > 0 {
+		return errors.Errorf("found %d critical problem(s) with the installation", critical)
+	}
+	common.LogResult("no critical problems found with the installation")
+	return nil
+}
+
+func (o *DoctorOptions) checkDevEnvironment() DoctorFinding {
+	f := DoctorFinding{Check: "dev Environment"}
+	jxClient, ns, err := o.KindResolver.GetFactory().CreateJXClient()
+	if err != nil {
+		return criticalFinding(f, errors.Wrap(err, "failed to create JX Client"), "Please check you are connected to the correct cluster")
+	}
+	dev, err := kube.GetDevEnvironment(jxClient, ns)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return criticalFinding(f, errors.Wrapf(err, "failed to get the dev Environment in namespace %s", ns), "Please check your RBAC permissions")
+	}
+	if dev == nil {
+		return criticalFinding(f, errors.Errorf("no dev Environment found in namespace %s", ns), "Please run the boot Job via 'helmboot run'")
+	}
+	if dev.Spec.Source.URL == "" {
+		return criticalFinding(f, errors.Errorf("the dev Environment in namespace %s has no source URL", ns), "Please run the boot Job via 'helmboot run --git-url'")
+	}
+	f.Message = fmt.Sprintf("found in namespace %s with source %s", ns, githelpers.RedactURLs(dev.Spec.Source.URL))
+	return f
+}
+
+func (o *DoctorOptions) checkSecrets() DoctorFinding {
+	f := DoctorFinding{Check: "boot secrets"}
+	err := o.KindResolver.VerifySecrets()
+	if err != nil {
+		return criticalFinding(f, err, "Please populate the secrets via 'helmboot secrets edit'")
+	}
+	f.Message = fmt.Sprintf("populated in the %s secret manager", o.KindResolver.Kind)
+	return f
+}
+
+func (o *DoctorOptions) checkBootJob() DoctorFinding {
+	f := DoctorFinding{Check: "boot Job"}
+	client, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		return criticalFinding(f, errors.Wrap(err, "failed to create Kubernetes client"), "Please check you are connected to the correct cluster")
+	}
+	job, err := client.BatchV1().Jobs(ns).Get("jx-boot", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			f.Message = fmt.Sprintf("no boot Job found in namespace %s", ns)
+			f.Remediation = "The boot Job may have been removed. Run it again via 'helmboot run' if the installation is not up to date"
+			return f
+		}
+		return criticalFinding(f, errors.Wrapf(err, "failed to get Job jx-boot in namespace %s", ns), "Please check your RBAC permissions")
+	}
+	err = o.verifyJobNotFailed(client, ns)
+	if err != nil {
+		return criticalFinding(f, err, fmt.Sprintf("Please view the logs via 'kubectl logs job/jx-boot -n %s' then run 'helmboot run' again", ns))
+	}
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobComplete && c.Status == corev1.ConditionTrue {
+			f.Message = "the last run completed successfully"
+			return f
+		}
+	}
+	f.Message = "the boot Job is still running"
+	f.Remediation = fmt.Sprintf("You can follow its progress via 'kubectl logs -f job/jx-boot -n %s'", ns)
+	return f
+}
+
+func (o *DoctorOptions) checkGitURL() DoctorFinding {
+	f := DoctorFinding{Check: "git repository"}
+	_, gitURL, err := o.findRequirementsAndGitURL()
+	if err != nil {
+		return criticalFinding(f, err, "Please specify the development git repository via --git-url")
+	}
+	if gitURL == "" {
+		return criticalFinding(f, errors.Errorf("could not find the development git repository"), "Please specify the development git repository via --git-url")
+	}
+	_, err = githelpers.GetRemoteRefSHA(gitURL, "HEAD")
+	if err != nil {
+		return criticalFinding(f, errors.Wrapf(githelpers.RedactError(err), "could not reach %s", githelpers.RedactURLs(gitURL)), "Please check the git URL and that the git user and token in the boot secrets can access it")
+	}
+	f.Message = fmt.Sprintf("%s is reachable", githelpers.RedactURLs(gitURL))
+	return f
+}
+
+func criticalFinding(f DoctorFinding, err error, remediation string) DoctorFinding {
+	f.Critical = true
+	f.Message = err.Error()
+	f.Remediation = remediation
+	return f
+}
@@ -0,0 +1,124 @@
+package run_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/cmd/run"
+	"github.com/jenkins-x-labs/helmboot/pkg/fakes/fakejxfactory"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	validSecretsYaml = `secrets:
+  adminUser:
+    username: admin
+    password: dummypwd
+  hmacToken: TODO
+  pipelineUser:
+    username: someuser
+    token: dummytoken
+    email: me@foo.com
+`
+
+	incompleteSecretsYaml = `secrets:
+  hmacToken: TODO
+`
+)
+
+func TestDoctorHealthyInstallation(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	devEnv := kube.CreateDefaultDevEnvironment("jx")
+	devEnv.Namespace = "jx"
+	devEnv.Spec.Source.URL = "file://" + repoDir
+	job := newBootJob(batchv1.JobComplete)
+
+	for _, format := range []string{"json", "yaml"} {
+		out := &bytes.Buffer{}
+		o := newDoctorOptions(t, repoDir, validSecretsYaml, []runtime.Object{job}, []runtime.Object{devEnv})
+		o.Output = format
+		o.Out = out
+
+		err := o.Run()
+		require.NoError(t, err, "%s: should not have found any critical problems", format)
+
+		var findings []run.DoctorFinding
+		if format == "json" {
+			err = json.Unmarshal(out.Bytes(), &findings)
+		} else {
+			err = yaml.Unmarshal(out.Bytes(), &findings)
+		}
+		require.NoError(t, err, "%s: failed to parse the output %s", format, out.String())
+		require.Len(t, findings, 4, "%s: findings", format)
+		for _, f := range findings {
+			assert.Equal(t, "OK", f.Status, "%s: status of check %s: %s", format, f.Check, f.Message)
+			assert.False(t, f.Critical, "%s: check %s should not be critical", format, f.Check)
+		}
+		assert.Equal(t, "the last run completed successfully", findings[2].Message, "%s: boot Job message", format)
+	}
+}
+
+func TestDoctorBrokenInstallation(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	out := &bytes.Buffer{}
+	o := newDoctorOptions(t, repoDir, incompleteSecretsYaml, []runtime.Object{newBootJob(batchv1.JobFailed)}, nil)
+	o.Out = out
+
+	err := o.Run()
+	require.Error(t, err, "should have found critical problems")
+	assert.Equal(t, "found 4 critical problem(s) with the installation", err.Error(), "error message")
+
+	table := out.String()
+	for _, expected := range []string{"STATUS", "REMEDIATION", "FAIL", "no dev Environment found in namespace jx", "missing secret entry: secrets.adminUser.username", "the boot Job has failed", "helmboot run"} {
+		assert.Contains(t, table, expected, "table output")
+	}
+}
+
+func TestDoctorInvalidOutput(t *testing.T) {
+	o := &run.DoctorOptions{Output: "xml", Out: &bytes.Buffer{}}
+	err := o.Run()
+	assert.Error(t, err, "should have failed with an invalid output format")
+}
+
+// newDoctorOptions creates the doctor options using a fake cluster and a file secret manager containing the secrets
+func newDoctorOptions(t *testing.T, repoDir string, secretsYaml string, kubeObjects []runtime.Object, jxObjects []runtime.Object) *run.DoctorOptions {
+	secretsFile := filepath.Join(repoDir, "secrets.yaml")
+	err := ioutil.WriteFile(secretsFile, []byte(secretsYaml), 0600)
+	require.NoError(t, err, "failed to save file %s", secretsFile)
+
+	kubeObjects = append(kubeObjects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "jx"}})
+
+	o := &run.DoctorOptions{}
+	o.Dir = repoDir
+	o.KindResolver.Kind = secretmgr.KindFile
+	o.KindResolver.SecretPath = secretsFile
+	o.KindResolver.Factory = fakejxfactory.NewFakeFactoryWithObjects(kubeObjects, jxObjects, "jx")
+	return o
+}
+
+func newBootJob(conditionType batchv1.JobConditionType) *batchv1.Job {
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: "jx-boot", Namespace: "jx"},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{Type: conditionType, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+			},
+		},
+	}
+}
@@ -0,0 +1,85 @@
+package run
+
+import (
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// validateImagePullSecretOptions validates the options for creating the image pull Secret
+func (o *RunOptions) validateImagePullSecretOptions() error {
+	if !o.CreatePullSecret {
+		return nil
+	}
+	if len(o.BootJob.ImagePullSecrets) == 0 {
+		return util.MissingOption("job-image-pull-secret")
+	}
+	if o.RegistryServer == "" {
+		return util.MissingOption("registry-server")
+	}
+	if o.RegistryUsername == "" {
+		return util.MissingOption("registry-username")
+	}
+	if o.RegistryPassword == "" {
+		return util.MissingOption("registry-password")
+	}
+	return nil
+}
+
+// verifyImagePullSecrets creates the image pull Secret if enabled and verifies all of the image pull Secrets of the
+// boot Job exist so that we fail fast rather than the boot image failing to pull
+func (o *RunOptions) verifyImagePullSecrets(requirements *config.RequirementsConfig) error {
+	names := o.BootJob.ImagePullSecrets
+	if len(names) == 0 {
+		return nil
+	}
+	kubeClient, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to create kube client")
+	}
+	if requirements.Cluster.Namespace != "" {
+		ns = requirements.Cluster.Namespace
+	}
+	if o.CreatePullSecret {
+		name := names[0]
+		if o.DryRun {
+			log.Logger().Infof("would create the image pull Secret %s for registry %s", util.ColorInfo(name), util.ColorInfo(o.RegistryServer))
+			return nil
+		}
+		err = clienthelpers.CreateImagePullSecret(kubeClient, ns, name, o.RegistryServer, o.RegistryUsername, o.RegistryPassword)
+		if err != nil {
+			return err
+		}
+		log.Logger().Infof("saved the image pull Secret %s for registry %s", util.ColorInfo(name), util.ColorInfo(o.RegistryServer))
+	}
+	return clienthelpers.VerifyImagePullSecrets(kubeClient, ns, names)
+}
+
+// diagnoseImagePull returns a clearer error if the boot Job pod failed to start because its image cannot be pulled.
+// Otherwise the original error is returned
+func (o *RunOptions) diagnoseImagePull(client kubernetes.Interface, ns string, selector map[string]string, cause error) error {
+	podList, err := client.CoreV1().Pods(ns).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(selector).String(),
+	})
+	if err != nil {
+		return cause
+	}
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		failure := clienthelpers.ImagePullFailure(pod)
+		if failure != "" {
+			return imagePullError(pod.Name, failure)
+		}
+	}
+	return cause
+}
+
+// imagePullError returns the error for a boot Job pod which cannot pull its image
+func imagePullError(pod string, failure string) error {
+	return errors.Errorf("the boot Job pod %s cannot start as the %s. If the image is in a private registry specify its credentials via --job-image-pull-secret", pod, failure)
+}
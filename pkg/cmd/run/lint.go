@@ -0,0 +1,144 @@
+package run
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintLong = templates.LongDesc(`
+		Validates the boot installer chart by running 'helm lint' and 'helm template' with the values derived from the requirements so that chart mistakes are found without creating the boot Job
+`)
+
+	lintExample = templates.Examples(`
+		# validates the released installer chart
+		%s run lint --git-url https://github.com/myorg/environment-mycluster-dev.git
+
+		# validates a local installer chart while developing it
+		%s run lint --installer-dir ../jxl-boot
+	`)
+)
+
+// LintOptions the options for validating the boot installer chart
+type LintOptions struct {
+	RunOptions
+	ShowTemplate bool
+}
+
+// NewCmdLint creates a command object for the command
+func NewCmdLint() (*cobra.Command, *LintOptions) {
+	o := &LintOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "lint",
+		Short:   "Validates the boot installer chart with the requirements without creating the boot Job",
+		Long:    lintLong,
+		Example: fmt.Sprintf(lintExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory to look for the requirements")
+	cmd.Flags().StringVarP(&o.GitURL, "git-url", "u", "", "the git URL of the boot configuration. If not specified it is discovered from the dev Environment or the current git clone")
+	cmd.Flags().StringVarP(&o.KindResolver.JXNamespace, "jx-namespace", "", "", "the namespace to find the dev Environment in if it is not the current namespace")
+	o.KindResolver.AddImpersonationFlags(cmd)
+	cmd.Flags().StringVarP(&o.RequirementsGit, "requirements-git-url", "", "", "the git URL of a repository containing the jx-requirements.yml to use rather than the requirements of the boot configuration")
+	cmd.Flags().StringVarP(&o.RequirementsRef, "requirements-git-ref", "", "", "the git ref of the --requirements-git-url repository. If not specified the default branch is used")
+	cmd.Flags().StringVarP(&o.ChartName, "chart", "c", defaultChartName, "the chart name to validate")
+	cmd.Flags().StringArrayVarP(&o.BootJob.Set, "set", "", nil, "an additional value of the form key=value for the boot Job chart which helm may convert to a number or boolean. Can be specified multiple times")
+	cmd.Flags().StringArrayVarP(&o.BootJob.SetString, "set-string", "", nil, "an additional value of the form key=value for the boot Job chart which is always treated as a string. Can be specified multiple times")
+	cmd.Flags().StringVarP(&o.InstallerDir, "installer-dir", "", "", "a local directory containing the boot installer chart to validate rather than the released chart")
+	cmd.Flags().BoolVarP(&o.ShowTemplate, "show-template", "", false, "prints the rendered templates of the chart as well as any errors")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input")
+	o.Proxy.AddFlags(cmd)
+	o.TLS.AddFlags(cmd)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *LintOptions) Run() error {
+	err := o.BootJob.Validate()
+	if err != nil {
+		return err
+	}
+	if o.InstallerDir != "" {
+		o.InstallerDir, err = reqhelpers.VerifyInstallerDir(o.InstallerDir)
+		if err != nil {
+			return err
+		}
+	}
+	err = o.Proxy.Apply()
+	if err != nil {
+		return err
+	}
+	err = o.TLS.Apply()
+	if err != nil {
+		return err
+	}
+	o.KindResolver.Dir = o.Dir
+	o.KindResolver.Gitter = o.Git()
+
+	err = o.detectGitURL()
+	if err != nil {
+		return err
+	}
+	requirements, gitURL, err := o.findRequirementsAndGitURL()
+	if err != nil {
+		return err
+	}
+	if gitURL == "" {
+		return util.MissingOption("git-url")
+	}
+
+	o.BootJob.BatchMode = o.BatchMode
+	chartName, version, err := o.resolveInstallerChart(requirements)
+	if err != nil {
+		return err
+	}
+	chartDir := chartName
+	if version != "" {
+		tmpDir, err := ioutil.TempDir("", "helmboot-lint-")
+		if err != nil {
+			return errors.Wrap(err, "failed to create a temporary directory")
+		}
+		defer os.RemoveAll(tmpDir)
+
+		err = o.helmClient(tmpDir).FetchChart(chartName, version, true, "", "", "", "")
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch chart %s version %s", chartName, version)
+		}
+		chartDir = filepath.Join(tmpDir, path.Base(chartName))
+	}
+
+	for _, c := range reqhelpers.GetBootJobLintCommands(requirements, gitURL, chartDir, &o.BootJob) {
+		c.Out = os.Stdout
+		c.Err = os.Stderr
+		if c.Args[0] == "template" && !o.ShowTemplate {
+			c.Out = ioutil.Discard
+		}
+		commandLine := githelpers.RedactURLs(fmt.Sprintf("%s %s", c.Name, strings.Join(c.Args, " ")))
+		log.Logger().Infof("running: %s", util.ColorInfo(commandLine))
+
+		_, err = c.RunWithoutRetry()
+		if err != nil {
+			return errors.Wrapf(githelpers.RedactError(err), "failed to run command %s", commandLine)
+		}
+	}
+	common.LogResult("the installer chart %s is valid", util.ColorInfo(chartName))
+	return nil
+}
@@ -0,0 +1,54 @@
+package run
+
+import (
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// applyLock pins the boot to the commit recorded in the lock file if --use-lock is specified
+func (o *RunOptions) applyLock(gitURL string) error {
+	if !o.UseLock {
+		return nil
+	}
+	lock, err := reqhelpers.LoadBootLock(o.LockFile)
+	if err != nil {
+		return err
+	}
+	err = lock.VerifyGitURL(gitURL)
+	if err != nil {
+		return errors.Wrapf(err, "cannot use lock file %s", o.LockFile)
+	}
+	log.Logger().Infof("booting commit %s of git ref %s from lock file %s", util.ColorInfo(lock.GitCommit), lock.GitRef, o.LockFile)
+	o.GitRef = lock.GitCommit
+	o.BootJob.GitRef = lock.GitCommit
+	return nil
+}
+
+// saveLock pins the boot to the commit the git ref resolved to and records it in the lock file if --lock is specified
+func (o *RunOptions) saveLock(gitURL string) error {
+	if !o.Lock {
+		return nil
+	}
+	ref := o.bootGitRef()
+	if o.bootGitCommit == "" {
+		commit, err := githelpers.RemoteCommitSHA(gitURL, ref)
+		if err != nil {
+			return errors.Wrapf(githelpers.RedactError(err), "failed to resolve the commit of git ref %s to lock the boot", ref)
+		}
+		o.bootGitCommit = commit
+	}
+	o.BootJob.GitRef = o.bootGitCommit
+	if o.DryRun {
+		log.Logger().Infof("would lock git ref %s to commit %s in lock file %s", ref, util.ColorInfo(o.bootGitCommit), o.LockFile)
+		return nil
+	}
+	err := reqhelpers.SaveBootLock(o.LockFile, reqhelpers.NewBootLock(gitURL, ref, o.bootGitCommit))
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("locked git ref %s to commit %s in lock file %s", ref, util.ColorInfo(o.bootGitCommit), util.ColorInfo(o.LockFile))
+	return nil
+}
@@ -0,0 +1,201 @@
+package run
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/factory"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	logsLong = templates.LongDesc(`
+		Prints the logs of a previous boot run given its run ID. The logs are found in the --output-dir, the --logs-bucket or the boot Job pod if it still exists for the run
+`)
+
+	logsExample = templates.Examples(`
+		# prints the logs of a boot run saved via 'run --output-dir'
+		%s run logs --run-id 20200401-120000 --output-dir /tmp/boot-logs
+
+		# prints the logs of a boot run archived via 'run --logs-bucket'
+		%s run logs --run-id 20200401-120000 --logs-bucket gs://mybucket
+	`)
+)
+
+// LogsOptions the options for printing the logs of a previous boot run
+type LogsOptions struct {
+	KindResolver factory.KindResolver
+	RunID        string
+	OutputDir    string
+	LogsBucket   string
+	ClusterName  string
+	Dir          string
+}
+
+// NewCmdLogs creates a command object for the command
+func NewCmdLogs() (*cobra.Command, *LogsOptions) {
+	o := &LogsOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "logs",
+		Short:   "Prints the logs of a previous boot run",
+		Long:    logsLong,
+		Example: fmt.Sprintf(logsExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			common.SetLoggingLevel(cmd, args)
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.RunID, "run-id", "", "", "the ID of the boot run such as 20200401-120000 which is logged when the boot starts")
+	cmd.Flags().StringVarP(&o.OutputDir, "output-dir", "", "", "the directory the boot Job logs were saved in via 'run --output-dir'")
+	cmd.Flags().StringVarP(&o.LogsBucket, "logs-bucket", "", "", "the bucket URL such as gs://mybucket or s3://mybucket the boot Job logs were uploaded to via 'run --logs-bucket'")
+	cmd.Flags().StringVarP(&o.ClusterName, "cluster-name", "", "", "the name of the cluster whose folder in the --logs-bucket contains the logs. If not specified it is loaded from the jx-requirements.yml in --dir")
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory to look for the requirements")
+	o.KindResolver.AddImpersonationFlags(cmd)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *LogsOptions) Run() error {
+	if o.RunID == "" {
+		return util.MissingOption("run-id")
+	}
+	err := clienthelpers.ValidateLogsBucket(o.LogsBucket)
+	if err != nil {
+		return err
+	}
+	var knownRunIDs []string
+	if o.OutputDir != "" {
+		fileName := clienthelpers.RunLogFileName(o.OutputDir, o.RunID)
+		data, err := ioutil.ReadFile(fileName)
+		if err == nil {
+			log.Logger().Infof("printing the logs of boot run %s from %s", util.ColorInfo(o.RunID), util.ColorInfo(fileName))
+			return printLogs(string(data))
+		}
+		if !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to read the log file %s", fileName)
+		}
+		knownRunIDs, err = clienthelpers.RunLogIDs(o.OutputDir)
+		if err != nil {
+			return err
+		}
+	}
+	if o.LogsBucket != "" {
+		logs, err := o.bucketLogs()
+		if err != nil {
+			return err
+		}
+		if logs != "" {
+			return printLogs(logs)
+		}
+	}
+
+	logs, jobRunID, err := o.podLogs()
+	if err != nil {
+		return err
+	}
+	if logs != "" {
+		return printLogs(logs)
+	}
+	if jobRunID != "" && util.StringArrayIndex(knownRunIDs, jobRunID) < 0 {
+		knownRunIDs = append(knownRunIDs, jobRunID)
+		sort.Strings(knownRunIDs)
+	}
+	return clienthelpers.UnknownRunIDError(o.RunID, knownRunIDs)
+}
+
+// bucketLogs returns the logs of the boot run from the logs bucket or a blank string if they do not exist
+func (o *LogsOptions) bucketLogs() (string, error) {
+	clusterName := o.ClusterName
+	if clusterName == "" {
+		requirements, _, err := config.LoadRequirementsConfig(o.Dir)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to load the requirements from %s to find the cluster name", o.Dir)
+		}
+		clusterName = requirements.Cluster.ClusterName
+		if clusterName == "" {
+			return "", util.MissingOption("cluster-name")
+		}
+	}
+	bucketURL := strings.TrimSuffix(o.LogsBucket, "/") + "/" + clienthelpers.RunLogBucketKey(clusterName, o.RunID)
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the bucket URL %s", bucketURL)
+	}
+	data, err := buckets.ReadBucketURL(u, logsUploadTimeout)
+	if err != nil {
+		if clienthelpers.IsBucketNotFound(err) {
+			log.Logger().Debugf("no logs found at %s: %s", bucketURL, err.Error())
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to read the logs from %s", bucketURL)
+	}
+	log.Logger().Infof("printing the logs of boot run %s from %s", util.ColorInfo(o.RunID), util.ColorInfo(bucketURL))
+	return string(data), nil
+}
+
+// podLogs returns the logs of the pods of the boot Job if it was created by the boot run along with the run ID of the
+// boot Job if it exists
+func (o *LogsOptions) podLogs() (string, string, error) {
+	client, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to create kube client")
+	}
+	job, err := client.BatchV1().Jobs(ns).Get("jx-boot", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", "", nil
+		}
+		return "", "", errors.Wrapf(err, "failed to get the boot Job in namespace %s", ns)
+	}
+	jobRunID := job.Annotations[reqhelpers.RunIDAnnotation]
+	if jobRunID != o.RunID {
+		return "", jobRunID, nil
+	}
+	podInterface := client.CoreV1().Pods(ns)
+	podList, err := podInterface.List(metav1.ListOptions{
+		LabelSelector: "job-name=jx-boot",
+	})
+	if err != nil {
+		return "", jobRunID, errors.Wrapf(err, "failed to list the boot Job pods in namespace %s", ns)
+	}
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].CreationTimestamp.Before(&pods[j].CreationTimestamp)
+	})
+	buf := strings.Builder{}
+	for _, pod := range pods {
+		buf.WriteString(clienthelpers.LogSeparator(pod.Name, pod.CreationTimestamp.Time))
+		buf.WriteString(jobPodLogs(podInterface, pod.Name, "boot", 0))
+	}
+	if len(pods) > 0 {
+		log.Logger().Infof("printing the logs of boot run %s from the boot Job pods in namespace %s", util.ColorInfo(o.RunID), util.ColorInfo(ns))
+	}
+	return buf.String(), jobRunID, nil
+}
+
+// printLogs prints the logs to stdout
+func printLogs(logs string) error {
+	_, err := fmt.Fprint(os.Stdout, logs)
+	if err != nil {
+		return errors.Wrap(err, "failed to print the logs")
+	}
+	return nil
+}
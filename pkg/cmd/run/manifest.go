@@ -0,0 +1,138 @@
+package run
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestLong = templates.LongDesc(`
+		Renders the Kubernetes Job manifest of the boot Job without applying it so it can be reviewed or applied by other tooling
+`)
+
+	manifestExample = templates.Examples(`
+		# prints the boot Job manifest to the terminal
+		%s run manifest --git-url https://github.com/myorg/environment-mycluster-dev.git
+
+		# writes the boot Job manifest to a file
+		%s run manifest --out /tmp/boot-job.yaml
+	`)
+)
+
+// ManifestOptions the options for rendering the boot Job manifest
+type ManifestOptions struct {
+	RunOptions
+	OutFile string
+}
+
+// NewCmdManifest creates a command object for the command
+func NewCmdManifest() (*cobra.Command, *ManifestOptions) {
+	o := &ManifestOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "manifest",
+		Short:   "Renders the boot Job manifest without applying it",
+		Long:    manifestLong,
+		Example: fmt.Sprintf(manifestExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory to look for the requirements")
+	cmd.Flags().StringVarP(&o.GitURL, "git-url", "u", "", "the git URL of the boot configuration. If not specified it is discovered from the dev Environment or the current git clone")
+	cmd.Flags().StringVarP(&o.KindResolver.JXNamespace, "jx-namespace", "", "", "the namespace to find the dev Environment in if it is not the current namespace")
+	o.KindResolver.AddImpersonationFlags(cmd)
+	cmd.Flags().StringVarP(&o.RequirementsGit, "requirements-git-url", "", "", "the git URL of a repository containing the jx-requirements.yml to use rather than the requirements of the boot configuration")
+	cmd.Flags().StringVarP(&o.RequirementsRef, "requirements-git-ref", "", "", "the git ref of the --requirements-git-url repository. If not specified the default branch is used")
+	cmd.Flags().StringVarP(&o.ChartName, "chart", "c", defaultChartName, "the chart name to use to install the boot Job")
+	cmd.Flags().StringVarP(&o.BootJob.StartStep, "start-step", "", "", "the step in the boot pipeline to start from. Possible values: "+strings.Join(reqhelpers.KnownBootSteps, ", "))
+	cmd.Flags().StringVarP(&o.BootJob.EndStep, "end-step", "", "", "the step in the boot pipeline to end at. Possible values: "+strings.Join(reqhelpers.KnownBootSteps, ", "))
+	cmd.Flags().StringArrayVarP(&o.BootJob.Set, "set", "", nil, "an additional value of the form key=value for the boot Job chart which helm may convert to a number or boolean. Can be specified multiple times")
+	cmd.Flags().StringArrayVarP(&o.BootJob.SetString, "set-string", "", nil, "an additional value of the form key=value for the boot Job chart which is always treated as a string. Can be specified multiple times")
+	cmd.Flags().StringVarP(&o.InstallerDir, "installer-dir", "", "", "a local directory containing the boot installer chart to use rather than the released chart")
+	cmd.Flags().StringVarP(&o.OutFile, "out", "o", "", "the file to write the Job manifest to. If not specified it is written to stdout")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input")
+	o.Proxy.AddFlags(cmd)
+	o.TLS.AddFlags(cmd)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *ManifestOptions) Run() error {
+	err := o.BootJob.Validate()
+	if err != nil {
+		return err
+	}
+	if o.InstallerDir != "" {
+		o.InstallerDir, err = reqhelpers.VerifyInstallerDir(o.InstallerDir)
+		if err != nil {
+			return err
+		}
+	}
+	err = o.Proxy.Apply()
+	if err != nil {
+		return err
+	}
+	err = o.TLS.Apply()
+	if err != nil {
+		return err
+	}
+	o.KindResolver.Dir = o.Dir
+	o.KindResolver.Gitter = o.Git()
+
+	err = o.detectGitURL()
+	if err != nil {
+		return err
+	}
+	requirements, gitURL, err := o.findRequirementsAndGitURL()
+	if err != nil {
+		return err
+	}
+	if gitURL == "" {
+		return util.MissingOption("git-url")
+	}
+
+	c, err := o.bootJobCommand(requirements, gitURL)
+	if err != nil {
+		return err
+	}
+	c = reqhelpers.GetBootJobTemplateCommand(c)
+	commandLine := common.RedactTrace(fmt.Sprintf("%s %s", c.Name, strings.Join(c.Args, " ")))
+	log.Logger().Debugf("running the command: %s", commandLine)
+
+	text, err := c.RunWithoutRetry()
+	if err != nil {
+		return errors.Wrapf(githelpers.RedactError(err), "failed to run command %s", commandLine)
+	}
+	manifest, err := reqhelpers.FilterManifestsByKind(text, "Job")
+	if err != nil {
+		return errors.Wrapf(err, "failed to find the Job in the output of %s", commandLine)
+	}
+	if manifest == "" {
+		return errors.Errorf("no Job was rendered by the command %s", commandLine)
+	}
+
+	if o.OutFile == "" {
+		_, err = fmt.Fprint(os.Stdout, manifest)
+		return err
+	}
+	err = ioutil.WriteFile(o.OutFile, []byte(manifest), util.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", o.OutFile)
+	}
+	log.Logger().Infof("saved the boot Job manifest to %s", util.ColorInfo(o.OutFile))
+	return nil
+}
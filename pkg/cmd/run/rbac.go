@@ -0,0 +1,48 @@
+package run
+
+import (
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// bootPermissions the permissions required in the deployment namespace to run the boot Job
+var bootPermissions = []clienthelpers.ResourcePermission{
+	{Verb: "create", Group: "batch", Resource: "jobs"},
+	{Verb: "get", Resource: "secrets"},
+	{Verb: "create", Resource: "secrets"},
+	{Verb: "update", Resource: "secrets"},
+	{Verb: "get", Group: "jenkins.io", Resource: "environments"},
+}
+
+// verifyRBAC verifies the current identity has the permissions required to run the boot Job before we modify anything
+func (o *RunOptions) verifyRBAC(requirements *config.RequirementsConfig) error {
+	if o.SkipRBACCheck {
+		return nil
+	}
+	kubeClient, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to create kube client")
+	}
+	if requirements.Cluster.Namespace != "" {
+		ns = requirements.Cluster.Namespace
+	}
+
+	missing, err := clienthelpers.MissingPermissions(kubeClient, ns, bootPermissions)
+	if err != nil {
+		return errors.Wrap(err, "failed to verify RBAC permissions. You can disable this check via --skip-rbac-check")
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	var names []string
+	for _, p := range missing {
+		log.Logger().Errorf("missing RBAC permission to %s in namespace %s", util.ColorError(p.String()), ns)
+		names = append(names, p.String())
+	}
+	return errors.Errorf("the current identity does not have the RBAC permissions to run the boot Job in namespace %s: %s. You can disable this check via --skip-rbac-check", ns, strings.Join(names, ", "))
+}
@@ -0,0 +1,107 @@
+package run
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/bootconfig"
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	rollbackLong = templates.LongDesc(`
+		Re-runs the boot Job using the commit of the last successful boot before the most recent one recorded in the audit ConfigMap
+`)
+
+	rollbackExample = templates.Examples(`
+		# rolls back to the previously booted commit
+		%s run rollback --audit-configmap helmboot-audit
+	`)
+)
+
+// RollbackOptions the options for rolling back to the previously booted commit
+type RollbackOptions struct {
+	RunOptions
+}
+
+// NewCmdRollback creates a command object for the command
+func NewCmdRollback() (*cobra.Command, *RollbackOptions) {
+	o := &RollbackOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "rollback",
+		Short:   "Re-runs the boot Job using the previously booted commit",
+		Long:    rollbackLong,
+		Example: fmt.Sprintf(rollbackExample, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			common.SetLoggingLevel(cmd, args)
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.Dir, "dir", "d", ".", "the directory to look for the requirements")
+	cmd.Flags().StringVarP(&o.GitURL, "git-url", "u", "", "the git URL of the boot configuration. If not specified it is discovered from the dev Environment or the current git clone")
+	cmd.Flags().StringVarP(&o.AuditConfigMap, "audit-configmap", "", "", "the name of the audit ConfigMap the previous boots were recorded in via 'run --audit-configmap'")
+	cmd.Flags().StringVarP(&o.ChartName, "chart", "c", defaultChartName, "the chart name to use to install the boot Job")
+	cmd.Flags().StringVarP(&o.ConfigFile, "config", "", "", "the configuration file used to default the command line arguments. If not specified the "+bootconfig.FileName+" file in the current directory is used if it exists")
+	cmd.Flags().StringVarP(&o.VersionStreamURL, "versions-repo", "", common.DefaultVersionsURL, "the bootstrap URL for the versions repo")
+	cmd.Flags().StringVarP(&o.VersionStreamRef, "versions-ref", "", common.DefaultVersionsRef, "the bootstrap ref for the versions repo")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input")
+	o.Proxy.AddFlags(cmd)
+	o.TLS.AddFlags(cmd)
+	o.Uninstall.AddFlags(cmd)
+
+	o.GitRepoKind = gits.KindGitHub
+	o.Cmd = cmd
+	return cmd, o
+}
+
+// Run implements the command
+func (o *RollbackOptions) Run() error {
+	err := o.applyConfigFile()
+	if err != nil {
+		return err
+	}
+	if o.AuditConfigMap == "" {
+		return util.MissingOption("audit-configmap")
+	}
+	kubeClient, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to create kube client")
+	}
+	cm, err := kubeClient.CoreV1().ConfigMaps(ns).Get(o.AuditConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the audit ConfigMap %s in namespace %s", o.AuditConfigMap, ns)
+	}
+	events, err := clienthelpers.LoadAuditEvents(cm)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the audit events from ConfigMap %s", o.AuditConfigMap)
+	}
+	event := clienthelpers.FindRollbackEvent(events)
+	if event == nil {
+		return errors.Errorf("no previous successful boot of a different commit is recorded in the audit ConfigMap %s in namespace %s", o.AuditConfigMap, ns)
+	}
+
+	ref := event.BootedRef()
+	log.Logger().Infof("rolling back to commit %s of git ref %s which was booted successfully at %s by %s", util.ColorInfo(ref), event.GitRef, event.Timestamp.Format("2006-01-02 15:04:05"), event.Actor)
+	if !o.BatchMode {
+		confirm, err := util.Confirm(fmt.Sprintf("You are about to re-run the boot Job using %s. Are you sure?", ref), false, "The boot Job is re-run using the boot configuration at the previously booted commit", common.GetIOFileHandles(nil))
+		if err != nil {
+			return err
+		}
+		if !confirm {
+			return errors.Errorf("rollback aborted")
+		}
+	}
+	o.GitRef = ref
+	o.BootJob.GitRef = ref
+	return o.RunOptions.Run()
+}
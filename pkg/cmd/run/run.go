@@ -2,19 +2,25 @@ package run
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/jenkins-x-labs/helmboot/pkg/bootconfig"
 	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
 	"github.com/jenkins-x-labs/helmboot/pkg/cmd/secrets"
 	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
 	"github.com/jenkins-x-labs/helmboot/pkg/helmer"
 	"github.com/jenkins-x-labs/helmboot/pkg/jxadapt"
 	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/factory"
+	"github.com/jenkins-x/jx/pkg/cloud/buckets"
 	"github.com/jenkins-x/jx/pkg/cmd/boot"
 	"github.com/jenkins-x/jx/pkg/cmd/clients"
 	"github.com/jenkins-x/jx/pkg/cmd/helper"
@@ -23,6 +29,7 @@ import (
 	"github.com/jenkins-x/jx/pkg/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jxfactory"
 	"github.com/jenkins-x/jx/pkg/kube"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -30,19 +37,92 @@ import (
 	"github.com/jenkins-x/jx/pkg/versionstream/versionstreamrepo"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
 )
 
 // RunOptions contains the command line arguments for this command
 type RunOptions struct {
 	boot.BootOptions
-	KindResolver factory.KindResolver
-	Gitter       gits.Gitter
-	ChartName    string
-	GitUserName  string
-	GitToken     string
-	BatchMode    bool
-	JobMode      bool
+	KindResolver         factory.KindResolver
+	BootJob              reqhelpers.BootJobOptions
+	Proxy                common.ProxyOptions
+	TLS                  common.TLSOptions
+	Uninstall            common.UninstallOptions
+	Gitter               gits.Gitter
+	Cmd                  *cobra.Command
+	ChartName            string
+	GitUserName          string
+	GitToken             string
+	RequirementsGit      string
+	RequirementsRef      string
+	RequirementsFiles    []string
+	VersionsGitUser      string
+	VersionsGitToken     string
+	ConfigFile           string
+	AuditConfigMap       string
+	LogsBucket           string
+	Pushgateway          string
+	TraceFile            string
+	LockFile             string
+	AllowedGitHosts      string
+	OutputDir            string
+	RegistryServer       string
+	RegistryUsername     string
+	RegistryPassword     string
+	GitRepo              string
+	GitRepoKind          string
+	GitHost              string
+	CompletionCheck      string
+	WorkDir              string
+	InstallerDir         string
+	CaptureOutput        string
+	WatchInterval        time.Duration
+	WatchDebounce        time.Duration
+	PodPollInterval      time.Duration
+	Since                time.Duration
+	CRDTimeout           time.Duration
+	JobRetryBackoff      time.Duration
+	WaitCRDs             []string
+	JobBackoffLimit      int
+	JobRetries           int
+	MaxLogBytes          int64
+	BatchMode            bool
+	JobMode              bool
+	Watch                bool
+	NoTail               bool
+	DryRun               bool
+	ShowCommand          bool
+	EmitEvents           bool
+	CreatePullSecret     bool
+	Lock                 bool
+	UseLock              bool
+	IfChanged            bool
+	SkipRBACCheck        bool
+	RequireConsistent    bool
+	ShowRequirementsDiff bool
+	StrictVersions       bool
+
+	// CapturedOutput the combined output of the boot command and the boot Job logs when CaptureOutput is specified
+	CapturedOutput string
+
+	completionCheck *reqhelpers.CompletionCheck
+	configApplied   bool
+	skipped         bool
+	bootGitURL      string
+	gitURLMirrors   []string
+	allowedGitHosts []string
+	runID           string
+	bootGitCommit   string
+	clusterName     string
+	timer           *common.BootTimer
 }
 
 var (
@@ -57,11 +137,35 @@ var (
 
 		# runs the boot Job to upgrade a cluster from the latest in git
 		%s run 
+
+		# passes additional arguments to the helm command which creates the boot Job
+		%s run -- --timeout 20m
 `)
 )
 
 const (
 	defaultChartName = "jx-labs/jxl-boot"
+
+	// jobPollInterval the interval to poll the boot Job status when we cannot view its logs
+	jobPollInterval = 10 * time.Second
+
+	// jobPodTimeout the maximum time we wait for the boot Job pod to start when using a custom poll interval
+	jobPodTimeout = 30 * time.Minute
+
+	// completionCheckTimeout the maximum time to wait for a custom completion check to pass once the boot Job pod has finished
+	completionCheckTimeout = 30 * time.Minute
+
+	// crdPollInterval the interval to poll the CRDs while waiting for them to be established
+	crdPollInterval = 2 * time.Second
+
+	// logsUploadTimeout the maximum time to spend uploading the boot Job logs to the logs bucket
+	logsUploadTimeout = 5 * time.Minute
+
+	// maxDiagnosisLogBytes the maximum amount of the end of the boot Job log output we scan for known failures
+	maxDiagnosisLogBytes = 1024 * 1024
+
+	// maxDiagnosisLogLines the maximum number of lines at the end of the boot Job log output we load to scan for known failures
+	maxDiagnosisLogLines = 10000
 )
 
 // NewCmdRun creates the new command
@@ -71,23 +175,39 @@ func NewCmdRun() *cobra.Command {
 		Use:     "run",
 		Short:   "boots up Jenkins and/or Jenkins X in a Kubernetes cluster using GitOps by triggering a Kubernetes Job inside the cluster",
 		Long:    stepCustomPipelineLong,
-		Example: fmt.Sprintf(stepCustomPipelineExample, common.BinaryName, common.BinaryName),
+		Example: fmt.Sprintf(stepCustomPipelineExample, common.BinaryName, common.BinaryName, common.BinaryName),
 		Run: func(command *cobra.Command, args []string) {
 			common.SetLoggingLevel(command, args)
-			err := options.Run()
+			err := options.setExtraArgs(command.ArgsLenAtDash(), args)
+			if err == nil {
+				err = options.Run()
+			}
 			helper.CheckErr(err)
 		},
 	}
 	command.Flags().StringVarP(&options.Dir, "dir", "d", ".", "the directory to look for the Jenkins X Pipeline, requirements and charts")
-	command.Flags().StringVarP(&options.GitURL, "git-url", "u", "", "override the Git clone URL for the JX Boot source to start from, ignoring the versions stream. Normally specified with git-ref as well")
+	command.Flags().StringVarP(&options.GitURL, "git-url", "u", "", "override the Git clone URL for the JX Boot source to start from, ignoring the versions stream. Normally specified with git-ref as well. Comma separated URLs of mirrors of the repository are tried in order until one can be cloned")
+	command.Flags().StringVarP(&options.GitRepo, "git-repo", "", "", "the development git repository of the form owner/name to use instead of --git-url")
+	command.Flags().StringVarP(&options.GitRepoKind, "git-kind", "", gits.KindGitHub, "the kind of git provider hosting --git-repo. Possible values: github, gitlab")
+	command.Flags().StringVarP(&options.GitHost, "git-host", "", "", "the host of a self hosted git provider for --git-repo such as github.mycompany.com. Defaults to github.com or gitlab.com")
 	command.Flags().StringVarP(&options.GitUserName, "git-user", "", "", "specify the git user name to clone the development git repository. If not specified it is found from the secrets at $JX_SECRETS_YAML")
 	command.Flags().StringVarP(&options.GitToken, "git-token", "", "", "specify the git token to clone the development git repository. If not specified it is found from the secrets at $JX_SECRETS_YAML")
 	command.Flags().StringVarP(&options.GitRef, "git-ref", "", "master", "override the Git ref for the JX Boot source to start from, ignoring the versions stream. Normally specified with git-url as well")
 	command.Flags().StringVarP(&options.ChartName, "chart", "c", defaultChartName, "the chart name to use to install the boot Job")
 	command.Flags().StringVarP(&options.VersionStreamURL, "versions-repo", "", common.DefaultVersionsURL, "the bootstrap URL for the versions repo. Once the boot config is cloned, the repo will be then read from the jx-requirements.yml")
 	command.Flags().StringVarP(&options.VersionStreamRef, "versions-ref", "", common.DefaultVersionsRef, "the bootstrap ref for the versions repo. Once the boot config is cloned, the repo will be then read from the jx-requirements.yml")
+	command.Flags().StringVarP(&options.VersionsGitUser, "versions-git-user", "", os.Getenv("JX_VERSIONS_GIT_USER"), "the git user name to clone a private versions repo. Defaults to $JX_VERSIONS_GIT_USER or the --git-user value")
+	command.Flags().StringVarP(&options.VersionsGitToken, "versions-git-token", "", os.Getenv("JX_VERSIONS_GIT_TOKEN"), "the git token to clone a private versions repo. Defaults to $JX_VERSIONS_GIT_TOKEN")
 	command.Flags().StringVarP(&options.HelmLogLevel, "helm-log", "v", "", "sets the helm logging level from 0 to 9. Passed into the helm CLI via the '-v' argument. Useful to diagnose helm related issues")
-	command.Flags().StringVarP(&options.RequirementsFile, "requirements", "r", "", "requirements file which will overwrite the default requirements file")
+	command.Flags().StringVarP(&options.AllowedGitHosts, "allowed-git-hosts", "", "", "comma separated git hosts such as github.com,gitlab.example.com which the boot, requirements and version stream git URLs must be on. If not specified any host is allowed")
+	command.Flags().StringVarP(&options.RequirementsGit, "requirements-git-url", "", "", "the git URL of a repository containing the jx-requirements.yml to use rather than the requirements of the boot configuration")
+	command.Flags().StringVarP(&options.RequirementsRef, "requirements-git-ref", "", "", "the git ref of the --requirements-git-url repository. If not specified the default branch is used")
+	command.Flags().StringVarP(&options.KindResolver.JXNamespace, "jx-namespace", "", "", "the namespace to find the dev Environment in if it is not the current namespace. The boot Secrets are still found in the current namespace")
+	command.Flags().BoolVarP(&options.KindResolver.IgnoreDevEnvironment, "ignore-dev-environment", "", false, "never loads the requirements or git URL from the dev Environment so that only the --git-url option and the jx-requirements.yml in --dir are used")
+	command.Flags().BoolVarP(&options.KindResolver.KMS, "kms", "", false, "uses the KMS key configured for vault in the jx-requirements.yml to decrypt the local Secret when verifying the secrets before the boot Job is launched if they were encrypted via 'secrets edit --kms'. The boot Job decrypts them itself via 'secrets yaml'")
+	command.Flags().StringVarP(&options.KindResolver.KMSKey, "kms-key", "", "", "the Google Cloud KMS key resource name or AWS KMS key ID, alias or ARN the secrets in the local Secret are encrypted with. Implies --kms")
+	options.KindResolver.AddImpersonationFlags(command)
+	command.Flags().StringVarP(&options.ConfigFile, "config", "", "", "the configuration file used to default the command line arguments. If not specified the "+bootconfig.FileName+" file in the current directory is used if it exists")
 
 	defaultBatchMode := false
 	if os.Getenv("JX_BATCH_MODE") == "true" {
@@ -97,14 +217,215 @@ func NewCmdRun() *cobra.Command {
 
 	command.Flags().BoolVarP(&options.JobMode, "job", "", false, "if running inside the cluster lets still default to creating the boot Job rather than running boot locally")
 
+	command.Flags().BoolVarP(&options.StrictVersions, "strict-versions", "", false, "fails rather than warns if the version stream is missing versions of charts used by the requirements")
+	command.Flags().StringArrayVarP(&options.RequirementsFiles, "requirements", "r", nil, "a requirements YAML file to deep merge on top of the requirements such as an environment overlay. Can be specified multiple times in which case later files override earlier ones")
+	command.Flags().BoolVarP(&options.ShowRequirementsDiff, "show-requirements-diff", "", false, "logs a diff of the requirements before and after applying each override such as --requirements-git-url or --requirements before creating the boot Job")
+	command.Flags().StringVarP(&options.LogsBucket, "logs-bucket", "", "", "the bucket URL such as gs://mybucket or s3://mybucket to upload the boot Job logs to once it completes. The logs are stored in a folder for the cluster name and time")
+	command.Flags().BoolVarP(&options.IfChanged, "if-changed", "", false, "skips the boot if the last successful boot was of the same git URL and commit and the dev Environment is healthy so that it is safe to run repeatedly")
+	command.Flags().BoolVarP(&options.NoTail, "no-tail", "", false, "creates the boot Job then returns immediately without tailing its logs or waiting for it to complete")
+	command.Flags().BoolP(opts.OptionVerbose, "", false, "enables verbose logging. Can also be enabled via the $JX_VERBOSE environment variable or the verbose setting of the configuration file")
+	command.Flags().BoolP(common.OptionQuiet, "q", false, "only logs warnings, errors and the final result while still streaming the boot Job logs. Can also be enabled via the $JX_QUIET environment variable")
+	command.Flags().BoolVarP(&options.Watch, "watch", "", false, "after booting keep polling the git repository and re-run the boot Job whenever the git ref changes")
+	command.Flags().Int64VarP(&options.MaxLogBytes, "max-log-bytes", "", 0, "the maximum size of the boot Job logs saved via --capture-output, --logs-bucket or --output-dir. Larger logs are truncated keeping the end, or the start for --output-dir as its log file is written as the logs stream. Streaming the logs is unaffected. If zero there is no limit")
+	command.Flags().StringVarP(&options.OutputDir, "output-dir", "", "", "the directory to write the boot Job logs to. The logs of each boot run are appended to a single boot-<run>.log file with a separator each time a new pod is attached to")
+	command.Flags().StringVarP(&options.CaptureOutput, "capture-output", "", "", "the file to save the combined output of the helm command and the boot Job logs to in addition to streaming the logs")
+	command.Flags().StringVarP(&options.InstallerDir, "installer-dir", "", "", "a local directory containing the boot installer chart to use rather than the released chart. Useful when developing the chart itself")
+	command.Flags().StringVarP(&options.WorkDir, "work-dir", "", "", "the directory to clone the development git repository into rather than a temporary directory. Any existing clone in the directory is fetched and reused on the next run")
+	command.Flags().StringVarP(&options.CompletionCheck, "completion-check", "", "", "a custom check to decide when the boot has completed instead of the boot Job status. Either a command prefixed with 'cmd:' which must succeed or a condition of the form kind/name[@namespace]=Condition on a deployment, job or pod such as 'deployment/jenkins=Available'")
+	command.Flags().DurationVarP(&options.CRDTimeout, "crd-timeout", "", 5*time.Minute, "the maximum time to wait for the --wait-crd CRDs to be established before creating the boot Job. If zero the CRDs are not checked")
+	command.Flags().StringArrayVarP(&options.WaitCRDs, "wait-crd", "", clienthelpers.DefaultBootCRDs, "the name of a CRD to wait for to be established before creating the boot Job if it exists. Can be specified multiple times")
+	command.Flags().DurationVarP(&options.Since, "since", "", 0, "when attaching to a boot Job which is already running shows the logs from this long ago such as 10m before following them. If not specified the logs are followed from the current point")
+	command.Flags().DurationVarP(&options.PodPollInterval, "pod-poll-interval", "", 0, "the interval such as 5s to poll for the boot Job pod to start. If not specified the jx default is used")
+	command.Flags().DurationVarP(&options.WatchInterval, "watch-interval", "", time.Minute, "the interval to poll the git repository for changes when using --watch")
+	command.Flags().DurationVarP(&options.WatchDebounce, "watch-debounce", "", 15*time.Second, "how long the git ref must be unchanged before re-running the boot Job when using --watch so that rapid pushes only trigger one boot")
+
+	command.Flags().StringVarP(&options.BootJob.StartStep, "start-step", "", "", "the step in the boot pipeline to start from so that a failed boot can be resumed. Possible values: "+strings.Join(reqhelpers.KnownBootSteps, ", "))
+	command.Flags().StringVarP(&options.BootJob.EndStep, "end-step", "", "", "the step in the boot pipeline to end at. Possible values: "+strings.Join(reqhelpers.KnownBootSteps, ", "))
+	command.Flags().IntVarP(&options.JobBackoffLimit, "job-backoff-limit", "", 0, "the number of retries of the boot Job before it is marked as failed. If not specified the chart default is used")
+	command.Flags().IntVarP(&options.JobRetries, "job-retries", "", 0, "the number of times to delete and re-run the whole boot if the boot Job fails such as due to transient cloud API errors. This is in addition to the retries of the Job itself via --job-backoff-limit")
+	command.Flags().DurationVarP(&options.JobRetryBackoff, "job-retry-backoff", "", 30*time.Second, "the time to wait before the first retry via --job-retries which doubles for each subsequent retry")
+	command.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "logs the helm command and the --job-retries plan without changing anything such as deleting or creating the boot Job, creating Events or recording the audit")
+	command.Flags().BoolVarP(&options.ShowCommand, "show-command", "", false, "prints just the helm command which would create the boot Job with any credentials redacted and exits without running anything so it can be copied or used in other scripts")
+	command.Flags().StringVarP(&options.BootJob.RestartPolicy, "job-restart-policy", "", "", "the restartPolicy of the boot Job pod. Possible values: "+strings.Join(reqhelpers.JobRestartPolicies, ", ")+". With Never the boot is only attempted once unless --job-backoff-limit is specified. If not specified the chart default is used")
+	command.Flags().StringVarP(&options.BootJob.CPURequest, "job-cpu", "", "", "the CPU request of the boot Job such as 500m. If not specified the chart default is used")
+	command.Flags().StringVarP(&options.BootJob.MemoryRequest, "job-memory", "", "", "the memory request of the boot Job such as 512Mi. If not specified the chart default is used")
+	command.Flags().StringVarP(&options.BootJob.CPULimit, "job-cpu-limit", "", "", "the CPU limit of the boot Job such as 2. If not specified the chart default is used")
+	command.Flags().StringVarP(&options.BootJob.MemoryLimit, "job-memory-limit", "", "", "the memory limit of the boot Job such as 2Gi. If not specified the chart default is used")
+	command.Flags().DurationVarP(&options.BootJob.Deadline, "job-deadline", "", 0, "the maximum duration such as 2h the boot Job may be active for before it is terminated. If not specified the chart default is used")
+	command.Flags().StringArrayVarP(&options.BootJob.Env, "job-env", "", nil, "an additional environment variable of the form KEY=VALUE to pass into the boot Job container. Can be specified multiple times")
+	command.Flags().StringArrayVarP(&options.BootJob.EnvFromSecret, "job-env-from-secret", "", nil, "an additional environment variable of the form KEY=SECRET_NAME:SECRET_KEY populated from an existing Secret in the boot Job container. Can be specified multiple times")
+	command.Flags().StringVarP(&options.BootJob.Image, "boot-image", "", "", "overrides the image repository of the boot Job such as when testing a fix. If not specified the image from the version stream is used")
+	command.Flags().StringArrayVarP(&options.BootJob.Set, "set", "", nil, "an additional value of the form key=value for the boot Job chart which helm may convert to a number or boolean. Can be specified multiple times")
+	command.Flags().StringArrayVarP(&options.BootJob.SetString, "set-string", "", nil, "an additional value of the form key=value for the boot Job chart which is always treated as a string such as a numeric looking token. Can be specified multiple times")
+	command.Flags().StringArrayVarP(&options.BootJob.Annotations, "job-annotation", "", nil, "an additional annotation of the form key=value for the boot Job which overrides any --gitops-controller annotation. Can be specified multiple times")
+	command.Flags().StringVarP(&options.BootJob.GitOpsController, "gitops-controller", "", "", "adds the annotations for the GitOps controller running helmboot to the boot Job such as ArgoCD sync hooks. Possible values: "+strings.Join(reqhelpers.GitOpsControllers, ", "))
+	command.Flags().StringArrayVarP(&options.BootJob.ImagePullSecrets, "job-image-pull-secret", "", nil, "the name of a docker registry Secret used to pull the boot image from a private registry. Can be specified multiple times")
+	command.Flags().BoolVarP(&options.CreatePullSecret, "create-image-pull-secret", "", false, "creates or updates the first --job-image-pull-secret from the --registry-server, --registry-username and --registry-password before launching the boot Job")
+	command.Flags().StringVarP(&options.RegistryServer, "registry-server", "", "", "the docker registry server such as myregistry.example.com used by --create-image-pull-secret")
+	command.Flags().StringVarP(&options.RegistryUsername, "registry-username", "", "", "the docker registry user name used by --create-image-pull-secret")
+	command.Flags().StringVarP(&options.RegistryPassword, "registry-password", "", "", "the docker registry password or token used by --create-image-pull-secret")
+	command.Flags().StringVarP(&options.BootJob.ImageTag, "boot-image-tag", "", "", "overrides the image tag of the boot Job such as when testing a fix. If not specified the image tag from the version stream is used")
+	command.Flags().BoolVarP(&options.EmitEvents, "emit-events", "", false, "creates Kubernetes Events against the boot Job as the boot progresses so it can be followed via 'kubectl get events'")
+	command.Flags().BoolVarP(&options.Lock, "lock", "", false, "resolves the --git-ref to its commit SHA, boots that exact commit and records it in the --lock-file so the boot can be reproduced via --use-lock")
+	command.Flags().BoolVarP(&options.UseLock, "use-lock", "", false, "boots the exact commit recorded in the --lock-file by a previous run with --lock")
+	command.Flags().StringVarP(&options.LockFile, "lock-file", "", reqhelpers.BootLockFile, "the lock file used by --lock and --use-lock")
+	command.Flags().StringVarP(&options.TraceFile, "trace", "", "", "writes a timestamped trace of each step, Kubernetes API call, command and decision taken to the given file as JSON lines for attaching to bug reports. Tokens and passwords are redacted")
+	command.Flags().StringVarP(&options.Pushgateway, "pushgateway", "", "", "the URL of a Prometheus Pushgateway such as http://pushgateway:9091 to push the boot duration, phase durations and outcome metrics to after each boot")
+	command.Flags().StringVarP(&options.AuditConfigMap, "audit-configmap", "", "", "the name of a ConfigMap to append an audit event to each time the boot Job is run")
+	command.Flags().BoolVarP(&options.RequireConsistent, "require-consistent", "", false, "fails if the local jx-requirements.yml and the dev Environment disagree on the cluster name, provider or git URL")
+	command.Flags().BoolVarP(&options.SkipRBACCheck, "skip-rbac-check", "", false, "skips verifying the current identity has the RBAC permissions to run the boot Job before starting")
+
+	options.Proxy.AddFlags(command)
+	options.TLS.AddFlags(command)
+	options.Uninstall.AddFlags(command)
+
+	command.AddCommand(common.SplitCommand(NewCmdManifest()))
+	command.AddCommand(common.SplitCommand(NewCmdBOM()))
+	command.AddCommand(common.SplitCommand(NewCmdRollback()))
+	command.AddCommand(common.SplitCommand(NewCmdLint()))
+	command.AddCommand(common.SplitCommand(NewCmdLogs()))
+
+	options.Cmd = command
 	return command
 }
 
 // Run implements the command
 func (o *RunOptions) Run() error {
+	if o.TraceFile != "" {
+		stopTrace, err := common.StartTrace(o.TraceFile)
+		if err != nil {
+			return err
+		}
+		defer stopTrace()
+		common.Tracef(common.TraceStep, "running %s run %s", common.BinaryName, strings.Join(os.Args[1:], " "))
+	}
+	err := o.applyConfigFile()
+	if err != nil {
+		return err
+	}
+	err = clienthelpers.ValidateImpersonation(o.KindResolver.AsUser, o.KindResolver.AsGroups)
+	if err != nil {
+		return err
+	}
+	err = o.Uninstall.Validate()
+	if err != nil {
+		return err
+	}
+	if o.TLS.InsecureSkipVerify && o.AllowedGitHosts != "" {
+		return errors.Errorf("cannot specify both --insecure-skip-tls-verify and --allowed-git-hosts as the git hosts cannot be trusted without TLS verification")
+	}
+	if o.RequirementsRef != "" && o.RequirementsGit == "" {
+		return util.MissingOption("requirements-git-url")
+	}
+	if o.GitRepo != "" {
+		if o.GitURL != "" {
+			return errors.Errorf("cannot specify both --git-url and --git-repo")
+		}
+		o.GitURL, err = githelpers.RepositoryCloneURL(o.GitRepoKind, o.GitHost, o.GitRepo)
+		if err != nil {
+			return err
+		}
+	}
+	if strings.Contains(o.GitURL, ",") {
+		o.gitURLMirrors, err = githelpers.SplitGitURLs(o.GitURL)
+		if err != nil {
+			return err
+		}
+		o.GitURL = o.gitURLMirrors[0]
+	}
+	err = o.verifyAllowedGitHostOptions()
+	if err != nil {
+		return err
+	}
+	if o.BootJob.GitRef == "" {
+		o.BootJob.GitRef = o.GitRef
+	}
+	if reqhelpers.FlagChanged(o.Cmd, "job-backoff-limit") {
+		o.BootJob.BackoffLimit = &o.JobBackoffLimit
+	} else if o.BootJob.RestartPolicy == string(corev1.RestartPolicyNever) {
+		// without an explicit backoff limit a Never restart policy means a single attempt
+		o.JobBackoffLimit = 0
+		o.BootJob.BackoffLimit = &o.JobBackoffLimit
+	}
+	err = clienthelpers.ValidateLogsBucket(o.LogsBucket)
+	if err != nil {
+		return err
+	}
+	if o.Pushgateway != "" && !strings.HasPrefix(o.Pushgateway, "http://") && !strings.HasPrefix(o.Pushgateway, "https://") {
+		return util.InvalidOptionf("pushgateway", o.Pushgateway, "the Pushgateway URL must start with http:// or https://")
+	}
+	err = o.validateImagePullSecretOptions()
+	if err != nil {
+		return err
+	}
+	if o.Lock && o.UseLock {
+		return errors.Errorf("cannot specify both --lock and --use-lock")
+	}
+	if o.KindResolver.IgnoreDevEnvironment && o.RequireConsistent {
+		return errors.Errorf("cannot specify both --ignore-dev-environment and --require-consistent as the consistency check compares the requirements with the dev Environment")
+	}
+	if o.ShowCommand && o.Watch {
+		return errors.Errorf("cannot specify both --show-command and --watch")
+	}
+	if o.Watch && (o.Lock || o.UseLock) {
+		return errors.Errorf("cannot specify --watch with --lock or --use-lock as the watch follows the git ref rather than a pinned commit")
+	}
+	if o.MaxLogBytes < 0 {
+		return util.InvalidOptionf("max-log-bytes", strconv.FormatInt(o.MaxLogBytes, 10), "the size must not be negative")
+	}
+	if o.CRDTimeout < 0 {
+		return util.InvalidOptionf("crd-timeout", o.CRDTimeout.String(), "the timeout must not be negative")
+	}
+	if o.JobRetries < 0 {
+		return util.InvalidOptionf("job-retries", strconv.Itoa(o.JobRetries), "the number of retries must not be negative")
+	}
+	if o.JobRetryBackoff < 0 {
+		return util.InvalidOptionf("job-retry-backoff", o.JobRetryBackoff.String(), "the backoff must not be negative")
+	}
+	if o.Since < 0 {
+		return util.InvalidOptionf("since", o.Since.String(), "the duration must not be negative")
+	}
+	if o.PodPollInterval < 0 {
+		return util.InvalidOptionf("pod-poll-interval", o.PodPollInterval.String(), "the interval must not be negative")
+	}
+	if o.CompletionCheck != "" {
+		o.completionCheck, err = reqhelpers.ParseCompletionCheck(o.CompletionCheck)
+		if err != nil {
+			return err
+		}
+	}
+	err = o.BootJob.Validate()
+	if err != nil {
+		return err
+	}
+	if o.InstallerDir != "" {
+		o.InstallerDir, err = reqhelpers.VerifyInstallerDir(o.InstallerDir)
+		if err != nil {
+			return err
+		}
+	}
+	err = o.Proxy.Apply()
+	if err != nil {
+		return err
+	}
+	err = o.TLS.Apply()
+	if err != nil {
+		return err
+	}
 	o.KindResolver.Dir = o.Dir
+	o.KindResolver.WorkDir = o.WorkDir
+	o.KindResolver.GitRef = o.BootJob.GitRef
+	o.KindResolver.BatchMode = o.BatchMode
+	o.KindResolver.Gitter = o.Git()
+	if o.ShowCommand {
+		return o.showCommand()
+	}
 	if (o.JobMode || !clienthelpers.IsInCluster()) && os.Getenv("JX_DEBUG_JOB") != "true" {
-		return o.RunBootJob()
+		err = o.RunBootJob()
+		if err != nil || !o.Watch {
+			return err
+		}
+		return o.watch()
 	}
 	bo := &o.BootOptions
 	if bo.CommonOptions == nil {
@@ -112,10 +433,32 @@ func (o *RunOptions) Run() error {
 		bo.CommonOptions = opts.NewCommonOptionsWithTerm(f, os.Stdin, os.Stdout, os.Stderr)
 		bo.BatchMode = o.BatchMode
 	}
-	err := o.addUserPasswordForPrivateGitClone(true)
+	if len(o.RequirementsFiles) > 1 {
+		return errors.Errorf("only one --requirements file can be specified when running boot inside the cluster as it replaces the requirements rather than merging them")
+	}
+	bo.SetGit(o.Git())
+	bo.StartStep = o.BootJob.StartStep
+	bo.EndStep = o.BootJob.EndStep
+	if len(o.RequirementsFiles) == 1 {
+		bo.RequirementsFile = o.RequirementsFiles[0]
+	}
+
+	versionsURL, err := o.versionsCloneURL(o.VersionStreamURL)
 	if err != nil {
 		return err
 	}
+	err = githelpers.VerifyRemoteRef(versionsURL, o.VersionStreamRef)
+	if err != nil {
+		return errors.Wrap(err, "invalid --versions-ref")
+	}
+	err = o.addUserPasswordForPrivateGitClone(true)
+	if err != nil {
+		return err
+	}
+	err = githelpers.VerifyRemoteRef(o.GitURL, o.GitRef)
+	if err != nil {
+		return errors.Wrap(err, "invalid --git-ref")
+	}
 	err = o.verifySecretsYAML()
 	if err != nil {
 		return err
@@ -123,75 +466,466 @@ func (o *RunOptions) Run() error {
 	return bo.Run()
 }
 
-// RunBootJob runs the boot installer Job
+// setExtraArgs uses any arguments after '--' as additional arguments of the boot command
+func (o *RunOptions) setExtraArgs(argsLenAtDash int, args []string) error {
+	if argsLenAtDash < 0 {
+		if len(args) > 0 {
+			return errors.Errorf("unexpected arguments %s. To pass arguments to the boot command specify them after '--'", strings.Join(args, " "))
+		}
+		return nil
+	}
+	if argsLenAtDash > 0 {
+		return errors.Errorf("unexpected arguments %s before '--'", strings.Join(args[:argsLenAtDash], " "))
+	}
+	o.BootJob.ExtraArgs = args[argsLenAtDash:]
+	return nil
+}
+
+// applyConfigFile defaults any command line arguments which were not explicitly specified from the optional configuration file
+func (o *RunOptions) applyConfigFile() error {
+	if o.configApplied {
+		return nil
+	}
+	o.configApplied = true
+	cfg, fileName, err := bootconfig.LoadConfig(o.ConfigFile, o.Dir)
+	if err != nil {
+		return err
+	}
+	if fileName == "" {
+		return nil
+	}
+	log.Logger().Debugf("loaded configuration from %s", fileName)
+
+	o.defaultFromConfig("git-url", &o.GitURL, cfg.GitURL)
+	o.defaultFromConfig("git-ref", &o.GitRef, cfg.GitRef)
+	o.defaultFromConfig("versions-repo", &o.VersionStreamURL, cfg.VersionsRepo)
+	o.defaultFromConfig("versions-ref", &o.VersionStreamRef, cfg.VersionsRef)
+	o.defaultFromConfig("chart", &o.ChartName, cfg.Chart)
+	if o.KindResolver.Kind == "" {
+		o.KindResolver.Kind = cfg.SecretKind
+	}
+	if cfg.BatchMode != nil && !reqhelpers.FlagChanged(o.Cmd, "batch-mode") && os.Getenv("JX_BATCH_MODE") == "" {
+		o.BatchMode = *cfg.BatchMode
+	}
+	return nil
+}
+
+// defaultFromConfig sets the value from the configuration file if the flag was not specified on the command line
+func (o *RunOptions) defaultFromConfig(flagName string, value *string, configValue string) {
+	if configValue != "" && !reqhelpers.FlagChanged(o.Cmd, flagName) {
+		*value = configValue
+	}
+}
+
+// bootGitRef returns the git ref the boot Job boots which defaults to master
+func (o *RunOptions) bootGitRef() string {
+	if o.BootJob.GitRef != "" {
+		return o.BootJob.GitRef
+	}
+	if o.GitRef != "" {
+		return o.GitRef
+	}
+	return "master"
+}
+
+// RunBootJob runs the boot installer Job recording the outcome in the audit ConfigMap if one is configured. If the
+// boot Job fails it is deleted and the whole boot is retried up to the configured number of retries
 func (o *RunOptions) RunBootJob() error {
+	o.CapturedOutput = ""
+	o.runID = time.Now().UTC().Format("20060102-150405")
+	o.BootJob.RunID = o.runID
+	common.SetUserAgentRunID(o.runID)
+	log.Logger().Infof("starting boot run %s", util.ColorInfo(o.runID))
+	delays := reqhelpers.RetryDelays(o.JobRetries, o.JobRetryBackoff)
+	attempts := len(delays) + 1
+	var errs []error
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			log.Logger().Infof("retrying the boot: attempt %d of %d", attempt, attempts)
+		}
+		err := o.runBootJobAttempt()
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+		if attempt >= attempts || !reqhelpers.IsJobFailed(err) {
+			return reqhelpers.AttemptsError(errs)
+		}
+		delay := delays[attempt-1]
+		log.Logger().Warnf("attempt %d of %d of the boot failed: %s", attempt, attempts, err.Error())
+		log.Logger().Infof("retrying the boot in %s which replaces the failed boot Job", util.ColorInfo(delay.String()))
+		time.Sleep(delay)
+	}
+}
+
+// startPhase starts timing the named phase of the boot and records it in the trace
+func (o *RunOptions) startPhase(name string) {
+	o.timer.StartPhase(name)
+	common.Tracef(common.TraceStep, "starting the %s phase of the boot", name)
+}
+
+// runBootJobAttempt runs the boot installer Job once recording the outcome
+func (o *RunOptions) runBootJobAttempt() error {
+	o.skipped = false
+	o.timer = common.NewBootTimer()
+	err := o.runBootJob()
+	o.timer.Stop()
+	if err != nil {
+		common.Tracef(common.TraceStep, "the boot failed after %s: %s", o.timer.Duration.String(), err.Error())
+	} else {
+		common.Tracef(common.TraceStep, "the boot completed after %s", o.timer.Duration.String())
+	}
+	// a dry run does not change anything so there is nothing to record
+	if !o.skipped && !o.DryRun {
+		o.auditBootJob(err)
+		o.pushMetrics(err)
+		if err != nil {
+			o.emitBootEvent(corev1.EventTypeWarning, "Failed", "the boot failed: "+githelpers.RedactURLs(err.Error()))
+		} else if !o.NoTail {
+			o.emitBootEvent(corev1.EventTypeNormal, "Succeeded", "the boot succeeded")
+		}
+	}
+	if err == nil && !o.skipped && !o.NoTail {
+		o.saveBootRecord()
+	}
+	if o.CaptureOutput != "" {
+		saveErr := ioutil.WriteFile(o.CaptureOutput, []byte(o.CapturedOutput), util.DefaultFileWritePermissions)
+		if saveErr != nil {
+			log.Logger().Warnf("failed to save the captured output to %s: %s", o.CaptureOutput, saveErr.Error())
+		}
+	}
+	return err
+}
+
+// waitForCRDs waits for any of the CRDs the boot Job depends on which exist to be established
+func (o *RunOptions) waitForCRDs() error {
+	if o.CRDTimeout == 0 || len(o.WaitCRDs) == 0 {
+		return nil
+	}
+	if o.DryRun {
+		log.Logger().Infof("would wait up to %s for the CRDs %s to be established", o.CRDTimeout.String(), strings.Join(o.WaitCRDs, ", "))
+		return nil
+	}
+	cfg, err := o.KindResolver.GetFactory().CreateKubeConfig()
+	if err != nil {
+		return errors.Wrap(err, "failed to create the kube config")
+	}
+	client, err := apiextensionsclientset.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to create the API extensions client")
+	}
+	return clienthelpers.WaitForCRDsEstablished(client, o.WaitCRDs, o.CRDTimeout, crdPollInterval)
+}
+
+// captureOutput appends the output to the captured output if it is enabled
+func (o *RunOptions) captureOutput(text string) {
+	if o.CaptureOutput == "" || text == "" {
+		return
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	o.CapturedOutput += text
+}
+
+func (o *RunOptions) runBootJob() error {
 	err := o.detectGitURL()
 	if err != nil {
 		return err
 	}
-	requirements, gitURL, err := reqhelpers.FindRequirementsAndGitURL(o.KindResolver.GetFactory(), o.GitURL, o.Git(), o.Dir)
+	err = githelpers.VerifyAllowedGitHost("git URL", o.GitURL, o.allowedGitHosts)
+	if err != nil {
+		return err
+	}
+	o.emitBootEvent(corev1.EventTypeNormal, "Cloning", "cloning the boot configuration")
+	o.startPhase("clone")
+	requirements, gitURL, err := o.findRequirementsAndGitURLFromMirrors()
 	if err != nil {
 		return err
 	}
 	if gitURL == "" {
 		return util.MissingOption("git-url")
 	}
+	o.clusterName = requirements.Cluster.ClusterName
+
+	err = o.verifyAllowedGitHosts(requirements, gitURL)
+	if err != nil {
+		return err
+	}
+
+	err = o.verifyRequirementsConsistent()
+	if err != nil {
+		return err
+	}
+
+	err = o.verifyRBAC(requirements)
+	if err != nil {
+		return err
+	}
 
 	err = o.addUserPasswordForPrivateGitClone(false)
 	if err != nil {
 		return errors.Wrapf(err, "could not default the git user and token to clone the git URL")
 	}
+	err = githelpers.VerifyRemoteRef(o.GitURL, o.BootJob.GitRef)
+	if err != nil {
+		return errors.Wrap(err, "invalid --git-ref")
+	}
 
-	clusterName := requirements.Cluster.ClusterName
-	log.Logger().Infof("running helmboot Job for cluster %s with git URL %s", util.ColorInfo(clusterName), util.ColorInfo(gitURL))
-
-	log.Logger().Debug("deleting the old jx-boot chart ...")
-	c := util.Command{
-		Name: "helm",
-		Args: []string{"delete", "jx-boot"},
+	err = o.applyLock(gitURL)
+	if err != nil {
+		return err
 	}
-	_, err = c.RunWithoutRetry()
+	err = o.resolveGitCommit(gitURL)
 	if err != nil {
-		log.Logger().Debugf("failed to delete the old jx-boot chart: %s", err.Error())
+		return err
 	}
+	err = o.saveLock(gitURL)
+	if err != nil {
+		return err
+	}
+	if o.IfChanged {
+		upToDate, err := o.isUpToDate()
+		if err != nil {
+			return err
+		}
+		if upToDate {
+			o.skipped = true
+			common.LogResult("the boot is already up to date with commit %s of %s", o.bootGitCommit, githelpers.RedactURLs(o.bootGitURL))
+			return nil
+		}
+	}
+
+	clusterName := requirements.Cluster.ClusterName
+	log.Logger().Infof("running helmboot Job for cluster %s with git URL %s", util.ColorInfo(clusterName), util.ColorInfo(githelpers.RedactURLs(gitURL)))
 
+	if !o.DryRun {
+		o.emitBootEvent(corev1.EventTypeNormal, "Uninstalling", "uninstalling the previous boot Job")
+		o.startPhase("uninstall")
+		log.Logger().Debugf("deleting the old %s chart ...", o.Uninstall.ReleaseName)
+		err = o.Uninstall.Uninstall("", nil, o.Uninstall.ReleaseName)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.startPhase("verify")
 	err = o.verifyBootSecret(requirements)
 	if err != nil {
 		return err
 	}
 
-	// lets add helm repository for jx-labs
-	h := helmer.NewHelmCLI(o.Dir)
-	_, err = helmer.AddHelmRepoIfMissing(h, helmer.LabsChartRepository, "jx-labs", "", "")
+	err = o.waitForCRDs()
 	if err != nil {
-		return errors.Wrap(err, "failed to add Jenkins X Labs chart repository")
+		return err
 	}
-	log.Logger().Infof("updating helm repositories")
-	err = h.UpdateRepo()
+
+	err = o.verifyImagePullSecrets(requirements)
 	if err != nil {
-		log.Logger().Warnf("failed to update helm repositories: %s", err.Error())
+		return err
 	}
 
-	version, err := o.findChartVersion(requirements)
+	c, err := o.bootJobCommand(requirements, gitURL)
 	if err != nil {
 		return err
 	}
 
-	c = reqhelpers.GetBootJobCommand(requirements, gitURL, o.ChartName, version)
+	customImage := o.BootJob.CustomImage()
+	if customImage != "" {
+		log.Logger().Warnf("using the non-default boot image %s so this is not a standard boot", util.ColorWarning(customImage))
+	}
 
-	commandLine := fmt.Sprintf("%s %s", c.Name, strings.Join(c.Args, " "))
+	commandLine := common.RedactTrace(fmt.Sprintf("%s %s", c.Name, strings.Join(c.Args, " ")))
 
+	if o.DryRun {
+		o.skipped = true
+		log.Logger().Infof("would run the command:\n\n%s\n\n", util.ColorInfo(commandLine))
+		log.Logger().Infof("%s", reqhelpers.DescribeRetryPlan(reqhelpers.RetryDelays(o.JobRetries, o.JobRetryBackoff)))
+		return nil
+	}
 	log.Logger().Infof("running the command:\n\n%s\n\n", util.ColorInfo(commandLine))
+	o.emitBootEvent(corev1.EventTypeNormal, "Launching", "launching the boot Job")
+	o.startPhase("launch")
+
+	common.TraceCommand(c)
+	text, err := c.RunWithoutRetry()
+	o.captureOutput(text)
+	if err != nil {
+		return errors.Wrapf(githelpers.RedactError(err), "failed to run command %s", commandLine)
+	}
+
+	if o.NoTail {
+		common.LogResult("the boot Job has been created. You can check its status via: %s", util.ColorInfo("kubectl get job jx-boot"))
+		log.Logger().Infof("and view its logs via: %s", util.ColorInfo("kubectl logs -f job/jx-boot"))
+		return nil
+	}
+	o.startPhase("job")
+	return o.tailJobLogs(clusterName)
+}
+
+// findRequirementsAndGitURLFromMirrors finds the requirements and git URL trying each of the git URL mirrors in order
+// until one can be cloned. The mirror which is used becomes the git URL of the boot Job
+func (o *RunOptions) findRequirementsAndGitURLFromMirrors() (*config.RequirementsConfig, string, error) {
+	if len(o.gitURLMirrors) < 2 {
+		return o.findRequirementsAndGitURL()
+	}
+	var requirements *config.RequirementsConfig
+	gitURL := ""
+	mirror, err := githelpers.TryGitURLs(o.gitURLMirrors, func(mirror string) error {
+		o.GitURL = mirror
+		o.KindResolver.GitURL = mirror
+		var err error
+		requirements, gitURL, err = o.findRequirementsAndGitURL()
+		return err
+	})
+	if err != nil {
+		return requirements, gitURL, err
+	}
+	log.Logger().Infof("using the git mirror %s", util.ColorInfo(githelpers.RedactURLs(mirror)))
+	return requirements, gitURL, nil
+}
+
+// findRequirementsAndGitURL finds the requirements and git URL of the boot configuration. If a requirements git URL
+// is specified the requirements are loaded from that repository instead. Any --requirements files are then merged
+// on top in order
+func (o *RunOptions) findRequirementsAndGitURL() (*config.RequirementsConfig, string, error) {
+	var f jxfactory.Factory
+	if !o.KindResolver.IgnoreDevEnvironment {
+		f = o.KindResolver.GetFactory()
+	}
+	requirements, gitURL, err := reqhelpers.FindRequirementsAndGitURL(f, o.GitURL, o.BootJob.GitRef, o.Git(), o.Dir, o.WorkDir)
+	if err != nil {
+		return requirements, gitURL, err
+	}
+	if o.RequirementsGit == "" && len(o.RequirementsFiles) == 0 {
+		if o.ShowRequirementsDiff {
+			log.Logger().Infof("no requirements overrides specified")
+		}
+		return requirements, gitURL, nil
+	}
+	if o.RequirementsGit != "" {
+		original := requirements
+		requirements, err = reqhelpers.GetRequirementsFromGitRef(o.Git(), o.RequirementsGit, o.RequirementsRef)
+		if err != nil {
+			return requirements, gitURL, errors.Wrapf(err, "failed to get requirements from --requirements-git-url %s", githelpers.RedactURLs(o.RequirementsGit))
+		}
+		err = o.showRequirementsDiff("the requirements overrides", original, requirements)
+		if err != nil {
+			return requirements, gitURL, err
+		}
+	}
+	for _, fileName := range o.RequirementsFiles {
+		original := requirements
+		requirements, err = reqhelpers.MergeRequirementsFile(requirements, fileName)
+		if err != nil {
+			return requirements, gitURL, err
+		}
+		log.Logger().Infof("merged the requirements file %s", util.ColorInfo(fileName))
+		err = o.showRequirementsDiff("the requirements file "+fileName, original, requirements)
+		if err != nil {
+			return requirements, gitURL, err
+		}
+	}
+	if len(o.RequirementsFiles) > 0 && o.ShowRequirementsDiff {
+		data, err := yaml.Marshal(requirements)
+		if err != nil {
+			return requirements, gitURL, errors.Wrap(err, "failed to marshal the merged requirements to YAML")
+		}
+		log.Logger().Infof("the merged requirements are:\n\n%s", string(data))
+	}
+	return requirements, gitURL, nil
+}
+
+// showRequirementsDiff logs the changes the named override made to the requirements if enabled
+func (o *RunOptions) showRequirementsDiff(name string, original *config.RequirementsConfig, requirements *config.RequirementsConfig) error {
+	if !o.ShowRequirementsDiff {
+		return nil
+	}
+	diff, err := reqhelpers.RequirementsDiff(original, requirements)
+	if err != nil {
+		return err
+	}
+	if diff == "" {
+		log.Logger().Infof("%s did not change the requirements", name)
+	} else {
+		log.Logger().Infof("%s made the following changes:\n\n%s", name, diff)
+	}
+	return nil
+}
 
-	_, err = c.RunWithoutRetry()
+// bootJobCommand returns the helm command used to install the boot Job chart
+func (o *RunOptions) bootJobCommand(requirements *config.RequirementsConfig, gitURL string) (util.Command, error) {
+	// lets make sure the boot process inside the Job does not prompt if we are running unattended
+	o.BootJob.BatchMode = o.BatchMode
+	chartName, version, err := o.resolveInstallerChart(requirements)
 	if err != nil {
-		return errors.Wrapf(err, "failed to run command %s", commandLine)
+		return util.Command{}, err
+	}
+	c := reqhelpers.GetBootJobCommand(requirements, gitURL, chartName, version, &o.BootJob)
+	c.Args = append(c.Args, o.TLS.HelmArgs()...)
+	return c, nil
+}
+
+// resolveInstallerChart returns the chart name and version of the boot installer chart adding the chart repository
+// if required. The version is blank when using the local --installer-dir
+func (o *RunOptions) resolveInstallerChart(requirements *config.RequirementsConfig) (string, string, error) {
+	if o.InstallerDir != "" {
+		log.Logger().Infof("using the local installer chart in %s", util.ColorInfo(o.InstallerDir))
+		return o.InstallerDir, "", nil
 	}
 
-	return o.tailJobLogs()
+	if o.DryRun {
+		log.Logger().Infof("would add the %s helm repository and update the helm repositories", util.ColorInfo(helmer.LabsChartRepository))
+	} else {
+		// lets add helm repository for jx-labs
+		h := o.helmClient(o.Dir)
+		_, err := helmer.AddHelmRepoIfMissing(h, helmer.LabsChartRepository, "jx-labs", "", "")
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to add Jenkins X Labs chart repository")
+		}
+		log.Logger().Infof("updating helm repositories")
+		err = h.UpdateRepo()
+		if err != nil {
+			log.Logger().Warnf("failed to update helm repositories: %s", err.Error())
+		}
+	}
+
+	version, err := o.findChartVersion(requirements)
+	if err != nil {
+		return "", "", err
+	}
+	return o.ChartName, version, nil
+}
+
+// helmClient returns the helm client for the given directory configured with the TLS options
+func (o *RunOptions) helmClient(dir string) *helmer.HelmCLI {
+	h := helmer.NewHelmCLI(dir)
+	h.CAFile = o.TLS.CAFile
+	h.InsecureSkipTLSVerify = o.TLS.InsecureSkipVerify
+	return h
+}
+
+// verifyRequirementsConsistent fails if the local requirements and the dev Environment disagree
+func (o *RunOptions) verifyRequirementsConsistent() error {
+	if !o.RequireConsistent {
+		return nil
+	}
+	conflicts, err := reqhelpers.FindRequirementsConflicts(o.KindResolver.GetFactory(), o.GitURL, o.Git(), o.Dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to compare the local requirements with the dev Environment")
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, c := range conflicts {
+		log.Logger().Errorf("%s", util.ColorError(c.String()))
+		messages = append(messages, c.String())
+	}
+	return errors.Errorf("the local requirements and the dev Environment disagree: %s", strings.Join(messages, ", "))
 }
 
-func (o *RunOptions) tailJobLogs() error {
+func (o *RunOptions) tailJobLogs(clusterName string) error {
 	a := jxadapt.NewJXAdapter(o.KindResolver.GetFactory(), o.Git(), o.BatchMode)
 	client, ns, err := o.KindResolver.GetFactory().CreateKubeClient()
 	if err != nil {
@@ -204,34 +938,235 @@ func (o *RunOptions) tailJobLogs() error {
 	}
 	containerName := "boot"
 	podInterface := client.CoreV1().Pods(ns)
+	previousPod := ""
 	for {
 		pod := ""
 		if err != nil {
 			return err
 		}
-		pod, err = co.WaitForReadyPodForSelectorLabels(client, ns, selector, false)
+		if o.PodPollInterval > 0 {
+			pod, err = o.waitForJobPod(client, ns, selector, previousPod)
+		} else {
+			pod, err = co.WaitForReadyPodForSelectorLabels(client, ns, selector, false)
+			if err != nil {
+				err = o.diagnoseImagePull(client, ns, selector, err)
+			}
+		}
 		if err != nil {
+			if clienthelpers.IsForbidden(err) {
+				return o.waitForJobWithoutLogs(client, ns, err)
+			}
 			return err
 		}
 		if pod == "" {
 			return fmt.Errorf("No pod found for namespace %s with selector %v", ns, selector)
 		}
-		err = co.TailLogs(ns, pod, containerName)
+		previousPod = pod
+		if o.OutputDir != "" {
+			err = o.appendPodLogs(podInterface, pod, containerName)
+		} else if o.Since > 0 {
+			err = clienthelpers.FollowPodLogs(podInterface, pod, containerName, o.Since, os.Stdout)
+		} else {
+			err = co.TailLogs(ns, pod, containerName)
+		}
 		if err != nil {
+			if clienthelpers.IsForbidden(err) {
+				return o.waitForJobWithoutLogs(client, ns, err)
+			}
 			return nil
 		}
 		podResource, err := podInterface.Get(pod, metav1.GetOptions{})
 		if err != nil {
 			return errors.Wrapf(err, "failed to get pod %s in namespace %s", pod, ns)
 		}
+		if o.completionCheck != nil {
+			err = o.waitForCompletionCheck(client, ns)
+			o.archiveJobLogs(podInterface, pod, containerName, clusterName)
+			if err != nil {
+				return reqhelpers.WithFailureDiagnosis(err, diagnosisLogs(podInterface, pod, containerName))
+			}
+			return nil
+		}
 		if kube.IsPodCompleted(podResource) {
-			log.Logger().Infof("the Job pod %s has completed successfully", pod)
+			o.archiveJobLogs(podInterface, pod, containerName, clusterName)
+			common.LogResult("the Job pod %s has completed successfully", pod)
 			return nil
 		}
+		if podResource.Status.Phase == corev1.PodFailed && o.BootJob.IsSingleAttempt() {
+			o.archiveJobLogs(podInterface, pod, containerName, clusterName)
+			err = reqhelpers.NewJobFailedError(errors.Errorf("the boot Job pod %s has failed and is not retried as the restart policy is Never with no backoff", pod))
+			return reqhelpers.WithFailureDiagnosis(err, diagnosisLogs(podInterface, pod, containerName))
+		}
 		log.Logger().Warnf("Job pod %s is not completed but has status: %s", pod, kube.PodStatus(podResource))
+
+		err = o.verifyJobNotFailed(client, ns)
+		if err != nil {
+			o.archiveJobLogs(podInterface, pod, containerName, clusterName)
+			return reqhelpers.WithFailureDiagnosis(err, diagnosisLogs(podInterface, pod, containerName))
+		}
 	}
 }
 
+// appendPodLogs follows the logs of the pod appending them to the log file of the boot run as well as the console so
+// that there is a single log file for the boot run even if the boot Job pod is restarted
+func (o *RunOptions) appendPodLogs(podInterface typedcorev1.PodInterface, pod string, containerName string) error {
+	fileName := clienthelpers.RunLogFileName(o.OutputDir, o.runID)
+	f, err := clienthelpers.OpenRunLogFile(fileName, pod, time.Now())
+	if err != nil {
+		log.Logger().Warnf("failed to write the boot Job logs to the output directory: %s", err.Error())
+		return clienthelpers.FollowPodLogs(podInterface, pod, containerName, o.Since, os.Stdout)
+	}
+	defer f.Close()
+	written := int64(0)
+	info, err := f.Stat()
+	if err == nil {
+		written = info.Size()
+	}
+	log.Logger().Infof("appending the logs of pod %s to %s", util.ColorInfo(pod), util.ColorInfo(fileName))
+	out := io.MultiWriter(os.Stdout, clienthelpers.NewLimitWriter(f, o.MaxLogBytes, written))
+	return clienthelpers.FollowPodLogs(podInterface, pod, containerName, o.Since, out)
+}
+
+// waitForJobPod waits for the newest pod matching the selector to start polling at the configured interval. Older pods
+// are ignored so that we wait for the pod retrying a failed pod to start. The previous pod we attached to is only
+// returned again if it is still running
+func (o *RunOptions) waitForJobPod(client kubernetes.Interface, ns string, selector map[string]string, previousPod string) (string, error) {
+	labelSelector := labels.SelectorFromSet(selector).String()
+	end := time.Now().Add(jobPodTimeout)
+	for {
+		podList, err := client.CoreV1().Pods(ns).List(metav1.ListOptions{
+			LabelSelector: labelSelector,
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to list pods in namespace %s with selector %s", ns, labelSelector)
+		}
+		pod := clienthelpers.NewestPod(podList.Items)
+		if pod != nil {
+			if clienthelpers.IsPodStarting(pod) {
+				failure := clienthelpers.ImagePullFailure(pod)
+				if failure != "" {
+					return "", imagePullError(pod.Name, failure)
+				}
+			} else if pod.Name != previousPod || pod.Status.Phase == corev1.PodRunning {
+				return pod.Name, nil
+			}
+		}
+		if time.Now().After(end) {
+			return "", errors.Errorf("timed out after %s waiting for a pod in namespace %s with selector %s to start", jobPodTimeout.String(), ns, labelSelector)
+		}
+		time.Sleep(o.PodPollInterval)
+	}
+}
+
+// archiveJobLogs captures the log output of the boot Job pod if enabled and uploads it to the logs bucket if one is
+// specified. Any failure is logged rather than failing the boot
+func (o *RunOptions) archiveJobLogs(podInterface typedcorev1.PodInterface, pod string, containerName string, clusterName string) {
+	if o.LogsBucket == "" && o.CaptureOutput == "" {
+		return
+	}
+	logs := reqhelpers.TruncateLogs(jobPodLogs(podInterface, pod, containerName, 0), o.MaxLogBytes)
+	o.captureOutput(logs)
+	if o.LogsBucket == "" {
+		return
+	}
+	runID := o.runID
+	if runID == "" {
+		runID = time.Now().UTC().Format("20060102-150405")
+	}
+	key := clienthelpers.RunLogBucketKey(clusterName, runID)
+	err := buckets.WriteBucket(o.LogsBucket, key, strings.NewReader(logs), logsUploadTimeout)
+	if err != nil {
+		log.Logger().Warnf("failed to upload the boot Job logs to %s: %s", o.LogsBucket, err.Error())
+		return
+	}
+	log.Logger().Infof("uploaded the boot Job logs to %s", util.ColorInfo(strings.TrimSuffix(o.LogsBucket, "/")+"/"+key))
+}
+
+// diagnosisLogs returns the end of the log output of the given pod which is scanned for known failures as the
+// cause of a failure is usually near the end of the log
+func diagnosisLogs(podInterface typedcorev1.PodInterface, pod string, containerName string) string {
+	return reqhelpers.TruncateLogs(jobPodLogs(podInterface, pod, containerName, maxDiagnosisLogLines), maxDiagnosisLogBytes)
+}
+
+// jobPodLogs returns the last given number of lines of the log output of the given pod or all of it if the limit is zero
+func jobPodLogs(podInterface typedcorev1.PodInterface, pod string, containerName string, tailLines int64) string {
+	opts := &corev1.PodLogOptions{
+		Container: containerName,
+	}
+	if tailLines > 0 {
+		opts.TailLines = &tailLines
+	}
+	data, err := podInterface.GetLogs(pod, opts).DoRaw()
+	if err != nil {
+		log.Logger().Debugf("failed to load the logs of pod %s: %s", pod, err.Error())
+		return ""
+	}
+	return string(data)
+}
+
+// waitForJobWithoutLogs polls the status of the boot Job until it completes for when we are not allowed to view the pod logs
+func (o *RunOptions) waitForJobWithoutLogs(client kubernetes.Interface, ns string, cause error) error {
+	log.Logger().Warnf("cannot stream the boot Job logs as the current identity does not have the RBAC permissions to view pods or their logs: %s", cause.Error())
+	log.Logger().Infof("waiting for the boot Job to complete instead. You can view the logs via: %s", util.ColorInfo("kubectl logs -f job/jx-boot -n "+ns))
+	if o.completionCheck != nil {
+		return o.waitForCompletionCheck(client, ns)
+	}
+	err := clienthelpers.WaitForJobComplete(client, ns, "jx-boot", jobPollInterval, func() error {
+		return o.verifyJobNotFailed(client, ns)
+	})
+	if err != nil {
+		return err
+	}
+	common.LogResult("the boot Job has completed successfully")
+	return nil
+}
+
+// waitForCompletionCheck polls the custom completion check until it passes, the boot Job fails or we time out
+func (o *RunOptions) waitForCompletionCheck(client kubernetes.Interface, ns string) error {
+	check := o.completionCheck.String()
+	end := time.Now().Add(completionCheckTimeout)
+	for {
+		complete, err := o.completionCheck.IsComplete(client, ns)
+		if err != nil {
+			return errors.Wrapf(err, "failed to evaluate the completion check %s", check)
+		}
+		if complete {
+			common.LogResult("the completion check %s has passed", check)
+			return nil
+		}
+		err = o.verifyJobNotFailed(client, ns)
+		if err != nil {
+			return err
+		}
+		if time.Now().After(end) {
+			return errors.Errorf("timed out after %s waiting for the completion check %s to pass", completionCheckTimeout.String(), check)
+		}
+		log.Logger().Infof("waiting for the completion check %s to pass", util.ColorInfo(check))
+		time.Sleep(jobPollInterval)
+	}
+}
+
+// verifyJobNotFailed returns an error if the boot Job has failed or has used up all of its configured retries
+func (o *RunOptions) verifyJobNotFailed(client kubernetes.Interface, ns string) error {
+	job, err := client.BatchV1().Jobs(ns).Get("jx-boot", metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get Job jx-boot in namespace %s", ns)
+	}
+	for _, c := range job.Status.Conditions {
+		if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+			return reqhelpers.NewJobFailedError(errors.Errorf("the boot Job has failed: %s %s", c.Reason, c.Message))
+		}
+	}
+	backoffLimit := o.BootJob.BackoffLimit
+	if backoffLimit != nil && int(job.Status.Failed) > *backoffLimit {
+		return reqhelpers.NewJobFailedError(errors.Errorf("the boot Job has failed %d times which exceeds the backoff limit of %d", job.Status.Failed, *backoffLimit))
+	}
+	return nil
+}
+
 // Git lazily create a gitter if its not specified
 func (o *RunOptions) Git() gits.Gitter {
 	if o.Gitter == nil {
@@ -250,7 +1185,9 @@ func (o *RunOptions) verifyBootSecret(requirements *config.RequirementsConfig) e
 	}
 
 	reqNs := requirements.Cluster.Namespace
-	if reqNs != "" && reqNs != ns {
+	if reqNs != "" && reqNs != ns && o.DryRun {
+		log.Logger().Infof("would switch to the deployment namespace %s as we currently are in the %s namespace", util.ColorInfo(reqNs), util.ColorInfo(ns))
+	} else if reqNs != "" && reqNs != ns {
 		log.Logger().Infof("switching to the deployment namespace %s as we currently are in the %s namespace", util.ColorInfo(reqNs), util.ColorInfo(ns))
 
 		f := clients.NewFactory()
@@ -271,6 +1208,10 @@ func (o *RunOptions) verifyBootSecret(requirements *config.RequirementsConfig) e
 	if err != nil {
 		return errors.Wrap(err, "failed to create Secrets manager")
 	}
+	if sm.Kind() == secretmgr.KindNone {
+		log.Logger().Warnf("not verifying the boot secrets as the secret manager kind is %s", secretmgr.KindNone)
+		return nil
+	}
 
 	secretYaml := ""
 	err = sm.UpsertSecrets(func(s string) (string, error) {
@@ -291,31 +1232,99 @@ func (o *RunOptions) verifyBootSecret(requirements *config.RequirementsConfig) e
 }
 
 func (o *RunOptions) findChartVersion(req *config.RequirementsConfig) (string, error) {
-	if o.ChartName == "" || o.ChartName[0] == '.' || o.ChartName[0] == '/' || o.ChartName[0] == '\\' || strings.Count(o.ChartName, "/") > 1 {
+	if o.isLocalChart() {
 		// relative chart folder so ignore version
 		return "", nil
 	}
 
+	resolver, err := o.cloneVersionStream(req)
+	if err != nil {
+		return "", err
+	}
+	version, err := o.stableChartVersion(req, resolver)
+	if err != nil {
+		return version, err
+	}
+	err = o.verifyVersionStreamDrift(req, resolver.VersionsDir)
+	if err != nil {
+		return version, err
+	}
+	return version, nil
+}
+
+// isLocalChart returns true if the chart name refers to a chart folder rather than a chart in a repository
+func (o *RunOptions) isLocalChart() bool {
+	return o.ChartName == "" || o.ChartName[0] == '.' || o.ChartName[0] == '/' || o.ChartName[0] == '\\' || strings.Count(o.ChartName, "/") > 1
+}
+
+// cloneVersionStream clones the version stream of the requirements
+func (o *RunOptions) cloneVersionStream(req *config.RequirementsConfig) (*versionstream.VersionResolver, error) {
 	f := clients.NewFactory()
 	co := opts.NewCommonOptionsWithTerm(f, os.Stdin, os.Stdout, os.Stderr)
 	co.BatchMode = o.BatchMode
 
 	u := req.VersionStream.URL
 	ref := req.VersionStream.Ref
-	version, err := getVersionNumber(versionstream.KindChart, o.ChartName, u, ref, o.Git(), co.GetIOFileHandles())
+	cloneURL, err := o.versionsCloneURL(u)
 	if err != nil {
-		return version, errors.Wrapf(err, "failed to find version of chart %s in version stream %s ref %s", o.ChartName, u, ref)
+		return nil, err
+	}
+	err = githelpers.VerifyRemoteRef(cloneURL, ref)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid versions ref. Please check the versionStream.ref in your jx-requirements.yml")
+	}
+	resolver, err := createVersionResolver(cloneURL, ref, o.Git(), co.GetIOFileHandles())
+	if err != nil {
+		return nil, errors.Wrapf(githelpers.RedactError(err), "failed to clone version stream %s ref %s", githelpers.RedactURLs(u), ref)
+	}
+	return resolver, nil
+}
+
+// stableChartVersion returns the version of the boot chart in the version stream
+func (o *RunOptions) stableChartVersion(req *config.RequirementsConfig, resolver *versionstream.VersionResolver) (string, error) {
+	version, err := resolver.StableVersionNumber(versionstream.KindChart, o.ChartName)
+	if err != nil {
+		return version, errors.Wrapf(githelpers.RedactError(err), "failed to find version of chart %s in version stream %s ref %s", o.ChartName, githelpers.RedactURLs(req.VersionStream.URL), req.VersionStream.Ref)
 	}
 	return version, nil
 }
 
-// getVersionNumber returns the version number for the given kind and name or blank string if there is no locked version
-func getVersionNumber(kind versionstream.VersionKind, name, repo, gitRef string, git gits.Gitter, handles util.IOFileHandles) (string, error) {
-	versioner, err := createVersionResolver(repo, gitRef, git, handles)
+// verifyVersionStreamDrift warns about or with --strict-versions fails on any charts used by the requirements
+// which are missing from the version stream so that we don't start a boot which can only partially succeed
+func (o *RunOptions) verifyVersionStreamDrift(req *config.RequirementsConfig, versionsDir string) error {
+	missing, err := reqhelpers.FindMissingChartVersions(req, versionsDir)
 	if err != nil {
-		return "", err
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	var messages []string
+	for _, m := range missing {
+		messages = append(messages, fmt.Sprintf("%s (needed for %s)", m.Chart, m.Reason))
+		log.Logger().Warnf("the version stream %s ref %s has no version for chart %s which is needed for %s", githelpers.RedactURLs(req.VersionStream.URL), req.VersionStream.Ref, util.ColorWarning(m.Chart), m.Reason)
+	}
+	if o.StrictVersions {
+		return errors.Errorf("the version stream is missing versions for charts used by the requirements: %s", strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+// versionsCloneURL returns the URL used to clone the versions repo adding the user and token if one is specified
+// so that private versions repositories can be used
+func (o *RunOptions) versionsCloneURL(gitURL string) (string, error) {
+	if o.VersionsGitToken == "" || gitURL == "" {
+		return gitURL, nil
+	}
+	username := o.VersionsGitUser
+	if username == "" {
+		username = o.GitUserName
+	}
+	answer, err := githelpers.AddUserTokenToURLIfRequired(gitURL, username, o.VersionsGitToken)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to add the user and token to the versions repo URL %s. Please specify --versions-git-user", gitURL)
 	}
-	return versioner.StableVersionNumber(kind, name)
+	return answer, nil
 }
 
 // createVersionResolver creates a new VersionResolver service
@@ -0,0 +1,344 @@
+package run_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/cmd/run"
+	"github.com/jenkins-x-labs/helmboot/pkg/fakes/fakejxfactory"
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+func TestRunBootJobDryRun(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+
+	testCases := []struct {
+		name      string
+		gitURL    string
+		expectErr bool
+	}{
+		{
+			name:   "success",
+			gitURL: "file://" + repoDir,
+		},
+		{
+			name:      "early failure",
+			gitURL:    "file://" + filepath.Join(repoDir, "does-not-exist"),
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		f := fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+		kubeClient := f.(*fakejxfactory.FakeFactory).KubeClient.(*fake.Clientset)
+
+		o := &run.RunOptions{
+			DryRun:          true,
+			EmitEvents:      true,
+			AuditConfigMap:  "helmboot-audit",
+			SkipRBACCheck:   true,
+			BatchMode:       true,
+			GitUserName:     "myuser",
+			GitToken:        "mytoken",
+			InstallerDir:    filepath.Join(repoDir, "installer"),
+			CRDTimeout:      1,
+			WaitCRDs:        []string{"environments.jenkins.io"},
+			JobRetries:      1,
+			JobRetryBackoff: 1,
+		}
+		o.GitURL = tc.gitURL
+		o.Dir = repoDir
+		o.KindResolver.Factory = f
+
+		err := o.RunBootJob()
+		if tc.expectErr {
+			assert.Error(t, err, "%s: should have failed", tc.name)
+		} else {
+			assert.NoError(t, err, "%s: failed to run the dry run", tc.name)
+		}
+
+		for _, action := range kubeClient.Actions() {
+			assert.Contains(t, []string{"get", "list", "watch"}, action.GetVerb(), "%s: a dry run should not %s %s", tc.name, action.GetVerb(), describeResource(action))
+			assert.NotEqual(t, "jobs", action.GetResource().Resource, "%s: a dry run should not access the boot Job", tc.name)
+		}
+	}
+}
+
+func TestRunBootJobRequireConsistent(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+	gitURL := "file://" + repoDir
+
+	testCases := []struct {
+		name        string
+		clusterName string
+		envGitURL   string
+		conflicts   []string
+	}{
+		{
+			name:        "consistent",
+			clusterName: "mycluster",
+			envGitURL:   gitURL,
+		},
+		{
+			name:        "different cluster name",
+			clusterName: "othercluster",
+			envGitURL:   gitURL,
+			conflicts:   []string{"cluster.clusterName"},
+		},
+		{
+			name:        "different git URL",
+			clusterName: "mycluster",
+			envGitURL:   "https://github.com/myorg/environment-other-dev.git",
+			conflicts:   []string{"git URL"},
+		},
+	}
+	for _, tc := range testCases {
+		envRequirements := config.NewRequirementsConfig()
+		envRequirements.Cluster.ClusterName = tc.clusterName
+		data, err := yaml.Marshal(envRequirements)
+		require.NoError(t, err, "%s: failed to marshal the requirements", tc.name)
+
+		devEnv := kube.CreateDefaultDevEnvironment("jx")
+		devEnv.Namespace = "jx"
+		devEnv.Spec.Source.URL = tc.envGitURL
+		devEnv.Spec.TeamSettings.BootRequirements = string(data)
+
+		o := &run.RunOptions{
+			DryRun:            true,
+			RequireConsistent: true,
+			SkipRBACCheck:     true,
+			BatchMode:         true,
+			GitUserName:       "myuser",
+			GitToken:          "mytoken",
+			InstallerDir:      filepath.Join(repoDir, "installer"),
+		}
+		o.GitURL = gitURL
+		o.Dir = repoDir
+		o.KindResolver.Factory = fakejxfactory.NewFakeFactoryWithObjects(nil, []runtime.Object{devEnv}, "jx")
+
+		err = o.RunBootJob()
+		if len(tc.conflicts) == 0 {
+			assert.NoError(t, err, "%s: should have passed the consistency check", tc.name)
+			continue
+		}
+		require.Error(t, err, "%s: should have failed the consistency check", tc.name)
+		assert.Contains(t, err.Error(), "the local requirements and the dev Environment disagree", "%s: error message", tc.name)
+		for _, field := range tc.conflicts {
+			assert.Contains(t, err.Error(), field, "%s: error message", tc.name)
+		}
+	}
+}
+
+func TestRunBootJobNoTail(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+	argsFile, restorePath := useFakeHelm(t, repoDir)
+	defer restorePath()
+
+	f := fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	kubeClient := f.(*fakejxfactory.FakeFactory).KubeClient.(*fake.Clientset)
+
+	o := &run.RunOptions{
+		NoTail:        true,
+		SkipRBACCheck: true,
+		BatchMode:     true,
+		GitUserName:   "myuser",
+		GitToken:      "mytoken",
+		InstallerDir:  filepath.Join(repoDir, "installer"),
+	}
+	o.GitURL = "file://" + repoDir
+	o.Dir = repoDir
+	o.KindResolver.Factory = f
+
+	err := o.RunBootJob()
+	require.NoError(t, err, "failed to create the boot Job")
+
+	data, err := ioutil.ReadFile(argsFile)
+	require.NoError(t, err, "failed to load file %s", argsFile)
+	assert.Contains(t, string(data), "install jx-boot", "should have installed the boot Job chart")
+
+	for _, action := range kubeClient.Actions() {
+		assert.NotEqual(t, "pods", action.GetResource().Resource, "should not have waited for the boot Job pod but did %s %s", action.GetVerb(), describeResource(action))
+	}
+}
+
+func TestRunBootJobRequirementsGitURL(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+	requirementsDir := createRequirementsRepo(t, "othercluster")
+	defer os.RemoveAll(requirementsDir)
+	argsFile, restorePath := useFakeHelm(t, repoDir)
+	defer restorePath()
+
+	o := &run.RunOptions{
+		NoTail:          true,
+		SkipRBACCheck:   true,
+		BatchMode:       true,
+		GitUserName:     "myuser",
+		GitToken:        "mytoken",
+		InstallerDir:    filepath.Join(repoDir, "installer"),
+		RequirementsGit: "file://" + requirementsDir,
+	}
+	o.GitURL = "file://" + repoDir
+	o.Dir = repoDir
+	o.KindResolver.Factory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+
+	err := o.RunBootJob()
+	require.NoError(t, err, "failed to create the boot Job")
+
+	data, err := ioutil.ReadFile(argsFile)
+	require.NoError(t, err, "failed to load file %s", argsFile)
+	assert.Contains(t, string(data), "jxRequirements.cluster.clusterName=othercluster", "should have used the requirements of the --requirements-git-url")
+
+	o = &run.RunOptions{RequirementsRef: "v1.0.0"}
+	o.Dir = repoDir
+	err = o.Run()
+	require.Error(t, err, "should have failed without --requirements-git-url")
+	assert.Contains(t, err.Error(), "requirements-git-url", "error message")
+}
+
+func TestRunBootJobCaptureOutput(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+	_, restorePath := useFakeHelm(t, repoDir)
+	defer restorePath()
+
+	captureFile := filepath.Join(repoDir, "output", "boot.txt")
+	err := os.MkdirAll(filepath.Dir(captureFile), util.DefaultWritePermissions)
+	require.NoError(t, err, "failed to create the output dir")
+
+	o := &run.RunOptions{
+		NoTail:        true,
+		SkipRBACCheck: true,
+		BatchMode:     true,
+		GitUserName:   "myuser",
+		GitToken:      "mytoken",
+		InstallerDir:  filepath.Join(repoDir, "installer"),
+		CaptureOutput: captureFile,
+	}
+	o.GitURL = "file://" + repoDir
+	o.Dir = repoDir
+	o.KindResolver.Factory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+
+	err = o.RunBootJob()
+	require.NoError(t, err, "failed to create the boot Job")
+
+	expected := "NAME: jx-boot\nSTATUS: deployed\n"
+	assert.Equal(t, expected, o.CapturedOutput, "captured output")
+	data, err := ioutil.ReadFile(captureFile)
+	require.NoError(t, err, "failed to load file %s", captureFile)
+	assert.Equal(t, expected, string(data), "should have saved the output of the helm command")
+}
+
+func TestRunBootJobLockUsesBootJobGitRef(t *testing.T) {
+	repoDir := createDevRepo(t)
+	defer os.RemoveAll(repoDir)
+	_, restorePath := useFakeHelm(t, repoDir)
+	defer restorePath()
+
+	git := func(args ...string) string {
+		c := util.Command{Dir: repoDir, Name: "git", Args: append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)}
+		text, err := c.RunWithoutRetry()
+		require.NoError(t, err, "failed to run git %v", args)
+		return strings.TrimSpace(text)
+	}
+	git("tag", "v1.0.0")
+	tagCommit := git("rev-parse", "v1.0.0")
+	git("commit", "--allow-empty", "-m", "second")
+
+	lockFile := filepath.Join(repoDir, ".helmboot-lock.yaml")
+	o := &run.RunOptions{
+		NoTail:        true,
+		SkipRBACCheck: true,
+		BatchMode:     true,
+		GitUserName:   "myuser",
+		GitToken:      "mytoken",
+		InstallerDir:  filepath.Join(repoDir, "installer"),
+		Lock:          true,
+		LockFile:      lockFile,
+	}
+	o.GitURL = "file://" + repoDir
+	o.GitRef = "master"
+	o.BootJob.GitRef = "v1.0.0"
+	o.Dir = repoDir
+	o.KindResolver.Factory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+
+	err := o.RunBootJob()
+	require.NoError(t, err, "failed to create the boot Job")
+
+	lock, err := reqhelpers.LoadBootLock(lockFile)
+	require.NoError(t, err, "failed to load the lock file")
+	assert.Equal(t, "v1.0.0", lock.GitRef, "should have locked the git ref of the boot Job")
+	assert.Equal(t, tagCommit, lock.GitCommit, "should have locked the commit of the boot Job git ref")
+}
+
+// useFakeHelm puts a fake helm binary on the PATH which appends its arguments to the returned file and prints the
+// output of a successful install. The returned function restores the PATH
+func useFakeHelm(t *testing.T, dir string) (string, func()) {
+	binDir := filepath.Join(dir, "bin")
+	err := os.MkdirAll(binDir, util.DefaultWritePermissions)
+	require.NoError(t, err, "failed to create dir %s", binDir)
+
+	argsFile := filepath.Join(dir, "helm-args.txt")
+	script := `#!/bin/sh
+echo "$@" >> "` + argsFile + `"
+if [ "$1" = "install" ]; then
+  echo "NAME: jx-boot"
+  echo "STATUS: deployed"
+fi
+`
+	err = ioutil.WriteFile(filepath.Join(binDir, "helm"), []byte(script), 0700)
+	require.NoError(t, err, "failed to save the fake helm binary")
+
+	oldPath := os.Getenv("PATH")
+	err = os.Setenv("PATH", binDir+string(os.PathListSeparator)+oldPath)
+	require.NoError(t, err, "failed to set the PATH")
+	return argsFile, func() {
+		os.Setenv("PATH", oldPath)
+	}
+}
+
+// createDevRepo creates a local git repository containing the requirements of the dev environment
+func createDevRepo(t *testing.T) string {
+	return createRequirementsRepo(t, "mycluster")
+}
+
+// createRequirementsRepo creates a local git repository containing the requirements for the given cluster name
+func createRequirementsRepo(t *testing.T, clusterName string) string {
+	repoDir, err := ioutil.TempDir("", "test-helmboot-dev-repo-")
+	require.NoError(t, err, "failed to create temp dir")
+
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.ClusterName = clusterName
+	requirements.Cluster.Namespace = "jx-staging"
+	requirements.SecretStorage = config.SecretStorageTypeVault
+	err = requirements.SaveConfig(filepath.Join(repoDir, config.RequirementsConfigFileName))
+	require.NoError(t, err, "failed to save requirements")
+	for _, args := range [][]string{
+		{"init"},
+		{"add", config.RequirementsConfigFileName},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-m", "initial"},
+	} {
+		c := util.Command{Dir: repoDir, Name: "git", Args: args}
+		_, err = c.RunWithoutRetry()
+		require.NoError(t, err, "failed to run git %v", args)
+	}
+	return repoDir
+}
+
+func describeResource(action k8stesting.Action) string {
+	return action.GetResource().Resource + " in namespace " + action.GetNamespace()
+}
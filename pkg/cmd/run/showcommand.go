@@ -0,0 +1,50 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// showCommand prints just the helm command line which would create the boot Job with any credentials redacted. Nothing
+// is uninstalled, verified or run beyond what is needed to resolve the requirements and git URL
+func (o *RunOptions) showCommand() error {
+	err := o.detectGitURL()
+	if err != nil {
+		return err
+	}
+	err = githelpers.VerifyAllowedGitHost("git URL", o.GitURL, o.allowedGitHosts)
+	if err != nil {
+		return err
+	}
+	requirements, gitURL, err := o.findRequirementsAndGitURLFromMirrors()
+	if err != nil {
+		return err
+	}
+	if gitURL == "" {
+		return util.MissingOption("git-url")
+	}
+	err = o.verifyAllowedGitHosts(requirements, gitURL)
+	if err != nil {
+		return err
+	}
+	err = o.applyLock(gitURL)
+	if err != nil {
+		return err
+	}
+	c, err := o.bootJobCommand(requirements, gitURL)
+	if err != nil {
+		return err
+	}
+	commandLine := common.RedactTrace(fmt.Sprintf("%s %s", c.Name, strings.Join(c.Args, " ")))
+	_, err = fmt.Fprintln(os.Stdout, commandLine)
+	if err != nil {
+		return errors.Wrap(err, "failed to print the boot command")
+	}
+	return nil
+}
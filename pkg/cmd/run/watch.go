@@ -0,0 +1,91 @@
+package run
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// watch polls the boot git repository and re-runs the boot Job whenever the git ref points to a new commit
+// until the process is interrupted
+func (o *RunOptions) watch() error {
+	if o.WatchInterval <= 0 {
+		return util.InvalidOptionf("watch-interval", o.WatchInterval.String(), "the poll interval must be positive")
+	}
+	gitURL := o.GitURL
+	ref := o.bootGitRef()
+	poll := githelpers.NewRefPoller(gitURL, ref)
+	sha, err := poll()
+	if err != nil {
+		return errors.Wrap(err, "failed to find the current commit of the boot git repository")
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	ticker := time.NewTicker(o.WatchInterval)
+	defer ticker.Stop()
+
+	log.Logger().Infof("watching ref %s for changes every %s. Press Ctrl-C to stop", util.ColorInfo(ref), o.WatchInterval.String())
+	for {
+		select {
+		case <-signals:
+			log.Logger().Infof("stopped watching for changes")
+			return nil
+
+		case <-ticker.C:
+			newSHA, err := poll()
+			if err != nil {
+				log.Logger().Warnf("failed to poll the boot git repository: %s", err.Error())
+				continue
+			}
+			if newSHA == sha {
+				continue
+			}
+
+			// lets wait for the pushes to settle down before re-booting
+			newSHA, err = githelpers.WaitForStableRef(poll, newSHA, o.WatchDebounce, signals)
+			if err != nil {
+				log.Logger().Warnf("failed to poll the boot git repository: %s", err.Error())
+				continue
+			}
+			if newSHA == "" {
+				log.Logger().Infof("stopped watching for changes")
+				return nil
+			}
+
+			log.Logger().Infof("ref %s has changed from %s to %s so re-running the boot Job", util.ColorInfo(ref), sha, util.ColorInfo(newSHA))
+			sha = newSHA
+			stopped, err := o.runBootJobUnlessStopped(signals)
+			if stopped {
+				log.Logger().Infof("stopped watching for changes while the boot Job for commit %s was running", newSHA)
+				return nil
+			}
+			if err != nil {
+				log.Logger().Errorf("failed to re-run the boot Job for commit %s: %s", newSHA, err.Error())
+			}
+		}
+	}
+}
+
+// runBootJobUnlessStopped runs the boot Job in the background so that the watch can still be stopped while the boot is
+// running. Returns true if the stop channel receives a signal before the boot completes
+func (o *RunOptions) runBootJobUnlessStopped(stop <-chan os.Signal) (bool, error) {
+	done := make(chan error, 1)
+	go func() {
+		done <- o.RunBootJob()
+	}()
+	select {
+	case <-stop:
+		return true, nil
+	case err := <-done:
+		return false, err
+	}
+}
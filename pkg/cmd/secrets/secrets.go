@@ -19,10 +19,15 @@ func NewCmdSecrets() *cobra.Command {
 			}
 		},
 	}
+	command.AddCommand(common.SplitCommand(NewCmdCheck()))
+	command.AddCommand(common.SplitCommand(NewCmdCheckAccess()))
 	command.AddCommand(common.SplitCommand(NewCmdEdit()))
 	command.AddCommand(common.SplitCommand(NewCmdExport()))
 	command.AddCommand(common.SplitCommand(NewCmdImport()))
+	command.AddCommand(common.SplitCommand(NewCmdPatch()))
+	command.AddCommand(common.SplitCommand(NewCmdRotateAll()))
 	command.AddCommand(common.SplitCommand(NewCmdVerify()))
+	command.AddCommand(common.SplitCommand(NewCmdWait()))
 	command.AddCommand(common.SplitCommand(NewCmdYAML()))
 	return command
 }
@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/factory"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkAccessLong = templates.LongDesc(`
+		Checks whether the current identity can read and write the secrets of the secret manager without storing anything so that permission problems are found before a boot depends on them
+`)
+
+	checkAccessExample = templates.Examples(`
+		# checks the access to the secret manager used by the cluster
+		%s secrets check-access
+
+		# checks the access to Google Secret Manager
+		%s secrets check-access --kind gsm
+	`)
+)
+
+// CheckAccessOptions the options for checking the access to the secret manager
+type CheckAccessOptions struct {
+	factory.KindResolver
+
+	// Access the result of the check
+	Access secretmgr.Access
+}
+
+// NewCmdCheckAccess creates a command object for the command
+func NewCmdCheckAccess() (*cobra.Command, *CheckAccessOptions) {
+	o := &CheckAccessOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "check-access",
+		Short:   "Checks whether the current identity can read and write the secrets",
+		Long:    checkAccessLong,
+		Example: fmt.Sprintf(checkAccessExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	AddKindResolverFlags(cmd, &o.KindResolver)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *CheckAccessOptions) Run() error {
+	sm, err := o.CreateSecretManager("")
+	if err != nil {
+		return err
+	}
+	o.Access, err = secretmgr.CheckAccess(sm)
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("checked the access to %s", util.ColorInfo(sm.String()))
+	log.Logger().Infof("read:  %s", colorAccess(o.Access.Read))
+	log.Logger().Infof("write: %s", colorAccess(o.Access.Write))
+	for _, d := range o.Access.Details {
+		log.Logger().Infof("  %s", d)
+	}
+	return secretmgr.VerifyAccess(o.Access, sm.String())
+}
+
+func colorAccess(access string) string {
+	switch access {
+	case secretmgr.AccessAllowed:
+		return util.ColorInfo(access)
+	case secretmgr.AccessDenied:
+		return util.ColorError(access)
+	default:
+		return util.ColorWarning(access)
+	}
+}
@@ -0,0 +1,79 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/factory"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkLong = templates.LongDesc(`
+		Checks the secrets in a local file are already stored in the Secret Manager with the same values without modifying anything.
+
+		The values of the secrets are never displayed so this is safe to use as a gate in a CI pipeline
+`)
+
+	checkExample = templates.Examples(`
+		# checks the stored secrets are in sync with a file
+		%s secrets check -f /tmp/mysecrets.yaml
+	`)
+)
+
+// CheckOptions the options for checking the secrets are in sync
+type CheckOptions struct {
+	factory.KindResolver
+	File string
+}
+
+// NewCmdCheck creates a command object for the command
+func NewCmdCheck() (*cobra.Command, *CheckOptions) {
+	o := &CheckOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "check",
+		Short:   "Checks the secrets in a local file are in sync with the Secret Manager",
+		Long:    checkLong,
+		Example: fmt.Sprintf(checkExample, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&o.File, "file", "f", "", "the file to load the desired Secrets YAML from")
+
+	AddKindResolverFlags(cmd, &o.KindResolver)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *CheckOptions) Run() error {
+	fileName := o.File
+	if fileName == "" {
+		return util.MissingOption("file")
+	}
+	data, err := secretmgr.ReadSecretFile(fileName)
+	if err != nil {
+		return err
+	}
+	currentYAML, err := o.LoadSecretsYAML()
+	if err != nil {
+		return err
+	}
+	mismatched, err := secretmgr.MismatchedSecrets(string(data), currentYAML)
+	if err != nil {
+		return err
+	}
+	if len(mismatched) > 0 {
+		return errors.Errorf("the secrets are not in sync with %s. Missing or different secrets: %s", fileName, strings.Join(mismatched, ", "))
+	}
+	common.LogResult("the secrets are in sync with %s", util.ColorInfo(fileName))
+	return nil
+}
@@ -130,17 +130,21 @@ var (
 	editExample = templates.Examples(`
 		# edit the secrets
 		%s secrets edit
+
+		# edit the secrets generating random values for any missing passwords and tokens
+		%s secrets edit --generate-missing
 	`)
 )
 
 // EditOptions the options for viewing running PRs
 type EditOptions struct {
 	factory.KindResolver
-	SchemaFile    string
-	IOFileHandles *util.IOFileHandles
-	AskExisting   bool
-	BatchMode     bool
-	Verbose       bool
+	SchemaFile      string
+	IOFileHandles   *util.IOFileHandles
+	AskExisting     bool
+	BatchMode       bool
+	Verbose         bool
+	GenerateMissing bool
 }
 
 // NewCmdEdit creates a command object for the command
@@ -151,7 +155,7 @@ func NewCmdEdit() (*cobra.Command, *EditOptions) {
 		Use:     "edit",
 		Short:   "Edits the secrets",
 		Long:    editLong,
-		Example: fmt.Sprintf(editExample, common.BinaryName),
+		Example: fmt.Sprintf(editExample, common.BinaryName, common.BinaryName),
 		Run: func(cmd *cobra.Command, args []string) {
 			err := o.Run()
 			helper.CheckErr(err)
@@ -162,6 +166,7 @@ func NewCmdEdit() (*cobra.Command, *EditOptions) {
 	cmd.Flags().BoolVarP(&o.AskExisting, "all", "a", false, "if enabled ask for confirmation on all secret values. Otherwise just prompt for missing values only")
 	cmd.Flags().BoolVarP(&o.Verbose, "verbose", "v", false, "enables verbose logging")
 	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input")
+	cmd.Flags().BoolVarP(&o.GenerateMissing, "generate-missing", "", false, "generates random values for any missing secrets which can be generated such as the admin password and webhook HMAC token rather than prompting for them")
 
 	AddKindResolverFlags(cmd, &o.KindResolver)
 	return cmd, o
@@ -169,6 +174,8 @@ func NewCmdEdit() (*cobra.Command, *EditOptions) {
 
 // Run implements the command
 func (o *EditOptions) Run() error {
+	o.KindResolver.BatchMode = o.BatchMode
+	o.KindResolver.IOFileHandles = o.IOFileHandles
 	sm, err := o.CreateSecretManager("")
 	if err != nil {
 		return err
@@ -216,6 +223,12 @@ func (o *EditOptions) editSecretsYaml(secretsYaml string) (string, error) {
 			return secretsYaml, errors.Wrap(err, "failed to unmarshal YAML")
 		}
 	}
+	if o.GenerateMissing {
+		err = o.generateMissingSecrets(secretClient)
+		if err != nil {
+			return secretsYaml, err
+		}
+	}
 	existing := map[string]interface{}{}
 	existingSecrets := secretClient.Data["secrets"]
 	existingSecretsMap, ok := existingSecrets.(map[string]interface{})
@@ -255,6 +268,23 @@ func (o *EditOptions) editSecretsYaml(secretsYaml string) (string, error) {
 	return updatedYaml, nil
 }
 
+// generateMissingSecrets generates random values for any missing secrets which can be generated
+func (o *EditOptions) generateMissingSecrets(secretClient *MemoryClient) error {
+	secretsMap, ok := secretClient.Data["secrets"].(map[string]interface{})
+	if !ok {
+		secretsMap = map[string]interface{}{}
+		secretClient.Data["secrets"] = secretsMap
+	}
+	generated, err := secretmgr.GenerateMissingSecrets(secretsMap)
+	if err != nil {
+		return err
+	}
+	for _, path := range generated {
+		log.Logger().Infof("generated a random value for secret %s", util.ColorInfo(path))
+	}
+	return nil
+}
+
 func (o *EditOptions) generateSchemaFile(requirements *config.RequirementsConfig) error {
 	templateFile := strings.TrimSuffix(o.SchemaFile, ".schema.json") + ".tmpl.schema.json"
 	schemaExists := false
@@ -39,8 +39,9 @@ var (
 // ExportOptions the options for viewing running PRs
 type ExportOptions struct {
 	factory.KindResolver
-	OutFile string
-	Console bool
+	OutFile    string
+	Console    bool
+	IgnoreFile string
 }
 
 // NewCmdExport creates a command object for the command
@@ -64,6 +65,7 @@ func NewCmdExport() (*cobra.Command, *ExportOptions) {
 	}
 	cmd.Flags().StringVarP(&o.OutFile, "file", "f", defaultOutputFile, "the file to use to save the secrets to")
 	cmd.Flags().BoolVarP(&o.Console, "console", "c", false, "display the secrets on the console instead of a file")
+	cmd.Flags().StringVarP(&o.IgnoreFile, "ignore-file", "", secretmgr.SecretsIgnoreFile, "The file of glob patterns of dot separated secret keys such as 'pipelineUser.*' which are never exported. Ignored if it does not exist")
 
 	AddKindResolverFlags(cmd, &o.KindResolver)
 	return cmd, o
@@ -74,6 +76,14 @@ func AddKindResolverFlags(cmd *cobra.Command, o *factory.KindResolver) {
 	cmd.Flags().StringVarP(&o.Kind, "kind", "k", "", "the kind of Secret Manager you wish to use. If no value is supplied it is detected based on the jx-requirements.yml. Possible values are: "+strings.Join(secretmgr.KindValues, ", "))
 	cmd.Flags().StringVarP(&o.Dir, "dir", "", ".", "the local directory used to find the jx-requirements.yml file if the cluster has not yet been booted")
 	cmd.Flags().StringVarP(&o.GitURL, "git-url", "u", "", "specify the git URL for the development environment so we can find the requirements")
+	cmd.Flags().StringVarP(&o.SecretPath, "secret-path", "", os.Getenv("JX_SECRET_PATH"), "the path of a file containing the secrets such as one mounted by an external secret operator. The file can be a secrets YAML file or lines of the form 'foo.bar: value'")
+	cmd.Flags().StringVarP(&o.Command, "secret-command", "", os.Getenv("JX_SECRET_COMMAND"), "the external command used to read and write the secrets YAML. It receives 'read' as the first line of stdin and should output the secrets YAML, or 'write' as the first line of stdin followed by the secrets YAML")
+	cmd.Flags().StringVarP(&o.Environment, "environment", "", "", "the name of the environment to scope the secrets to so that several environments can keep separate secrets in the same secret manager. They are stored under environments.<name>.secrets in the secrets YAML")
+	cmd.Flags().BoolVarP(&o.IgnoreDevEnvironment, "ignore-dev-environment", "", false, "never loads the requirements or git URL from the dev Environment so that only --git-url and the jx-requirements.yml in --dir are used")
+	cmd.Flags().BoolVarP(&o.KMS, "kms", "", false, "encrypts the secrets YAML in the local Secret with the KMS key configured for vault in the jx-requirements.yml so the secrets are protected even if the Secret is exfiltrated")
+	cmd.Flags().StringVarP(&o.KMSKey, "kms-key", "", "", "the Google Cloud KMS key resource name or AWS KMS key ID, alias or ARN used to encrypt the secrets YAML in the local Secret. Implies --kms")
+	cmd.Flags().BoolVarP(&o.NoCluster, "no-cluster", "", false, "creates the secret manager from --kind and the requirements in --dir without accessing the cluster. The local and vault kinds require cluster access")
+	o.AddImpersonationFlags(cmd)
 }
 
 // Run implements the command
@@ -109,6 +119,12 @@ func (o *ExportOptions) Run() error {
 
 	log.Logger().Infof("loaded Secrets from: %s", util.ColorInfo(sm.String()))
 
+	data, err := removeIgnoredSecrets(o.IgnoreFile, map[string][]byte{secretmgr.LocalSecretKey: []byte(secretsYAML)})
+	if err != nil {
+		return err
+	}
+	secretsYAML = string(data[secretmgr.LocalSecretKey])
+
 	if o.Console {
 		log.Logger().Infof("%s", util.ColorStatus(secretsYAML))
 		return nil
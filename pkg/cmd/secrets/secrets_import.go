@@ -2,7 +2,7 @@ package secrets
 
 import (
 	"fmt"
-	"io/ioutil"
+	"strings"
 
 	"github.com/jenkins-x-labs/helmboot/pkg/common"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
@@ -23,13 +23,23 @@ var (
 	importExample = templates.Examples(`
 		# imports the secrets
 		%s secrets import -f /tmp/mysecrets.yaml
+
+		# imports the secrets into both the local Secret and Google Secret Manager while migrating
+		%s secrets import -f /tmp/mysecrets.yaml --to local --to gsm
+
+		# imports the secrets removing any stored secrets which are not in the file
+		%s secrets import -f /tmp/mysecrets.yaml --prune
 	`)
 )
 
 // ImportOptions the options for viewing running PRs
 type ImportOptions struct {
 	factory.KindResolver
-	File string
+	File         string
+	To           []string
+	AllOrNothing bool
+	Prune        bool
+	BatchMode    bool
 }
 
 // NewCmdImport creates a command object for the command
@@ -40,7 +50,7 @@ func NewCmdImport() (*cobra.Command, *ImportOptions) {
 		Use:     "import",
 		Short:   "Imports the secrets from the local file system",
 		Long:    importLong,
-		Example: fmt.Sprintf(importExample, common.BinaryName),
+		Example: fmt.Sprintf(importExample, common.BinaryName, common.BinaryName, common.BinaryName),
 		Run: func(cmd *cobra.Command, args []string) {
 			err := o.Run()
 			helper.CheckErr(err)
@@ -48,6 +58,10 @@ func NewCmdImport() (*cobra.Command, *ImportOptions) {
 	}
 
 	cmd.Flags().StringVarP(&o.File, "file", "f", "", "the file to load the Secrets YAML from")
+	cmd.Flags().StringArrayVarP(&o.To, "to", "", nil, "the kinds of Secret Manager to import the secrets into such as while migrating between them. Can be specified multiple times. Possible values are: "+strings.Join(secretmgr.KindValues, ", "))
+	cmd.Flags().BoolVarP(&o.AllOrNothing, "all-or-nothing", "", false, "when importing into multiple Secret Managers stop at the first one which fails rather than importing into the others")
+	cmd.Flags().BoolVarP(&o.Prune, "prune", "", false, "removes any stored secrets which are not in the file so the stored secrets exactly match it. By default the file is merged into the stored secrets")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input such as to confirm pruning secrets")
 
 	AddKindResolverFlags(cmd, &o.KindResolver)
 	return cmd, o
@@ -55,23 +69,96 @@ func NewCmdImport() (*cobra.Command, *ImportOptions) {
 
 // Run implements the command
 func (o *ImportOptions) Run() error {
+	o.KindResolver.BatchMode = o.BatchMode
 	fileName := o.File
 	if fileName == "" {
 		return util.MissingOption("file")
 	}
 
-	data, err := ioutil.ReadFile(fileName)
+	data, err := secretmgr.ReadSecretFile(fileName)
 	if err != nil {
-		return errors.Wrapf(err, "failed to load file %s", fileName)
+		return err
 	}
 
 	secretsYAML := string(data)
-	sm, err := o.CreateSecretManager(secretsYAML)
+	if len(o.To) > 0 {
+		err = o.importToTargets(secretsYAML, fileName)
+		if err != nil {
+			return err
+		}
+		return o.SaveBootRunGitCloneSecret(secretsYAML)
+	}
+
+	err = o.importSecrets(&o.KindResolver, secretsYAML, fileName)
+	if err != nil {
+		return err
+	}
+	return o.SaveBootRunGitCloneSecret(secretsYAML)
+}
+
+// importToTargets imports the secrets into each of the target kinds of secret manager reporting the result of each.
+// A failure on one target does not stop the others being imported unless AllOrNothing is enabled
+func (o *ImportOptions) importToTargets(secretsYAML string, fileName string) error {
+	for _, kind := range o.To {
+		if util.StringArrayIndex(secretmgr.KindValues, kind) < 0 {
+			return util.InvalidOption("to", kind, secretmgr.KindValues)
+		}
+	}
+
+	var failed []string
+	for _, kind := range o.To {
+		r := o.KindResolver
+		r.Kind = kind
+		err := o.importSecrets(&r, secretsYAML, fileName)
+		if err != nil {
+			if o.AllOrNothing {
+				return errors.Wrapf(err, "stopped importing secrets as the %s secret manager failed", kind)
+			}
+			log.Logger().Errorf("failed to import secrets to %s: %s", util.ColorError(kind), err.Error())
+			failed = append(failed, kind)
+			continue
+		}
+		if o.GitURL == "" {
+			o.GitURL = r.GitURL
+		}
+	}
+	if len(failed) > 0 {
+		return errors.Errorf("failed to import secrets to: %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// importSecrets imports the secrets YAML into the secret manager of the given resolver
+func (o *ImportOptions) importSecrets(r *factory.KindResolver, secretsYAML string, fileName string) error {
+	sm, err := r.CreateSecretManager(secretsYAML)
 	if err != nil {
 		return err
 	}
 
 	cb := func(currentYaml string) (string, error) {
+		stale, err := secretmgr.StaleSecrets(secretsYAML, currentYaml)
+		if err != nil {
+			return "", err
+		}
+		if len(stale) == 0 {
+			return secretsYAML, nil
+		}
+		if !o.Prune {
+			log.Logger().Infof("keeping %d stored secrets which are not in the file %s. Use --prune to remove them", len(stale), fileName)
+			return secretmgr.MergeSecretsYAML(currentYaml, secretsYAML)
+		}
+		if !o.BatchMode {
+			confirm, err := util.Confirm(fmt.Sprintf("You are about to remove %d secrets from %s which are not in the file %s. Are you sure?", len(stale), sm.String(), fileName), false, "The removed secrets are: "+strings.Join(stale, ", "), common.GetIOFileHandles(nil))
+			if err != nil {
+				return "", err
+			}
+			if !confirm {
+				return "", errors.Errorf("aborted pruning the secrets")
+			}
+		}
+		for _, path := range stale {
+			log.Logger().Infof("pruned secret %s", util.ColorInfo(path))
+		}
 		return secretsYAML, nil
 	}
 	err = sm.UpsertSecrets(cb, secretmgr.DefaultSecretsYaml)
@@ -79,6 +166,5 @@ func (o *ImportOptions) Run() error {
 		return errors.Wrapf(err, "failed to import Secrets YAML from secret manager %s", sm.String())
 	}
 	log.Logger().Infof("imported Secrets to %s from file: %s", sm.String(), util.ColorInfo(fileName))
-
-	return o.SaveBootRunGitCloneSecret(secretsYAML)
+	return nil
 }
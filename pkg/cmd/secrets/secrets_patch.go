@@ -0,0 +1,175 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/factory"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	patchLong = templates.LongDesc(`
+		Merges a secrets YAML file of just the secrets to change into the stored secrets leaving any other secrets untouched
+`)
+
+	patchExample = templates.Examples(`
+		# changes just the secrets in the patch file after confirming
+		%s secrets patch -f /tmp/patch.yaml
+
+		# shows which secrets would change without modifying them
+		%s secrets patch -f /tmp/patch.yaml --dry-run
+	`)
+)
+
+// PatchOptions the options for patching the secrets
+type PatchOptions struct {
+	factory.KindResolver
+	File      string
+	DryRun    bool
+	BatchMode bool
+
+	// Changes the changes to the secrets in the patch with masked values
+	Changes []secretmgr.SecretChange
+}
+
+// NewCmdPatch creates a command object for the command
+func NewCmdPatch() (*cobra.Command, *PatchOptions) {
+	o := &PatchOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "patch",
+		Short:   "Merges a patch file of the secrets to change into the stored secrets",
+		Long:    patchLong,
+		Example: fmt.Sprintf(patchExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().StringVarP(&o.File, "file", "f", "", "the secrets YAML file of just the secrets to change")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "shows the secrets which would change with their values masked without modifying them")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input such as to confirm patching the secrets")
+
+	AddKindResolverFlags(cmd, &o.KindResolver)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *PatchOptions) Run() error {
+	o.KindResolver.BatchMode = o.BatchMode
+	fileName := o.File
+	if fileName == "" {
+		return util.MissingOption("file")
+	}
+	data, err := secretmgr.ReadSecretFile(fileName)
+	if err != nil {
+		return err
+	}
+	patchYAML := string(data)
+	err = verifyPatch(patchYAML, fileName)
+	if err != nil {
+		return err
+	}
+
+	sm, err := o.CreateSecretManager(patchYAML)
+	if err != nil {
+		return err
+	}
+	updatedYaml := ""
+	err = sm.UpsertSecrets(func(currentYaml string) (string, error) {
+		answer, err := o.patchSecretsYaml(currentYaml, patchYAML, sm.String())
+		if err != nil {
+			return "", err
+		}
+		updatedYaml = answer
+		return answer, nil
+	}, secretmgr.DefaultSecretsYaml)
+	if err != nil {
+		return errors.Wrapf(err, "failed to patch the secrets in secret manager %s", sm.String())
+	}
+	if o.DryRun {
+		return nil
+	}
+	log.Logger().Infof("patched %d secrets in %s from file: %s", countChanged(o.Changes), sm.String(), util.ColorInfo(fileName))
+	return o.SaveBootRunGitCloneSecret(updatedYaml)
+}
+
+// patchSecretsYaml returns the current secrets YAML with the patch merged into it. On a dry run the changes are
+// logged and the current YAML is returned unchanged
+func (o *PatchOptions) patchSecretsYaml(currentYaml string, patchYAML string, name string) (string, error) {
+	changes, err := secretmgr.PatchSecretChanges(currentYaml, patchYAML)
+	if err != nil {
+		return "", err
+	}
+	o.Changes = changes
+	changed := countChanged(changes)
+	if o.DryRun {
+		for _, c := range changes {
+			status := "unchanged"
+			if c.Changed {
+				status = "would change"
+			}
+			log.Logger().Infof("%s: %s -> %s (%s)", util.ColorInfo(c.Path), c.Before, c.After, status)
+		}
+		log.Logger().Infof("would patch %d secrets in %s", changed, name)
+		return currentYaml, nil
+	}
+	if changed == 0 {
+		log.Logger().Infof("the secrets in %s already match the patch", name)
+		return currentYaml, nil
+	}
+	if !o.BatchMode {
+		var paths []string
+		for _, c := range changes {
+			if c.Changed {
+				paths = append(paths, c.Path)
+			}
+		}
+		confirm, err := util.Confirm(fmt.Sprintf("You are about to change %d secrets in %s. Are you sure?", changed, name), false, "The changed secrets are: "+strings.Join(paths, ", "), common.GetIOFileHandles(o.IOFileHandles))
+		if err != nil {
+			return "", err
+		}
+		if !confirm {
+			return "", errors.Errorf("aborted patching the secrets")
+		}
+	}
+	return secretmgr.MergeSecretsYAML(currentYaml, patchYAML)
+}
+
+// verifyPatch verifies the patch contains at least one secret and only secrets under the secrets root key
+func verifyPatch(patchYAML string, fileName string) error {
+	changes, err := secretmgr.PatchSecretChanges("", patchYAML)
+	if err != nil {
+		return errors.Wrapf(err, "invalid patch file %s", fileName)
+	}
+	if len(changes) == 0 {
+		return errors.Errorf("no secrets in the patch file %s", fileName)
+	}
+	prefix := secretmgr.DefaultSecretsRootKey + "."
+	for _, c := range changes {
+		if !strings.HasPrefix(c.Path, prefix) {
+			return errors.Errorf("the patch file %s contains %s which is not under the %s key", fileName, c.Path, secretmgr.DefaultSecretsRootKey)
+		}
+	}
+	return nil
+}
+
+// countChanged returns the number of changes which modify a secret
+func countChanged(changes []secretmgr.SecretChange) int {
+	count := 0
+	for _, c := range changes {
+		if c.Changed {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,137 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/factory"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	rotateAllLong = templates.LongDesc(`
+		Regenerates all of the secrets which can be generated such as the admin password and webhook HMAC token with fresh random values. Secrets supplied by the user are kept
+`)
+
+	rotateAllExample = templates.Examples(`
+		# regenerates the generatable secrets after confirming
+		%s secrets rotate-all
+
+		# shows which secrets would be regenerated without changing them
+		%s secrets rotate-all --dry-run
+	`)
+)
+
+// RotateAllOptions the options for regenerating the generatable secrets
+type RotateAllOptions struct {
+	factory.KindResolver
+	DryRun    bool
+	BatchMode bool
+
+	// Rotated the sorted paths of the secrets which were regenerated or would be on a dry run
+	Rotated []string
+}
+
+// NewCmdRotateAll creates a command object for the command
+func NewCmdRotateAll() (*cobra.Command, *RotateAllOptions) {
+	o := &RotateAllOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "rotate-all",
+		Short:   "Regenerates all of the secrets which can be generated",
+		Long:    rotateAllLong,
+		Example: fmt.Sprintf(rotateAllExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "lists the secrets which would be regenerated without modifying them")
+	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input such as to confirm regenerating the secrets")
+
+	AddKindResolverFlags(cmd, &o.KindResolver)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *RotateAllOptions) Run() error {
+	o.KindResolver.BatchMode = o.BatchMode
+	o.Rotated = nil
+	sm, err := o.CreateSecretManager("")
+	if err != nil {
+		return err
+	}
+
+	updatedYaml := ""
+	err = sm.UpsertSecrets(func(currentYaml string) (string, error) {
+		answer, err := o.rotateSecretsYaml(currentYaml, sm.String())
+		if err != nil {
+			return "", err
+		}
+		updatedYaml = answer
+		return answer, nil
+	}, secretmgr.DefaultSecretsYaml)
+	if err != nil {
+		return errors.Wrapf(err, "failed to rotate the secrets in secret manager %s", sm.String())
+	}
+
+	if o.DryRun {
+		for _, path := range o.Rotated {
+			log.Logger().Infof("would regenerate secret %s", util.ColorInfo(path))
+		}
+		return nil
+	}
+	for _, path := range o.Rotated {
+		log.Logger().Infof("regenerated secret %s", util.ColorInfo(path))
+	}
+	log.Logger().Infof("rotated %d secrets in %s", len(o.Rotated), sm.String())
+	return o.SaveBootRunGitCloneSecret(updatedYaml)
+}
+
+// rotateSecretsYaml returns the secrets YAML with the generatable secrets regenerated. On a dry run the current
+// YAML is returned unchanged
+func (o *RotateAllOptions) rotateSecretsYaml(currentYaml string, name string) (string, error) {
+	values := map[string]interface{}{}
+	if strings.TrimSpace(currentYaml) != "" {
+		err := yaml.Unmarshal([]byte(currentYaml), &values)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to unmarshal the secrets YAML")
+		}
+	}
+	secretsMap, ok := values[secretmgr.DefaultSecretsRootKey].(map[string]interface{})
+	if !ok {
+		secretsMap = map[string]interface{}{}
+		values[secretmgr.DefaultSecretsRootKey] = secretsMap
+	}
+	rotated, err := secretmgr.RegenerateSecrets(secretsMap)
+	if err != nil {
+		return "", err
+	}
+	o.Rotated = rotated
+	if o.DryRun {
+		return currentYaml, nil
+	}
+	if !o.BatchMode {
+		confirm, err := util.Confirm(fmt.Sprintf("You are about to regenerate %d secrets in %s. Are you sure?", len(rotated), name), false, "The regenerated secrets are: "+strings.Join(rotated, ", "), common.GetIOFileHandles(nil))
+		if err != nil {
+			return "", err
+		}
+		if !confirm {
+			return "", errors.Errorf("aborted rotating the secrets")
+		}
+	}
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the secrets YAML")
+	}
+	return string(data), nil
+}
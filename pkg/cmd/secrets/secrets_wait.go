@@ -0,0 +1,89 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/factory"
+	"github.com/jenkins-x/jx/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+var (
+	waitLong = templates.LongDesc(`
+		Waits until all the required secrets are populated in the underlying Secret Manager
+`)
+
+	waitExample = templates.Examples(`
+		# waits for the secrets to be populated
+		%s secrets wait
+
+		# waits for the secrets to be populated for up to 10 minutes checking every 30 seconds
+		%s secrets wait --timeout 10m --poll-interval 30s
+	`)
+)
+
+// WaitOptions the options for waiting for the secrets to be populated
+type WaitOptions struct {
+	factory.KindResolver
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// NewCmdWait creates a command object for the command
+func NewCmdWait() (*cobra.Command, *WaitOptions) {
+	o := &WaitOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "wait",
+		Short:   "Waits until all the required secrets are populated",
+		Long:    waitLong,
+		Example: fmt.Sprintf(waitExample, common.BinaryName, common.BinaryName),
+		Run: func(cmd *cobra.Command, args []string) {
+			err := o.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().DurationVarP(&o.Timeout, "timeout", "t", 30*time.Minute, "the maximum amount of time to wait for the secrets to be populated")
+	cmd.Flags().DurationVarP(&o.PollInterval, "poll-interval", "", 10*time.Second, "the interval between checking the secrets")
+	AddKindResolverFlags(cmd, &o.KindResolver)
+	return cmd, o
+}
+
+// Run implements the command
+func (o *WaitOptions) Run() error {
+	if o.PollInterval <= 0 {
+		return util.InvalidOptionf("poll-interval", o.PollInterval.String(), "the poll interval must be positive")
+	}
+	end := time.Now().Add(o.Timeout)
+	for {
+		missing, err := o.findMissingSecrets()
+		if err != nil {
+			return err
+		}
+		if len(missing) == 0 {
+			log.Logger().Infof("secrets are populated")
+			return nil
+		}
+		if time.Now().After(end) {
+			return errors.Errorf("timed out after %s waiting for secrets: %s", o.Timeout.String(), strings.Join(missing, ", "))
+		}
+		log.Logger().Infof("waiting for secrets: %s", util.ColorInfo(strings.Join(missing, ", ")))
+		time.Sleep(o.PollInterval)
+	}
+}
+
+func (o *WaitOptions) findMissingSecrets() ([]string, error) {
+	secretsYAML, err := o.LoadSecretsYAML()
+	if err != nil {
+		return nil, err
+	}
+	return secretmgr.MissingBootSecrets(secretsYAML)
+}
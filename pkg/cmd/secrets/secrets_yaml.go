@@ -1,13 +1,20 @@
 package secrets
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
 	"github.com/jenkins-x-labs/helmboot/pkg/common"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/kms"
 	"github.com/jenkins-x/jx/pkg/cmd/helper"
 	"github.com/jenkins-x/jx/pkg/cmd/templates"
 	"github.com/jenkins-x/jx/pkg/jxfactory"
@@ -15,12 +22,18 @@ import (
 	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/yaml"
 )
 
 var (
+	// splitKeyRegex matches the top level secret keys which are safe to use in the file names of --split-by-top-level
+	splitKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 	yamlLong = templates.LongDesc(`
 		Edits all or the missing secrets and stores them in the underlying Secret Manager
 `)
@@ -33,12 +46,38 @@ var (
 
 // YAMLOptions the options for viewing running PRs
 type YAMLOptions struct {
-	JXFactory  jxfactory.Factory
-	SecretName string
-	SecretFile string
-	OutFile    string
-	BatchMode  bool
-	Verbose    bool
+	JXFactory           jxfactory.Factory
+	SecretName          string
+	Environment         string
+	SecretFile          string
+	OutFile             string
+	OutDir              string
+	ChecksumFile        string
+	ApplySecret         string
+	IgnoreFile          string
+	RootKey             string
+	Delimiter           string
+	AsUser              string
+	AsGroups            []string
+	SecretLabels        []string
+	SecretRefs          []string
+	IOFileHandles       *util.IOFileHandles
+	SplitByTopLevel     bool
+	JSONStdin           bool
+	TraceSources        bool
+	DryRun              bool
+	ForceRecreateSecret bool
+	BatchMode           bool
+	Verbose             bool
+}
+
+// SecretsChecksum the summary of a generated secrets file so that downstream steps can detect unintended changes
+type SecretsChecksum struct {
+	// Keys the number of top level secret keys
+	Keys int `json:"keys"`
+
+	// SHA256 the hex encoded SHA-256 of the generated file
+	SHA256 string `json:"sha256"`
 }
 
 // NewCmdYAML creates a command object for the command
@@ -57,7 +96,22 @@ func NewCmdYAML() (*cobra.Command, *YAMLOptions) {
 	}
 
 	cmd.Flags().StringVarP(&o.OutFile, "out", "o", "", "The output YAML file to generate")
+	cmd.Flags().StringArrayVarP(&o.SecretRefs, "secret-ref", "", nil, "a Kubernetes Secret of the form namespace/name to read the data for the secrets YAML from. Can be specified multiple times in which case the data is merged with later Secrets winning")
+	cmd.Flags().StringVarP(&o.Environment, "environment", "", "", "The name of the environment whose secrets are read from under environments.<name>.secrets in the secrets YAML of the Secret")
 	cmd.Flags().StringVarP(&o.SecretFile, "file", "f", "", "The secret file to use to get the data for the secrets YAML if using a file rather than kubernetes Secret")
+	cmd.Flags().BoolVarP(&o.JSONStdin, "json-stdin", "", false, "Reads the data for the secrets YAML from a JSON object on stdin. Nested objects are converted to dot separated keys")
+	cmd.Flags().StringVarP(&o.OutDir, "out-dir", "", "", "The output directory to generate a YAML file per top level secret when using --split-by-top-level")
+	cmd.Flags().BoolVarP(&o.SplitByTopLevel, "split-by-top-level", "", false, "Generates a separate YAML file for each top level secret in the --out-dir directory rather than a single --out file. The top level keys must only contain letters, digits, _ or - and --checksum-file is not supported")
+	cmd.Flags().BoolVarP(&o.TraceSources, "trace-sources", "", false, "Writes a sidecar file next to the generated YAML mapping each secret to the file, environment variable or Secret it came from. The values are never included")
+	cmd.Flags().StringVarP(&o.IgnoreFile, "ignore-file", "", secretmgr.SecretsIgnoreFile, "The file of glob patterns of dot separated secret keys such as 'pipelineUser.*' which are never included in the output. Ignored if it does not exist")
+	cmd.Flags().StringVarP(&o.Delimiter, "delimiter", "", secretmgr.DefaultSecretFileDelimiter, "The separator of the keys and values of the lines of the secret file such as = for 'foo.bar=value'. Use \\t or tab for tab separated values. Only the first occurrence on each line is used so values can contain the delimiter")
+	cmd.Flags().StringVarP(&o.RootKey, "root-key", "", secretmgr.DefaultSecretsRootKey, "The dot separated path to nest the secrets under in the generated YAML such as jxRequirements.secrets so it can be used as the values file of a chart")
+	cmd.Flags().StringVarP(&o.ChecksumFile, "checksum-file", "", "", "The optional file to write the number of top level secret keys and the SHA-256 of the generated YAML")
+	cmd.Flags().StringVarP(&o.ApplySecret, "apply-secret", "", "", "The name of a Kubernetes Secret in the current namespace to store the secrets YAML in rather than generating a file")
+	cmd.Flags().StringArrayVarP(&o.SecretLabels, "secret-label", "", nil, "When using --apply-secret adds the label of the form 'key=value' to the Secret so that it can be found by other tools. Can be specified multiple times")
+	cmd.Flags().BoolVarP(&o.ForceRecreateSecret, "force-recreate-secret", "", false, "When using --apply-secret deletes and recreates the Secret rather than updating it so that any stale keys are removed")
+	cmd.Flags().BoolVarP(&o.DryRun, "dry-run", "", false, "When using --apply-secret prints the Secret manifest rather than applying it")
+	clienthelpers.AddImpersonationFlags(cmd, &o.AsUser, &o.AsGroups)
 	cmd.Flags().BoolVarP(&o.Verbose, "verbose", "v", false, "enables verbose logging")
 	cmd.Flags().BoolVarP(&o.BatchMode, "batch-mode", "b", false, "Runs in batch mode without prompting for user input")
 	return cmd, o
@@ -65,49 +119,186 @@ func NewCmdYAML() (*cobra.Command, *YAMLOptions) {
 
 // Run implements the command
 func (o *YAMLOptions) Run() error {
+	err := clienthelpers.ValidateImpersonation(o.AsUser, o.AsGroups)
+	if err != nil {
+		return err
+	}
 	if o.JXFactory == nil {
 		o.JXFactory = jxfactory.NewFactory()
 	}
+	o.JXFactory = clienthelpers.WithImpersonation(o.JXFactory, o.AsUser, o.AsGroups)
 
 	kubeClient, ns, err := o.JXFactory.CreateKubeClient()
 	if err != nil {
 		return err
 	}
 
+	traceFile := ""
+	if o.TraceSources {
+		traceFile, err = o.traceSourcesFile()
+		if err != nil {
+			return err
+		}
+	}
+	data, sources, err := o.loadSecretData(kubeClient, ns)
+	if err != nil {
+		return err
+	}
+	data, err = removeIgnoredSecrets(o.IgnoreFile, data)
+	if err != nil {
+		return err
+	}
+	err = o.writeSecretsYAML(kubeClient, ns, data)
+	if err != nil || traceFile == "" {
+		return err
+	}
+	return writeSourcesTrace(traceFile, data, sources)
+}
+
+// loadSecretData loads the secret data from the configured source along with the source of each key
+func (o *YAMLOptions) loadSecretData(kubeClient kubernetes.Interface, ns string) (map[string][]byte, map[string]string, error) {
+	sources := map[string]string{}
 	secretFile := o.SecretFile
 	if secretFile == "" {
 		secretFile = os.Getenv("JXL_SECRET_FILE")
 	}
 
 	var data map[string][]byte
-	if secretFile != "" {
-		data, err = loadSecretFile(secretFile)
+	var err error
+	if o.JSONStdin {
+		if secretFile != "" || len(o.SecretRefs) > 0 {
+			return nil, nil, errors.Errorf("cannot specify --json-stdin with a secret file or --secret-ref")
+		}
+		handles := common.GetIOFileHandles(o.IOFileHandles)
+		input, err := ioutil.ReadAll(handles.In)
 		if err != nil {
-			return err
+			return nil, nil, errors.Wrap(err, "failed to read the secrets JSON from stdin")
+		}
+		data, err = secretmgr.ParseSecretJSON(input)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(data) == 0 {
+			return nil, nil, fmt.Errorf("no data in the secrets JSON from stdin")
+		}
+		addSources(sources, data, "stdin")
+	} else if len(o.SecretRefs) > 0 {
+		if secretFile != "" {
+			return nil, nil, errors.Errorf("cannot specify both a secret file and --secret-ref")
+		}
+		data, err = loadSecretRefs(kubeClient, ns, o.SecretRefs, sources)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else if secretFile != "" {
+		delimiter, err := secretmgr.ParseSecretFileDelimiter(o.Delimiter)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err = secretmgr.LoadSecretFileWithDelimiter(secretFile, delimiter)
+		if err != nil {
+			return nil, nil, err
 		}
 		if len(data) == 0 {
-			return fmt.Errorf("no data for secret file %s", secretFile)
+			return nil, nil, fmt.Errorf("no data for secret file %s", secretFile)
+		}
+		source := "file " + secretFile
+		if o.SecretFile == "" {
+			source += " from $JXL_SECRET_FILE"
 		}
+		addSources(sources, data, source)
 	} else {
 		secretName := o.SecretName
+		source := ""
 		if secretName == "" {
 			secretName = os.Getenv("JXL_SECRET_NAME")
+			if secretName != "" {
+				source = " from $JXL_SECRET_NAME"
+			}
 		}
 		if secretName == "" {
 			secretName = secretmgr.LocalSecret
 		}
+		err = secretmgr.ValidateEnvironmentName(o.Environment)
+		if err != nil {
+			return nil, nil, err
+		}
 
 		secret, err := kubeClient.CoreV1().Secrets(ns).Get(secretName, metav1.GetOptions{})
 		if err != nil {
 			if apierrors.IsNotFound(err) {
-				return fmt.Errorf("could not read Secret %s in namespace %s", secretName, ns)
+				return nil, nil, fmt.Errorf("could not read Secret %s in namespace %s", secretName, ns)
 			}
-			return errors.Wrapf(err, "failed to read Secret %s in namespace %s", secretName, ns)
+			return nil, nil, errors.Wrapf(err, "failed to read Secret %s in namespace %s", secretName, ns)
 		}
 		data = secret.Data
 		if len(data) == 0 {
-			return fmt.Errorf("no data for Secret %s in namespace %s", secretName, ns)
+			return nil, nil, fmt.Errorf("no data for Secret %s in namespace %s", secretName, ns)
 		}
+		// lets decrypt the secrets if they were encrypted with a KMS key via --kms
+		data, err = kms.DecryptSecretData(data, nil)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to decrypt Secret %s in namespace %s", secretName, ns)
+		}
+		if o.Environment != "" {
+			envYaml, err := secretmgr.EnvironmentSecretsYAML(data[secretmgr.LocalSecretKey], o.Environment)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "failed to read the secrets of environment %s from Secret %s in namespace %s", o.Environment, secretName, ns)
+			}
+			data = map[string][]byte{secretmgr.LocalSecretKey: envYaml}
+			source += " environment " + o.Environment
+		}
+		addSources(sources, data, "Secret "+ns+"/"+secretName+source)
+	}
+	return data, sources, nil
+}
+
+// removeIgnoredSecrets removes any secrets matching the patterns in the ignore file so they never leave the cluster
+func removeIgnoredSecrets(ignoreFile string, data map[string][]byte) (map[string][]byte, error) {
+	if ignoreFile == "" {
+		return data, nil
+	}
+	patterns, err := secretmgr.LoadSecretsIgnore(ignoreFile)
+	if err != nil {
+		return nil, err
+	}
+	data, omitted, err := secretmgr.RemoveIgnoredSecrets(data, patterns)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range omitted {
+		log.Logger().Infof("omitted secret %s as it matches the ignore file %s", util.ColorInfo(k), ignoreFile)
+	}
+	return data, nil
+}
+
+// writeSecretsYAML stores the secrets YAML in a Secret or generates the YAML files
+func (o *YAMLOptions) writeSecretsYAML(kubeClient kubernetes.Interface, ns string, data map[string][]byte) error {
+	if o.RootKey == "" {
+		o.RootKey = secretmgr.DefaultSecretsRootKey
+	}
+	err := secretmgr.ValidateRootKey(o.RootKey)
+	if err != nil {
+		return err
+	}
+	if o.ApplySecret != "" {
+		if o.RootKey != secretmgr.DefaultSecretsRootKey {
+			return errors.Errorf("cannot use --root-key with --apply-secret as the boot Job requires the secrets under the %s key", secretmgr.DefaultSecretsRootKey)
+		}
+		return o.applySecretsYAML(kubeClient, ns, data)
+	}
+	if o.ForceRecreateSecret {
+		return util.MissingOption("apply-secret")
+	}
+
+	if o.SplitByTopLevel {
+		if o.OutDir == "" {
+			return util.MissingOption("out-dir")
+		}
+		if o.ChecksumFile != "" {
+			return errors.Errorf("cannot use --checksum-file with --split-by-top-level as the checksum is of a single generated file")
+		}
+		return generateSplitSecretsYAML(o.OutDir, o.RootKey, data)
 	}
 
 	if o.OutFile == "" {
@@ -116,60 +307,346 @@ func (o *YAMLOptions) Run() error {
 	if o.OutFile == "" {
 		return util.MissingOption("out")
 	}
-	return generateSecretsYAML(o.OutFile, data)
+	return generateSecretsYAML(o.OutFile, o.ChecksumFile, o.RootKey, data)
 }
 
-// loadSecretFile loads a secret file of lines of the form "foo: bar"
-func loadSecretFile(fileName string) (map[string][]byte, error) {
-	exists, err := util.FileExists(fileName)
+// applySecretsYAML stores the secrets YAML in the Secret so it can be used directly in the cluster
+// or prints the Secret manifest if using dry run
+func (o *YAMLOptions) applySecretsYAML(kubeClient kubernetes.Interface, ns string, secretData map[string][]byte) error {
+	labels, err := o.secretLabels()
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to check if secret file %s exists", fileName)
+		return err
+	}
+	data, err := secretmgr.SecretDataToYAML(secretData)
+	if err != nil {
+		return err
+	}
+	name := o.ApplySecret
+	secret := &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels:    labels,
+		},
+		Data: map[string][]byte{
+			secretmgr.LocalSecretKey: data,
+		},
 	}
 
-	if !exists {
-		return nil, errors.Errorf("secret file %s does not exist", fileName)
+	if o.DryRun {
+		manifest, err := yaml.Marshal(secret)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal Secret %s to YAML", name)
+		}
+		_, err = fmt.Fprint(os.Stdout, string(manifest))
+		return err
 	}
 
-	answer := map[string][]byte{}
-	data, err := ioutil.ReadFile(fileName)
+	secrets := kubeClient.CoreV1().Secrets(ns)
+	current, err := secrets.Get(name, metav1.GetOptions{})
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to load secret file %s exists", fileName)
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get Secret %s in namespace %s", name, ns)
+		}
+		current = nil
 	}
-	for _, l := range strings.Split(string(data), "\n") {
-		line := strings.TrimSpace(l)
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
+	if current != nil && o.ForceRecreateSecret {
+		if !o.BatchMode {
+			confirm, err := util.Confirm(fmt.Sprintf("You are about to delete and recreate the Secret %s in namespace %s. Are you sure?", name, ns), false, "Any keys in the Secret other than the secrets YAML will be removed", common.GetIOFileHandles(nil))
+			if err != nil {
+				return err
+			}
+			if !confirm {
+				return nil
+			}
 		}
-		entry := strings.SplitN(line, ":", 2)
-		if len(entry) == 2 {
-			answer[strings.TrimSpace(entry[0])] = []byte(strings.TrimSpace(entry[1]))
+		err = secrets.Delete(name, &metav1.DeleteOptions{})
+		if err != nil {
+			return errors.Wrapf(err, "failed to delete Secret %s in namespace %s", name, ns)
 		}
+		log.Logger().Infof("deleted Secret %s in namespace %s so it can be recreated", util.ColorInfo(name), util.ColorInfo(ns))
+		current = nil
 	}
-	return answer, nil
-}
-
-func generateSecretsYAML(fileName string, secretData map[string][]byte) error {
-	var err error
-	data := secretData[secretmgr.LocalSecretKey]
-	if len(data) == 0 {
-		secrets := map[string]interface{}{}
-		for k, v := range secretData {
-			util.SetMapValueViaPath(secrets, k, string(v))
+	if current == nil {
+		_, err = secrets.Create(secret)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create Secret %s in namespace %s", name, ns)
 		}
-		values := map[string]interface{}{
-			"secrets": secrets,
+	} else {
+		if current.Data == nil {
+			current.Data = map[string][]byte{}
 		}
-
-		data, err = yaml.Marshal(values)
+		current.Data[secretmgr.LocalSecretKey] = data
+		if current.Labels == nil {
+			current.Labels = map[string]string{}
+		}
+		for k, v := range labels {
+			current.Labels[k] = v
+		}
+		_, err = secrets.Update(current)
 		if err != nil {
-			return errors.Wrap(err, "failed to marshal data to YAML")
+			return errors.Wrapf(err, "failed to update Secret %s in namespace %s", name, ns)
 		}
 	}
+	log.Logger().Infof("stored the secrets YAML in Secret %s in namespace %s", util.ColorInfo(name), util.ColorInfo(ns))
+	return nil
+}
+
+func generateSecretsYAML(fileName string, checksumFile string, rootKey string, secretData map[string][]byte) error {
+	data, err := secretmgr.SecretDataToYAMLWithRootKey(secretData, rootKey)
+	if err != nil {
+		return err
+	}
 
 	err = ioutil.WriteFile(fileName, data, util.DefaultFileWritePermissions)
 	if err != nil {
 		return errors.Wrapf(err, "failed to save file %s", fileName)
 	}
 	log.Logger().Infof("generated secrets file %s", util.ColorInfo(fileName))
+
+	checksum, err := toSecretsChecksum(data, rootKey)
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("secrets file %s has %d top level keys and SHA-256 %s", fileName, checksum.Keys, util.ColorInfo(checksum.SHA256))
+	if checksumFile == "" {
+		return nil
+	}
+	checksumData, err := yaml.Marshal(checksum)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal checksum to YAML")
+	}
+	err = ioutil.WriteFile(checksumFile, checksumData, util.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save checksum file %s", checksumFile)
+	}
 	return nil
 }
+
+// toSecretsChecksum counts the top level secret keys and calculates the checksum of the given secrets YAML
+func toSecretsChecksum(data []byte, rootKey string) (*SecretsChecksum, error) {
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal(data, &values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal secrets YAML")
+	}
+	keys := 0
+	secrets, ok := util.GetMapValueViaPath(values, rootKey).(map[string]interface{})
+	if ok {
+		keys = len(secrets)
+	}
+	sum := sha256.Sum256(data)
+	return &SecretsChecksum{
+		Keys:   keys,
+		SHA256: hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// generateSplitSecretsYAML generates a secrets YAML file for each top level secret in the given directory
+// such as secrets.pipelineUser.yaml
+func generateSplitSecretsYAML(dir string, rootKey string, secretData map[string][]byte) error {
+	data, err := secretmgr.SecretDataToYAML(secretData)
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &values)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal secrets YAML")
+	}
+	secrets, ok := values[secretmgr.DefaultSecretsRootKey].(map[string]interface{})
+	if !ok || len(secrets) == 0 {
+		return fmt.Errorf("no secrets found to split")
+	}
+
+	keys := make([]string, 0, len(secrets))
+	for k := range secrets {
+		if !splitKeyRegex.MatchString(k) {
+			return errors.Errorf("cannot split the secret %q into its own file as the top level key must only contain letters, digits, '_' or '-'", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	err = os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create directory %s", dir)
+	}
+
+	for _, k := range keys {
+		group := map[string]interface{}{}
+		util.SetMapValueViaPath(group, rootKey, map[string]interface{}{
+			k: secrets[k],
+		})
+		groupData, err := yaml.Marshal(group)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal secret %s to YAML", k)
+		}
+		fileName := filepath.Join(dir, fmt.Sprintf("secrets.%s.yaml", k))
+		err = ioutil.WriteFile(fileName, groupData, util.DefaultFileWritePermissions)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save file %s", fileName)
+		}
+		log.Logger().Infof("generated secrets file %s", util.ColorInfo(fileName))
+	}
+	return nil
+}
+
+// addSources records the source of each key of the data replacing any previous source
+func addSources(sources map[string]string, data map[string][]byte, source string) {
+	for k := range data {
+		sources[k] = source
+	}
+}
+
+// traceSourcesFile returns the sidecar file to write the source of each secret to
+func (o *YAMLOptions) traceSourcesFile() (string, error) {
+	if o.ApplySecret != "" {
+		return "", errors.Errorf("cannot use --trace-sources with --apply-secret")
+	}
+	if o.SplitByTopLevel {
+		if o.OutDir == "" {
+			return "", util.MissingOption("out-dir")
+		}
+		return filepath.Join(o.OutDir, "sources.yaml"), nil
+	}
+	outFile := o.OutFile
+	if outFile == "" {
+		outFile = os.Getenv("JX_SECRETS_YAML")
+	}
+	if outFile == "" {
+		return "", util.MissingOption("out")
+	}
+	return strings.TrimSuffix(outFile, filepath.Ext(outFile)) + ".sources.yaml", nil
+}
+
+// writeSourcesTrace writes the file mapping the path of each secret to its source. The values are never included
+func writeSourcesTrace(fileName string, data map[string][]byte, sources map[string]string) error {
+	trace := map[string]string{}
+	for k, v := range data {
+		source := sources[k]
+		if k != secretmgr.LocalSecretKey {
+			trace[k] = source
+			continue
+		}
+		values, err := secretmgr.UnmarshalSecretsYAML(string(v))
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse the secrets YAML from %s", source)
+		}
+		for _, path := range secretmgr.SecretPaths(values) {
+			// lets prefer the source of each path if the secrets YAML was merged from several sources
+			if pathSource, ok := sources[path]; ok {
+				trace[path] = pathSource
+			} else {
+				trace[path] = source
+			}
+		}
+	}
+	out, err := yaml.Marshal(trace)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the secret sources to YAML")
+	}
+	err = ioutil.WriteFile(fileName, out, util.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save file %s", fileName)
+	}
+	log.Logger().Infof("saved the source of each secret to %s", util.ColorInfo(fileName))
+	return nil
+}
+
+// loadSecretRefs loads and merges the data of the Secrets of the form namespace/name with later Secrets winning.
+// If no namespace is specified the current namespace is used. If any Secret contains a secrets YAML document the
+// documents and the keys of the other Secrets are deep merged into a single secrets YAML document
+func loadSecretRefs(kubeClient kubernetes.Interface, currentNS string, refs []string, sources map[string]string) (map[string][]byte, error) {
+	answer := map[string][]byte{}
+	mergedYAML := ""
+	hasYAML := false
+	for _, ref := range refs {
+		ns := currentNS
+		name := ref
+		values := strings.Split(ref, "/")
+		if len(values) == 2 {
+			ns = values[0]
+			name = values[1]
+		}
+		if len(values) > 2 || ns == "" || name == "" {
+			return nil, util.InvalidOptionf("secret-ref", ref, "the secret must be of the form namespace/name")
+		}
+		secret, err := kubeClient.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, fmt.Errorf("could not find Secret %s in namespace %s for --secret-ref %s", name, ns, ref)
+			}
+			return nil, errors.Wrapf(err, "failed to read Secret %s in namespace %s for --secret-ref %s", name, ns, ref)
+		}
+		source := "Secret " + ns + "/" + name
+		flat := map[string][]byte{}
+		for k, v := range secret.Data {
+			if k != secretmgr.LocalSecretKey {
+				flat[k] = v
+				answer[k] = v
+			}
+		}
+		addSources(sources, flat, source)
+
+		secretsYAML := secret.Data[secretmgr.LocalSecretKey]
+		if len(secretsYAML) > 0 {
+			hasYAML = true
+			values, err := secretmgr.UnmarshalSecretsYAML(string(secretsYAML))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse the secrets YAML of Secret %s in namespace %s for --secret-ref %s", name, ns, ref)
+			}
+			for _, path := range secretmgr.SecretPaths(values) {
+				sources[path] = source
+			}
+			mergedYAML, err = secretmgr.MergeSecretsYAML(mergedYAML, string(secretsYAML))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to merge the secrets YAML of Secret %s in namespace %s for --secret-ref %s", name, ns, ref)
+			}
+		}
+		if len(flat) > 0 {
+			flatYAML, err := secretmgr.SecretDataToYAML(flat)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid keys in Secret %s in namespace %s for --secret-ref %s", name, ns, ref)
+			}
+			mergedYAML, err = secretmgr.MergeSecretsYAML(mergedYAML, string(flatYAML))
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to merge the keys of Secret %s in namespace %s for --secret-ref %s", name, ns, ref)
+			}
+		}
+	}
+	if hasYAML {
+		return map[string][]byte{secretmgr.LocalSecretKey: []byte(mergedYAML)}, nil
+	}
+	if len(answer) == 0 {
+		return nil, fmt.Errorf("no data for the Secrets %s", strings.Join(refs, ", "))
+	}
+	return answer, nil
+}
+
+// secretLabels returns the labels to add to the applied Secret including the default managed by label
+func (o *YAMLOptions) secretLabels() (map[string]string, error) {
+	labels := map[string]string{
+		secretmgr.LabelManagedBy: secretmgr.ManagedByHelmboot,
+	}
+	for _, l := range o.SecretLabels {
+		values := strings.SplitN(l, "=", 2)
+		if len(values) != 2 {
+			return nil, util.InvalidOptionf("secret-label", l, "labels must be of the form 'key=value'")
+		}
+		key := strings.TrimSpace(values[0])
+		value := strings.TrimSpace(values[1])
+		if msgs := validation.IsQualifiedName(key); len(msgs) > 0 {
+			return nil, util.InvalidOptionf("secret-label", l, "invalid label key: %s", strings.Join(msgs, ", "))
+		}
+		if msgs := validation.IsValidLabelValue(value); len(msgs) > 0 {
+			return nil, util.InvalidOptionf("secret-label", l, "invalid label value: %s", strings.Join(msgs, ", "))
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
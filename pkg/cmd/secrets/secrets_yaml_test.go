@@ -1,7 +1,11 @@
 package secrets_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -17,6 +21,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	sigyaml "sigs.k8s.io/yaml"
 )
 
 const (
@@ -76,6 +81,168 @@ func TestSecretsYAMLWithEntries(t *testing.T) {
 	assertGeneratedYAMLFileIsValid(t, outFileName)
 }
 
+func TestSecretsYAMLWithJSONStdin(t *testing.T) {
+	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	outFileName := outFile.Name()
+
+	_, yo := secrets.NewCmdYAML()
+
+	input := fmt.Sprintf(`{
+  "adminUser": {"username": %q, "password": %q},
+  "hmacToken": %q,
+  "pipelineUser": {"username": %q, "token": %q, "email": %q}
+}`, expectedAdminUser, expectedAdminPassword, expectedHmacToken, expectedPipelineUser, expectedPipelineToken, expectedPipelineEmail)
+
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutFile = outFileName
+	yo.JSONStdin = true
+	yo.IOFileHandles = &util.IOFileHandles{
+		In:  stdinFile(t, input),
+		Out: os.Stdout,
+		Err: os.Stderr,
+	}
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	assertGeneratedYAMLFileIsValid(t, outFileName)
+
+	yo.IOFileHandles.In = stdinFile(t, `{"adminUser": `)
+	err = yo.Run()
+	require.Error(t, err, "should have failed for malformed JSON")
+}
+
+// stdinFile returns a file containing the given text to use as stdin
+func stdinFile(t *testing.T, text string) *os.File {
+	f, err := ioutil.TempFile("", "test-helmboot-stdin-")
+	require.NoError(t, err, "failed to create a temporary file")
+	_, err = f.WriteString(text)
+	require.NoError(t, err, "failed to write to file %s", f.Name())
+	_, err = f.Seek(0, 0)
+	require.NoError(t, err, "failed to seek file %s", f.Name())
+	return f
+}
+
+func TestSecretsYAMLWithSecretRefs(t *testing.T) {
+	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	outFileName := outFile.Name()
+
+	_, yo := secrets.NewCmdYAML()
+
+	ns := "jx"
+	userData := map[string][]byte{}
+	tokenData := map[string][]byte{}
+	for k, v := range testSecretData {
+		if strings.HasPrefix(k, "adminUser.") {
+			userData[k] = v
+		} else {
+			tokenData[k] = v
+		}
+	}
+	// the later Secret should win
+	userData["adminUser.username"] = []byte("overridden")
+	tokenData["adminUser.username"] = []byte(expectedAdminUser)
+	k8sObjects := []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "users",
+				Namespace: "other",
+			},
+			Data: userData,
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "tokens",
+				Namespace: ns,
+			},
+			Data: tokenData,
+		},
+	}
+	f := fakejxfactory.NewFakeFactoryWithObjects(k8sObjects, nil, ns)
+	yo.JXFactory = f
+	yo.OutFile = outFileName
+	yo.SecretRefs = []string{"other/users", "tokens"}
+	yo.TraceSources = true
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	assertGeneratedYAMLFileIsValid(t, outFileName)
+
+	traceFile := strings.TrimSuffix(outFileName, filepath.Ext(outFileName)) + ".sources.yaml"
+	data, err := ioutil.ReadFile(traceFile)
+	require.NoError(t, err, "failed to read the sources trace file %s", traceFile)
+	trace := map[string]string{}
+	require.NoError(t, sigyaml.Unmarshal(data, &trace), "failed to parse the sources trace")
+	assert.Equal(t, "Secret jx/tokens", trace["adminUser.username"], "the later Secret should be the source")
+	assert.Equal(t, "Secret other/users", trace["adminUser.password"], "source of adminUser.password")
+	assert.Equal(t, "Secret jx/tokens", trace["hmacToken"], "source of hmacToken")
+	assert.NotContains(t, string(data), expectedAdminPassword, "the trace should not contain the values")
+
+	yo.SecretRefs = []string{"other/users", "other/missing"}
+	err = yo.Run()
+	require.Error(t, err, "should have failed for a missing Secret")
+	assert.Contains(t, err.Error(), "other/missing")
+}
+
+func TestSecretsYAMLWithSecretRefsContainingSecretsYAML(t *testing.T) {
+	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary file")
+	outFileName := outFile.Name()
+
+	_, yo := secrets.NewCmdYAML()
+
+	ns := "jx"
+	k8sObjects := []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "base",
+				Namespace: ns,
+			},
+			Data: map[string][]byte{
+				secretmgr.LocalSecretKey: []byte("secrets:\n  adminUser:\n    username: admin\n    password: base-password\n  hmacToken: base-token\n"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "overrides",
+				Namespace: ns,
+			},
+			Data: map[string][]byte{
+				secretmgr.LocalSecretKey: []byte("secrets:\n  adminUser:\n    password: override-password\n"),
+				"pipelineUser.token":     []byte("pipeline-token"),
+			},
+		},
+	}
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(k8sObjects, nil, ns)
+	yo.OutFile = outFileName
+	yo.SecretRefs = []string{"base", "overrides"}
+	yo.TraceSources = true
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	data, err := ioutil.ReadFile(outFileName)
+	require.NoErrorf(t, err, "failed to load generated YAML")
+	expected := `secrets:
+  adminUser:
+    password: override-password
+    username: admin
+  hmacToken: base-token
+  pipelineUser:
+    token: pipeline-token
+`
+	assert.Equal(t, expected, string(data), "should have deep merged the secrets YAML of the Secrets")
+
+	traceFile := strings.TrimSuffix(outFileName, filepath.Ext(outFileName)) + ".sources.yaml"
+	data, err = ioutil.ReadFile(traceFile)
+	require.NoError(t, err, "failed to read the sources trace file %s", traceFile)
+	trace := map[string]string{}
+	require.NoError(t, sigyaml.Unmarshal(data, &trace), "failed to parse the sources trace")
+	assert.Equal(t, "Secret jx/base", trace["adminUser.username"], "source of adminUser.username")
+	assert.Equal(t, "Secret jx/overrides", trace["adminUser.password"], "source of adminUser.password")
+	assert.Equal(t, "Secret jx/overrides", trace["pipelineUser.token"], "source of pipelineUser.token")
+}
+
 func assertGeneratedYAMLFileIsValid(t *testing.T, outFileName string) {
 	assert.FileExists(t, outFileName, "did not generate output YAML file")
 	data, err := ioutil.ReadFile(outFileName)
@@ -144,6 +311,38 @@ func TestSecretsYAMLWithYAML(t *testing.T) {
 	}
 }
 
+func TestSecretsYAMLWithEnvironment(t *testing.T) {
+	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary file")
+	outFileName := outFile.Name()
+
+	_, yo := secrets.NewCmdYAML()
+
+	ns := "jx"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretmgr.LocalSecret,
+			Namespace: ns,
+		},
+		Data: map[string][]byte{
+			secretmgr.LocalSecretKey: []byte("secrets:\n  hmacToken: default-token\nenvironments:\n  staging:\n    secrets:\n      hmacToken: staging-token\n"),
+		},
+	}
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects([]runtime.Object{secret}, nil, ns)
+	yo.Environment = "staging"
+	yo.OutFile = outFileName
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	data, err := ioutil.ReadFile(outFileName)
+	require.NoErrorf(t, err, "failed to load generated YAML")
+	assert.Equal(t, "secrets:\n  hmacToken: staging-token\n", string(data), "should have generated the secrets of the environment")
+
+	yo.Environment = "production"
+	err = yo.Run()
+	require.Error(t, err, "should fail for an environment without secrets")
+}
+
 func TestSecretsYAMLFromFile(t *testing.T) {
 	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
 	require.NoError(t, err, "failed to create a temporary dir")
@@ -177,3 +376,306 @@ func convertMaps(sm map[interface{}]interface{}) map[string]interface{} {
 	}
 	return sm2
 }
+
+func TestSecretsYAMLFromFileIsSorted(t *testing.T) {
+	expected := `secrets:
+  adminUser:
+    password: dummypwd
+    username: someuser
+  hmacToken: TODO
+  pipelineUser:
+    email: me@foo.com
+    token: dummmytoken
+    username: somepipelineuser
+`
+
+	var previous string
+	for i := 0; i < 5; i++ {
+		outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+		require.NoError(t, err, "failed to create a temporary dir")
+		outFileName := outFile.Name()
+
+		_, yo := secrets.NewCmdYAML()
+		yo.SecretFile = filepath.Join("test_data", "sample_secrets.txt")
+		yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+		yo.OutFile = outFileName
+		err = yo.Run()
+		require.NoErrorf(t, err, "should not have failed to create YAML")
+
+		data, err := ioutil.ReadFile(outFileName)
+		require.NoErrorf(t, err, "failed to load generated YAML")
+		got := string(data)
+
+		assert.Equal(t, expected, got, "generated YAML for run %d", i)
+		if i > 0 {
+			assert.Equal(t, previous, got, "generated YAML should be identical across runs")
+		}
+		previous = got
+	}
+}
+
+func TestSecretsYAMLChecksumFile(t *testing.T) {
+	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary file")
+	outFileName := outFile.Name()
+
+	checksumFile, err := ioutil.TempFile("", "test-helmboot-secret-checksum-")
+	require.NoError(t, err, "failed to create a temporary file")
+	checksumFileName := checksumFile.Name()
+
+	_, yo := secrets.NewCmdYAML()
+	yo.SecretFile = filepath.Join("test_data", "sample_secrets.txt")
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutFile = outFileName
+	yo.ChecksumFile = checksumFileName
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	data, err := ioutil.ReadFile(outFileName)
+	require.NoErrorf(t, err, "failed to load generated YAML")
+	sum := sha256.Sum256(data)
+
+	checksumData, err := ioutil.ReadFile(checksumFileName)
+	require.NoErrorf(t, err, "failed to load generated checksum file")
+
+	checksum := &secrets.SecretsChecksum{}
+	err = sigyaml.Unmarshal(checksumData, checksum)
+	require.NoErrorf(t, err, "failed to unmarshal checksum file")
+
+	assert.Equal(t, 3, checksum.Keys, "number of top level keys")
+	assert.Equal(t, hex.EncodeToString(sum[:]), checksum.SHA256, "checksum")
+}
+
+func TestSecretsYAMLSplitByTopLevel(t *testing.T) {
+	outDir, err := ioutil.TempDir("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary dir")
+
+	_, yo := secrets.NewCmdYAML()
+	yo.SecretFile = filepath.Join("test_data", "sample_secrets.txt")
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutDir = outDir
+	yo.SplitByTopLevel = true
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	expectedFiles := map[string]string{
+		"secrets.adminUser.yaml": `secrets:
+  adminUser:
+    password: dummypwd
+    username: someuser
+`,
+		"secrets.hmacToken.yaml": `secrets:
+  hmacToken: TODO
+`,
+		"secrets.pipelineUser.yaml": `secrets:
+  pipelineUser:
+    email: me@foo.com
+    token: dummmytoken
+    username: somepipelineuser
+`,
+	}
+	for name, expected := range expectedFiles {
+		data, err := ioutil.ReadFile(filepath.Join(outDir, name))
+		require.NoErrorf(t, err, "failed to load generated file %s", name)
+		assert.Equal(t, expected, string(data), "generated file %s", name)
+	}
+}
+
+func TestSecretsYAMLSplitByTopLevelRejectsUnsafeKeys(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	defer os.RemoveAll(tmpDir)
+
+	secretFile := filepath.Join(tmpDir, "secrets.txt")
+	err = ioutil.WriteFile(secretFile, []byte("hmacToken:TODO\nescaped/token:dummytoken\n"), 0600)
+	require.NoError(t, err, "failed to save the secret file")
+	outDir := filepath.Join(tmpDir, "out")
+
+	_, yo := secrets.NewCmdYAML()
+	yo.SecretFile = secretFile
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutDir = outDir
+	yo.SplitByTopLevel = true
+	err = yo.Run()
+	require.Error(t, err, "should have failed to split a key containing a path")
+	assert.Contains(t, err.Error(), "cannot split the secret", "error message")
+	_, err = os.Stat(outDir)
+	assert.True(t, os.IsNotExist(err), "should not have generated any files")
+
+	_, yo = secrets.NewCmdYAML()
+	yo.SecretFile = filepath.Join("test_data", "sample_secrets.txt")
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutDir = outDir
+	yo.SplitByTopLevel = true
+	yo.ChecksumFile = filepath.Join(tmpDir, "checksum.yaml")
+	err = yo.Run()
+	assert.Error(t, err, "should have failed to use --checksum-file with --split-by-top-level")
+}
+
+func TestSecretsYAMLRootKey(t *testing.T) {
+	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary file")
+	outFileName := outFile.Name()
+
+	_, yo := secrets.NewCmdYAML()
+	yo.SecretFile = filepath.Join("test_data", "sample_secrets.txt")
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutFile = outFileName
+	yo.RootKey = "jxRequirements.secrets"
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	data, err := ioutil.ReadFile(outFileName)
+	require.NoErrorf(t, err, "failed to load generated YAML")
+	values := map[string]interface{}{}
+	err = sigyaml.Unmarshal(data, &values)
+	require.NoErrorf(t, err, "failed to unmarshal generated YAML")
+
+	assert.Equal(t, "dummypwd", util.GetMapValueViaPath(values, "jxRequirements.secrets.adminUser.password"), "nested admin password")
+	assert.Nil(t, values["secrets"], "should not have a top level secrets key")
+
+	_, yo = secrets.NewCmdYAML()
+	yo.SecretFile = filepath.Join("test_data", "sample_secrets.txt")
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutFile = outFileName
+	yo.RootKey = "jxRequirements..secrets"
+	err = yo.Run()
+	assert.Error(t, err, "should have failed for an empty path segment")
+}
+
+func TestSecretsYAMLIgnoreFile(t *testing.T) {
+	outFile, err := ioutil.TempFile("", "test-helmboot-secret-yaml-")
+	require.NoError(t, err, "failed to create a temporary file")
+	outFileName := outFile.Name()
+
+	ignoreFile, err := ioutil.TempFile("", "test-helmboot-secretsignore-")
+	require.NoError(t, err, "failed to create a temporary file")
+	ignoreFileName := ignoreFile.Name()
+	err = ioutil.WriteFile(ignoreFileName, []byte("# never share the pipeline user\npipelineUser\nhmacToken\n"), 0600)
+	require.NoError(t, err, "failed to write the ignore file")
+
+	_, yo := secrets.NewCmdYAML()
+	yo.SecretFile = filepath.Join("test_data", "sample_secrets.txt")
+	yo.JXFactory = fakejxfactory.NewFakeFactoryWithObjects(nil, nil, "jx")
+	yo.OutFile = outFileName
+	yo.IgnoreFile = ignoreFileName
+	err = yo.Run()
+	require.NoErrorf(t, err, "should not have failed to create YAML")
+
+	data, err := ioutil.ReadFile(outFileName)
+	require.NoErrorf(t, err, "failed to load generated YAML")
+	assert.Equal(t, `secrets:
+  adminUser:
+    password: dummypwd
+    username: someuser
+`, string(data), "generated YAML")
+}
+
+func TestSecretsYAMLApplySecret(t *testing.T) {
+	_, yo := secrets.NewCmdYAML()
+
+	ns := "jx"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretmgr.LocalSecret,
+			Namespace: ns,
+		},
+		Data: testSecretData,
+	}
+	f := fakejxfactory.NewFakeFactoryWithObjects([]runtime.Object{secret}, nil, ns)
+	yo.JXFactory = f
+	yo.ApplySecret = "my-secrets-yaml"
+	err := yo.Run()
+	require.NoErrorf(t, err, "should not have failed to apply the secrets YAML")
+
+	kubeClient, _, err := f.CreateKubeClient()
+	require.NoError(t, err, "failed to create kube client")
+	applied, err := kubeClient.CoreV1().Secrets(ns).Get(yo.ApplySecret, metav1.GetOptions{})
+	require.NoError(t, err, "failed to get the applied Secret %s", yo.ApplySecret)
+
+	actual := string(applied.Data[secretmgr.LocalSecretKey])
+	assert.Contains(t, actual, "secrets:", "applied secrets YAML")
+	assert.Contains(t, actual, "username: "+expectedPipelineUser, "applied secrets YAML")
+}
+
+func TestSecretsYAMLForceRecreateSecret(t *testing.T) {
+	_, yo := secrets.NewCmdYAML()
+
+	ns := "jx"
+	name := "my-secrets-yaml"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretmgr.LocalSecret,
+			Namespace: ns,
+		},
+		Data: testSecretData,
+	}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+		},
+		Data: map[string][]byte{
+			"stale.yaml": []byte("old: value"),
+		},
+	}
+	f := fakejxfactory.NewFakeFactoryWithObjects([]runtime.Object{secret, existing}, nil, ns)
+	yo.JXFactory = f
+	yo.ApplySecret = name
+	yo.ForceRecreateSecret = true
+	yo.BatchMode = true
+	err := yo.Run()
+	require.NoErrorf(t, err, "should not have failed to recreate the Secret")
+
+	kubeClient, _, err := f.CreateKubeClient()
+	require.NoError(t, err, "failed to create kube client")
+	applied, err := kubeClient.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
+	require.NoError(t, err, "failed to get the recreated Secret %s", name)
+
+	assert.NotContains(t, applied.Data, "stale.yaml", "should have removed the stale key")
+	assert.Contains(t, applied.Data, secretmgr.LocalSecretKey, "should have the secrets YAML")
+}
+
+func TestSecretsYAMLApplySecretLabels(t *testing.T) {
+	_, yo := secrets.NewCmdYAML()
+
+	ns := "jx"
+	name := "my-secrets-yaml"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretmgr.LocalSecret,
+			Namespace: ns,
+		},
+		Data: testSecretData,
+	}
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: ns,
+			Labels: map[string]string{
+				"backup": "daily",
+			},
+		},
+	}
+	f := fakejxfactory.NewFakeFactoryWithObjects([]runtime.Object{secret, existing}, nil, ns)
+	yo.JXFactory = f
+	yo.ApplySecret = name
+	yo.SecretLabels = []string{"team=platform"}
+	err := yo.Run()
+	require.NoErrorf(t, err, "should not have failed to apply the secrets YAML")
+
+	kubeClient, _, err := f.CreateKubeClient()
+	require.NoError(t, err, "failed to create kube client")
+	applied, err := kubeClient.CoreV1().Secrets(ns).Get(name, metav1.GetOptions{})
+	require.NoError(t, err, "failed to get the applied Secret %s", name)
+
+	assert.Equal(t, map[string]string{
+		"backup":                 "daily",
+		"team":                   "platform",
+		secretmgr.LabelManagedBy: secretmgr.ManagedByHelmboot,
+	}, applied.Labels, "labels of the applied Secret")
+
+	yo.SecretLabels = []string{"not a label"}
+	err = yo.Run()
+	require.Error(t, err, "should have failed for an invalid label")
+}
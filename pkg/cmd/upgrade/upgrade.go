@@ -246,7 +246,7 @@ func (o *UpgradeOptions) addMissingFiles(dir string) error {
 			if err != nil {
 				return errors.Wrap(err, "failed to create temp dir")
 			}
-			err = o.Gitter.Clone(o.InitialGitURL, dir)
+			err = githelpers.CloneWithProgress(o.Gitter, o.InitialGitURL, dir)
 			if err != nil {
 				return errors.Wrapf(err, "failed to git clone %s", o.InitialGitURL)
 			}
@@ -395,7 +395,7 @@ func (o *UpgradeOptions) gitCloneIfRequired(gitter gits.Gitter, devSource v1.Env
 
 	log.Logger().Debugf("cloning %s to directory %s", util.ColorInfo(gitURL), util.ColorInfo(dir))
 
-	err = gitter.Clone(gitURL, dir)
+	err = githelpers.CloneWithProgress(gitter, gitURL, dir)
 	if err != nil {
 		return "", errors.Wrapf(err, "failed to clone repository %s to directory: %s", gitURL, dir)
 	}
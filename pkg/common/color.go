@@ -0,0 +1,36 @@
+package common
+
+import (
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+// OptionNoColor the flag used to disable colored output
+const OptionNoColor = "no-color"
+
+// noColor whether colored output was disabled via the flag
+var noColor bool
+
+// AddColorFlags adds the flag to disable colored output to the command and all of its sub commands
+func AddColorFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVarP(&noColor, OptionNoColor, "", false, "disables colored output. Colors are also disabled if the $NO_COLOR environment variable is set or the output is not a terminal")
+}
+
+// ConfigureColor disables colored output globally if the no-color flag is specified, the $NO_COLOR
+// environment variable is set or the standard output is not a terminal so that captured logs are clean
+func ConfigureColor() {
+	if ColorDisabled(noColor, os.Getenv("NO_COLOR"), os.Stdout.Fd()) {
+		color.NoColor = true
+	}
+}
+
+// ColorDisabled returns true if colored output should be disabled for the given flag, $NO_COLOR value and output
+func ColorDisabled(flag bool, noColorEnv string, fd uintptr) bool {
+	if flag || noColorEnv != "" {
+		return true
+	}
+	return !isatty.IsTerminal(fd) && !isatty.IsCygwinTerminal(fd)
+}
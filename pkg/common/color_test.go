@@ -0,0 +1,22 @@
+package common_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColorDisabled(t *testing.T) {
+	f, err := ioutil.TempFile("", "test-helmboot-color-")
+	require.NoError(t, err, "failed to create a temporary file")
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	assert.True(t, common.ColorDisabled(true, "", f.Fd()), "with the flag")
+	assert.True(t, common.ColorDisabled(false, "1", f.Fd()), "with $NO_COLOR")
+	assert.True(t, common.ColorDisabled(false, "", f.Fd()), "when writing to a file")
+}
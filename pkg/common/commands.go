@@ -0,0 +1,49 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// ErrCommandTimeout the error returned when a command does not complete within its timeout
+var ErrCommandTimeout = errors.New("command timed out")
+
+// RunCommandWithTimeout runs the command killing it if it does not complete within the timeout in which case
+// an error with a cause of ErrCommandTimeout is returned. A zero timeout means wait forever
+func RunCommandWithTimeout(c util.Command, timeout time.Duration) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	commandLine := strings.TrimSpace(fmt.Sprintf("%s %s", c.Name, strings.Join(c.Args, " ")))
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range c.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	text := strings.TrimSpace(out.String())
+	if ctx.Err() == context.DeadlineExceeded {
+		return text, errors.Wrapf(ErrCommandTimeout, "%s did not complete within %s", commandLine, timeout.String())
+	}
+	if err != nil {
+		return text, errors.Wrapf(err, "failed to run %s: %s", commandLine, text)
+	}
+	return text, nil
+}
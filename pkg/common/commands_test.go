@@ -0,0 +1,26 @@
+package common_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunCommandWithTimeout(t *testing.T) {
+	text, err := common.RunCommandWithTimeout(util.Command{Name: "echo", Args: []string{"hello"}}, time.Minute)
+	require.NoError(t, err, "should not have failed to run echo")
+	assert.Equal(t, "hello", text)
+
+	_, err = common.RunCommandWithTimeout(util.Command{Name: "sleep", Args: []string{"10"}}, 100*time.Millisecond)
+	require.Error(t, err, "should have timed out")
+	assert.Equal(t, common.ErrCommandTimeout, errors.Cause(err), "error cause")
+
+	_, err = common.RunCommandWithTimeout(util.Command{Name: "false"}, time.Minute)
+	require.Error(t, err, "should have failed")
+	assert.NotEqual(t, common.ErrCommandTimeout, errors.Cause(err), "error cause")
+}
@@ -1,13 +1,17 @@
 package common
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 
+	"github.com/jenkins-x-labs/helmboot/pkg/bootconfig"
 	"github.com/jenkins-x/jx/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 // BinaryName the binary name to use in help docs
@@ -16,6 +20,12 @@ var BinaryName string
 // TopLevelCommand the top level command name
 var TopLevelCommand string
 
+// OptionQuiet the flag used to only log warnings and errors
+const OptionQuiet = "quiet"
+
+// quiet whether only warnings, errors and results are logged
+var quiet bool
+
 func init() {
 	BinaryName = os.Getenv("BINARY_NAME")
 	if BinaryName == "" {
@@ -27,15 +37,18 @@ func init() {
 	}
 }
 
+// SetLoggingLevel sets the logging level from the $JX_LOG_LEVEL environment variable, the quiet flag
+// (or $JX_QUIET environment variable) or the verbose flag (or $JX_VERBOSE environment variable) in that order of precedence.
+// If the quiet or verbose flags are not specified via a flag or environment variable they default from the optional configuration file
 func SetLoggingLevel(cmd *cobra.Command, args []string) {
-	verbose := false
-	flag := cmd.Flag(opts.OptionVerbose)
-	if flag != nil {
-		var err error
-		verbose, err = strconv.ParseBool(flag.Value.String())
-		if err != nil {
-			log.Logger().Errorf("Unable to check if the verbose flag is set")
-		}
+	cfg := loadLoggingConfig(cmd)
+	verbose, err := ResolveBool(cmd.Flag(opts.OptionVerbose), "JX_VERBOSE", cfg.Verbose)
+	if err != nil {
+		log.Logger().Errorf("Unable to check if the verbose flag is set: %s", err.Error())
+	}
+	quiet, err = ResolveBool(cmd.Flag(OptionQuiet), "JX_QUIET", cfg.Quiet)
+	if err != nil {
+		log.Logger().Errorf("Unable to check if the quiet flag is set: %s", err.Error())
 	}
 
 	level := os.Getenv("JX_LOG_LEVEL")
@@ -48,6 +61,11 @@ func SetLoggingLevel(cmd *cobra.Command, args []string) {
 		if err != nil {
 			log.Logger().Errorf("Unable to set log level to %s", level)
 		}
+	} else if quiet {
+		err := log.SetLevel("warn")
+		if err != nil {
+			log.Logger().Errorf("Unable to set log level to warn")
+		}
 	} else {
 		if verbose {
 			err := log.SetLevel("debug")
@@ -63,6 +81,60 @@ func SetLoggingLevel(cmd *cobra.Command, args []string) {
 	}
 }
 
+// ResolveBool resolves a boolean setting using the precedence: flag, environment variable, configuration file value
+// then the default value of the flag
+func ResolveBool(flag *pflag.Flag, envVar string, configValue *bool) (bool, error) {
+	if flag != nil && flag.Changed {
+		return strconv.ParseBool(flag.Value.String())
+	}
+	if envVar != "" {
+		value := os.Getenv(envVar)
+		if value != "" {
+			answer, err := strconv.ParseBool(value)
+			if err != nil {
+				return false, errors.Wrapf(err, "invalid value of $%s", envVar)
+			}
+			return answer, nil
+		}
+	}
+	if configValue != nil {
+		return *configValue, nil
+	}
+	if flag != nil {
+		return strconv.ParseBool(flag.Value.String())
+	}
+	return false, nil
+}
+
+// loadLoggingConfig loads the optional configuration file from the --config and --dir flags of the command if present.
+// Any failure is logged as the command reports the error when it loads the configuration itself
+func loadLoggingConfig(cmd *cobra.Command) *bootconfig.Config {
+	flag := cmd.Flag("config")
+	if flag == nil {
+		return &bootconfig.Config{}
+	}
+	fileName := flag.Value.String()
+	dir := "."
+	flag = cmd.Flag("dir")
+	if flag != nil {
+		dir = flag.Value.String()
+	}
+	cfg, _, err := bootconfig.LoadConfig(fileName, dir)
+	if err != nil {
+		log.Logger().Debugf("failed to load the configuration file: %s", err.Error())
+	}
+	return cfg
+}
+
+// LogResult logs the final result of a command so that it is still shown in quiet mode
+func LogResult(format string, args ...interface{}) {
+	if quiet {
+		fmt.Fprintf(os.Stdout, format+"\n", args...)
+		return
+	}
+	log.Logger().Infof(format, args...)
+}
+
 // SplitCommand helper command to ignore the options object
 func SplitCommand(cmd *cobra.Command, options interface{}) *cobra.Command {
 	return cmd
@@ -0,0 +1,112 @@
+package common_test
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBool(t *testing.T) {
+	envVar := "TEST_HELMBOOT_RESOLVE_BOOL"
+	os.Unsetenv(envVar)
+	defer os.Unsetenv(envVar)
+
+	enabled := true
+	disabled := false
+
+	cmd := &cobra.Command{}
+	cmd.Flags().Bool("verbose", false, "")
+	flag := cmd.Flag("verbose")
+
+	value, err := common.ResolveBool(flag, envVar, nil)
+	require.NoError(t, err)
+	assert.False(t, value, "should use the flag default")
+
+	value, err = common.ResolveBool(flag, envVar, &enabled)
+	require.NoError(t, err)
+	assert.True(t, value, "config should override the flag default")
+
+	os.Setenv(envVar, "false")
+	value, err = common.ResolveBool(flag, envVar, &enabled)
+	require.NoError(t, err)
+	assert.False(t, value, "env var should override the config")
+
+	require.NoError(t, cmd.Flags().Set("verbose", "true"))
+	value, err = common.ResolveBool(flag, envVar, &disabled)
+	require.NoError(t, err)
+	assert.True(t, value, "flag should override the env var and config")
+
+	os.Setenv(envVar, "maybe")
+	_, err = common.ResolveBool(nil, envVar, nil)
+	assert.Error(t, err, "should fail for an invalid env var")
+}
+
+func TestSetLoggingLevelQuiet(t *testing.T) {
+	oldLevel := log.GetLevel()
+	defer log.SetLevel(oldLevel)
+	for _, envVar := range []string{"JX_LOG_LEVEL", "JX_QUIET", "JX_VERBOSE"} {
+		value, ok := os.LookupEnv(envVar)
+		os.Unsetenv(envVar)
+		if ok {
+			defer os.Setenv(envVar, value)
+		}
+	}
+	newCommand := func(args ...string) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().BoolP("verbose", "v", false, "")
+		cmd.Flags().BoolP(common.OptionQuiet, "q", false, "")
+		require.NoError(t, cmd.Flags().Parse(args), "failed to parse %v", args)
+		return cmd
+	}
+
+	common.SetLoggingLevel(newCommand(), nil)
+	assert.Equal(t, "info", log.GetLevel(), "default level")
+
+	common.SetLoggingLevel(newCommand("--quiet"), nil)
+	assert.Equal(t, "warning", log.GetLevel(), "quiet level")
+
+	common.SetLoggingLevel(newCommand("--quiet", "--verbose"), nil)
+	assert.Equal(t, "warning", log.GetLevel(), "quiet should take precedence over verbose")
+
+	os.Setenv("JX_QUIET", "true")
+	common.SetLoggingLevel(newCommand(), nil)
+	assert.Equal(t, "warning", log.GetLevel(), "quiet level via $JX_QUIET")
+
+	common.SetLoggingLevel(newCommand("--quiet=false"), nil)
+	assert.Equal(t, "info", log.GetLevel(), "the flag should override $JX_QUIET")
+	os.Unsetenv("JX_QUIET")
+
+	os.Setenv("JX_LOG_LEVEL", "debug")
+	common.SetLoggingLevel(newCommand("--quiet"), nil)
+	assert.Equal(t, "debug", log.GetLevel(), "$JX_LOG_LEVEL should take precedence over quiet")
+	os.Unsetenv("JX_LOG_LEVEL")
+}
+
+func TestLogResultWhenQuiet(t *testing.T) {
+	oldLevel := log.GetLevel()
+	defer log.SetLevel(oldLevel)
+	cmd := &cobra.Command{}
+	cmd.Flags().BoolP(common.OptionQuiet, "q", false, "")
+	require.NoError(t, cmd.Flags().Set(common.OptionQuiet, "true"))
+	common.SetLoggingLevel(cmd, nil)
+	defer common.SetLoggingLevel(&cobra.Command{}, nil)
+
+	tmpFile, err := ioutil.TempFile("", "test-helmboot-stdout-")
+	require.NoError(t, err, "failed to create a temporary file")
+	defer os.Remove(tmpFile.Name())
+	oldStdout := os.Stdout
+	os.Stdout = tmpFile
+	common.LogResult("the boot Job has completed %s", "successfully")
+	os.Stdout = oldStdout
+	tmpFile.Close()
+
+	data, err := ioutil.ReadFile(tmpFile.Name())
+	require.NoError(t, err, "failed to read file %s", tmpFile.Name())
+	assert.Equal(t, "the boot Job has completed successfully\n", string(data), "the result should be printed in quiet mode")
+}
@@ -0,0 +1,164 @@
+package common
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MetricsJobName the Pushgateway job name the boot metrics are grouped by
+	MetricsJobName = "helmboot"
+
+	pushTimeout = 30 * time.Second
+)
+
+// PhaseDuration the time spent in a phase of the boot
+type PhaseDuration struct {
+	Name     string
+	Duration time.Duration
+}
+
+// BootTimer records the duration of the boot and of each of its phases
+type BootTimer struct {
+	// Clock returns the current time. If nil time.Now is used
+	Clock func() time.Time
+
+	Start    time.Time
+	Duration time.Duration
+	Phases   []PhaseDuration
+
+	phase      string
+	phaseStart time.Time
+}
+
+// NewBootTimer creates a timer starting now
+func NewBootTimer() *BootTimer {
+	t := &BootTimer{}
+	t.Start = t.now()
+	return t
+}
+
+// StartPhase ends any current phase and starts timing the named phase
+func (t *BootTimer) StartPhase(name string) {
+	if t == nil {
+		return
+	}
+	now := t.now()
+	t.endPhase(now)
+	t.phase = name
+	t.phaseStart = now
+}
+
+// Stop ends any current phase and records the total duration of the boot
+func (t *BootTimer) Stop() {
+	if t == nil {
+		return
+	}
+	now := t.now()
+	t.endPhase(now)
+	t.Duration = now.Sub(t.Start)
+}
+
+func (t *BootTimer) endPhase(now time.Time) {
+	if t.phase != "" {
+		t.Phases = append(t.Phases, PhaseDuration{Name: t.phase, Duration: now.Sub(t.phaseStart)})
+		t.phase = ""
+	}
+}
+
+func (t *BootTimer) now() time.Time {
+	if t.Clock != nil {
+		return t.Clock()
+	}
+	return time.Now()
+}
+
+// BootMetricsText returns the metrics of the stopped timer in the Prometheus text format. The labels such as the
+// git ref are added to every metric along with an outcome label on the boot counter
+func BootMetricsText(t *BootTimer, succeeded bool, labels map[string]string) string {
+	outcome := "succeeded"
+	if !succeeded {
+		outcome = "failed"
+	}
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "# HELP helmboot_boot_duration_seconds the duration of the boot\n")
+	fmt.Fprintf(buf, "# TYPE helmboot_boot_duration_seconds gauge\n")
+	fmt.Fprintf(buf, "helmboot_boot_duration_seconds%s %g\n", metricLabels(labels), t.Duration.Seconds())
+	if len(t.Phases) > 0 {
+		fmt.Fprintf(buf, "# HELP helmboot_boot_phase_duration_seconds the duration of each phase of the boot\n")
+		fmt.Fprintf(buf, "# TYPE helmboot_boot_phase_duration_seconds gauge\n")
+		for _, p := range t.Phases {
+			fmt.Fprintf(buf, "helmboot_boot_phase_duration_seconds%s %g\n", metricLabels(labels, "phase", p.Name), p.Duration.Seconds())
+		}
+	}
+	fmt.Fprintf(buf, "# HELP helmboot_boot_total the boots by outcome\n")
+	fmt.Fprintf(buf, "# TYPE helmboot_boot_total counter\n")
+	fmt.Fprintf(buf, "helmboot_boot_total%s 1\n", metricLabels(labels, "outcome", outcome))
+	fmt.Fprintf(buf, "# HELP helmboot_boot_last_timestamp_seconds the time the last boot completed\n")
+	fmt.Fprintf(buf, "# TYPE helmboot_boot_last_timestamp_seconds gauge\n")
+	fmt.Fprintf(buf, "helmboot_boot_last_timestamp_seconds%s %d\n", metricLabels(labels), t.Start.Add(t.Duration).Unix())
+	return buf.String()
+}
+
+// PushMetrics pushes the metrics text to the Prometheus Pushgateway at the given URL grouped by the job and
+// grouping labels. The metrics of the same names in the group are replaced
+func PushMetrics(gatewayURL string, job string, grouping map[string]string, text string) error {
+	u := strings.TrimSuffix(gatewayURL, "/") + "/metrics/job/" + url.PathEscape(job)
+	for _, k := range sortedKeys(grouping) {
+		if grouping[k] != "" {
+			u += "/" + url.PathEscape(k) + "/" + url.PathEscape(grouping[k])
+		}
+	}
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewBufferString(text))
+	if err != nil {
+		return errors.Wrapf(err, "failed to create the request to the Pushgateway %s", gatewayURL)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	client := &http.Client{Timeout: pushTimeout, Transport: NewUserAgentRoundTripper(nil)}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to push metrics to the Pushgateway %s", gatewayURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return errors.Errorf("the Pushgateway %s returned status %d: %s", gatewayURL, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// metricLabels formats the labels along with any extra name value pairs in the Prometheus text format
+func metricLabels(labels map[string]string, extra ...string) string {
+	values := map[string]string{}
+	for k, v := range labels {
+		values[k] = v
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		values[extra[i]] = extra[i+1]
+	}
+	var parts []string
+	for _, k := range sortedKeys(values) {
+		parts = append(parts, fmt.Sprintf("%s=%q", k, values[k]))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,65 @@
+package common_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBootMetrics(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	timer := &common.BootTimer{
+		Clock: func() time.Time { return now },
+		Start: now,
+	}
+	timer.StartPhase("clone")
+	now = now.Add(2 * time.Second)
+	timer.StartPhase("job")
+	now = now.Add(90 * time.Second)
+	timer.Stop()
+
+	assert.Equal(t, 92*time.Second, timer.Duration, "boot duration")
+	require.Len(t, timer.Phases, 2, "phases")
+
+	text := common.BootMetricsText(timer, true, map[string]string{"git_ref": "master"})
+	assert.Contains(t, text, `helmboot_boot_duration_seconds{git_ref="master"} 92`+"\n")
+	assert.Contains(t, text, `helmboot_boot_phase_duration_seconds{git_ref="master",phase="clone"} 2`+"\n")
+	assert.Contains(t, text, `helmboot_boot_phase_duration_seconds{git_ref="master",phase="job"} 90`+"\n")
+	assert.Contains(t, text, `helmboot_boot_total{git_ref="master",outcome="succeeded"} 1`+"\n")
+	assert.Contains(t, text, `helmboot_boot_last_timestamp_seconds{git_ref="master"} 1600000092`+"\n")
+
+	text = common.BootMetricsText(timer, false, nil)
+	assert.Contains(t, text, `helmboot_boot_total{outcome="failed"} 1`+"\n")
+}
+
+func TestPushMetrics(t *testing.T) {
+	var method, path, body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		path = r.URL.Path
+		data, _ := ioutil.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer server.Close()
+
+	err := common.PushMetrics(server.URL+"/", common.MetricsJobName, map[string]string{"cluster": "mycluster"}, "helmboot_boot_total 1\n")
+	require.NoError(t, err, "failed to push metrics")
+	assert.Equal(t, http.MethodPost, method, "method")
+	assert.Equal(t, "/metrics/job/helmboot/cluster/mycluster", path, "path")
+	assert.Equal(t, "helmboot_boot_total 1\n", body, "body")
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad metrics", http.StatusBadRequest)
+	}))
+	defer failing.Close()
+
+	err = common.PushMetrics(failing.URL, common.MetricsJobName, nil, "invalid")
+	require.Error(t, err, "should have failed")
+	assert.Contains(t, err.Error(), "bad metrics", "error message")
+}
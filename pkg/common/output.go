@@ -0,0 +1,94 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OutputFormatTable a human readable table with aligned columns
+	OutputFormatTable = "table"
+
+	// OutputFormatJSON indented JSON for scripting
+	OutputFormatJSON = "json"
+
+	// OutputFormatYAML YAML for scripting
+	OutputFormatYAML = "yaml"
+)
+
+// OutputFormats the supported values of the --output flag
+var OutputFormats = []string{OutputFormatTable, OutputFormatJSON, OutputFormatYAML}
+
+// Table the rows of a table to output
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// NewTable creates a table with the given column headers
+func NewTable(headers ...string) *Table {
+	return &Table{Headers: headers}
+}
+
+// AddRow adds a row of values to the table
+func (t *Table) AddRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// AddOutputFlag adds the --output flag for choosing the output format
+func AddOutputFlag(cmd *cobra.Command, format *string) {
+	cmd.Flags().StringVarP(format, "output", "o", OutputFormatTable, "the output format. Possible values: "+strings.Join(OutputFormats, ", "))
+}
+
+// ValidateOutputFormat returns an error if the format is not supported
+func ValidateOutputFormat(format string) error {
+	if util.StringArrayIndex(OutputFormats, format) < 0 {
+		return util.InvalidOption("output", format, OutputFormats)
+	}
+	return nil
+}
+
+// WriteOutput writes the value as JSON or YAML or the table for the table format. The JSON and YAML have
+// sorted map keys and the field order of structs so they are stable for scripting
+func WriteOutput(out io.Writer, format string, value interface{}, table *Table) error {
+	switch format {
+	case OutputFormatJSON:
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the output to JSON")
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	case OutputFormatYAML:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the output to YAML")
+		}
+		_, err = out.Write(data)
+		return err
+	case OutputFormatTable, "":
+		return writeTable(out, table)
+	default:
+		return ValidateOutputFormat(format)
+	}
+}
+
+// writeTable writes the table aligning the columns
+func writeTable(out io.Writer, table *Table) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if len(table.Headers) > 0 {
+		fmt.Fprintln(w, strings.Join(table.Headers, "\t"))
+	}
+	for _, row := range table.Rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,38 @@
+package common_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteOutput(t *testing.T) {
+	type item struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+	}
+	items := []item{{Name: "dev Environment", Status: "OK"}, {Name: "boot Job", Status: "FAIL"}}
+	table := common.NewTable("NAME", "STATUS")
+	for _, i := range items {
+		table.AddRow(i.Name, i.Status)
+	}
+
+	testCases := map[string]string{
+		common.OutputFormatTable: "NAME             STATUS\ndev Environment  OK\nboot Job         FAIL\n",
+		common.OutputFormatJSON:  "[\n  {\n    \"name\": \"dev Environment\",\n    \"status\": \"OK\"\n  },\n  {\n    \"name\": \"boot Job\",\n    \"status\": \"FAIL\"\n  }\n]\n",
+		common.OutputFormatYAML:  "- name: dev Environment\n  status: OK\n- name: boot Job\n  status: FAIL\n",
+	}
+	for format, expected := range testCases {
+		out := &bytes.Buffer{}
+		err := common.WriteOutput(out, format, items, table)
+		require.NoError(t, err, "failed to write output for format %s", format)
+		assert.Equal(t, expected, out.String(), "output for format %s", format)
+	}
+
+	assert.NoError(t, common.ValidateOutputFormat("yaml"))
+	assert.Error(t, common.ValidateOutputFormat("xml"), "should not support xml")
+	assert.Error(t, common.WriteOutput(&bytes.Buffer{}, "xml", items, table), "should not support xml")
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// ProxySchemes the supported URL schemes of a proxy
+var ProxySchemes = []string{"http", "https", "socks5"}
+
+// ProxyOptions the proxy used for network calls such as git clones, helm and cloud API calls
+type ProxyOptions struct {
+	Proxy   string
+	NoProxy string
+}
+
+// AddFlags adds the proxy flags to the given command
+func (o *ProxyOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.Proxy, "proxy", "", "", "the HTTP or SOCKS proxy URL such as http://proxy:3128 or socks5://proxy:1080 used by helmboot, git and helm. Defaults to the $HTTPS_PROXY / $HTTP_PROXY environment variables")
+	cmd.Flags().StringVarP(&o.NoProxy, "no-proxy", "", "", "a comma separated list of hosts which should not use the proxy. Defaults to the $NO_PROXY environment variable")
+}
+
+// Apply validates the proxy settings then exports them as the standard proxy environment variables.
+//
+// The environment variables are honoured by the Go HTTP client used for cloud and Kubernetes API calls
+// and are inherited by the git and helm processes we shell out to so that all network calls use the same proxy
+func (o *ProxyOptions) Apply() error {
+	if o.Proxy != "" {
+		u, err := url.Parse(o.Proxy)
+		if err != nil || u.Host == "" {
+			return util.InvalidOptionf("proxy", o.Proxy, "the proxy must be a URL such as http://proxy:3128")
+		}
+		if util.StringArrayIndex(ProxySchemes, u.Scheme) < 0 {
+			return util.InvalidOptionf("proxy", o.Proxy, "the proxy URL scheme must be one of %s", strings.Join(ProxySchemes, ", "))
+		}
+		for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy"} {
+			err = os.Setenv(name, o.Proxy)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if o.NoProxy != "" {
+		for _, name := range []string{"NO_PROXY", "no_proxy"} {
+			err := os.Setenv(name, o.NoProxy)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package common_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProxyOptionsApply(t *testing.T) {
+	for _, name := range []string{"HTTP_PROXY", "HTTPS_PROXY", "http_proxy", "https_proxy", "NO_PROXY", "no_proxy"} {
+		defer os.Setenv(name, os.Getenv(name))
+	}
+
+	o := &common.ProxyOptions{
+		Proxy:   "socks5://proxy.example.com:1080",
+		NoProxy: "localhost,.svc",
+	}
+	require.NoError(t, o.Apply(), "failed to apply proxy")
+	assert.Equal(t, "socks5://proxy.example.com:1080", os.Getenv("HTTPS_PROXY"), "$HTTPS_PROXY")
+	assert.Equal(t, "socks5://proxy.example.com:1080", os.Getenv("http_proxy"), "$http_proxy")
+	assert.Equal(t, "localhost,.svc", os.Getenv("NO_PROXY"), "$NO_PROXY")
+}
+
+func TestProxyOptionsInvalid(t *testing.T) {
+	for _, proxy := range []string{"proxy.example.com:3128", "ftp://proxy.example.com"} {
+		o := &common.ProxyOptions{Proxy: proxy}
+		assert.Error(t, o.Apply(), "should have failed for proxy %s", proxy)
+	}
+}
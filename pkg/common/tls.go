@@ -0,0 +1,103 @@
+package common
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// TLSOptions the custom certificate authorities to trust for private git servers, helm repositories and cloud endpoints
+type TLSOptions struct {
+	CAFile string
+
+	// InsecureSkipVerify disables TLS verification entirely which is only intended for dev clusters with self-signed
+	// certificates
+	InsecureSkipVerify bool
+}
+
+// AddFlags adds the TLS flags to the given command
+func (o *TLSOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&o.CAFile, "ca-file", "", "", "a PEM encoded CA bundle to trust when connecting to private git servers, helm repositories and cloud endpoints")
+	cmd.Flags().BoolVarP(&o.InsecureSkipVerify, "insecure-skip-tls-verify", "", false, "UNSAFE: disables TLS verification of git servers, helm repositories and cloud endpoints. Only use this for dev clusters with self-signed certificates, never in production")
+}
+
+// Apply configures the git CLI and the Go HTTP client to trust the CA bundle if one is specified.
+//
+// The git CLI uses the bundle via the $GIT_SSL_CAINFO environment variable which is inherited by the git processes
+// we shell out to. Helm needs the bundle passed explicitly via its --ca-file argument
+func (o *TLSOptions) Apply() error {
+	if o.InsecureSkipVerify {
+		return o.applyInsecureSkipVerify()
+	}
+	if o.CAFile == "" {
+		return nil
+	}
+	exists, err := util.FileExists(o.CAFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to check if the CA file %s exists", o.CAFile)
+	}
+	if !exists {
+		return util.InvalidOptionf("ca-file", o.CAFile, "the file does not exist")
+	}
+	data, err := ioutil.ReadFile(o.CAFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to load the CA file %s", o.CAFile)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return util.InvalidOptionf("ca-file", o.CAFile, "the file does not contain any PEM encoded certificates")
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if ok {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}
+	return os.Setenv("GIT_SSL_CAINFO", o.CAFile)
+}
+
+// applyInsecureSkipVerify disables TLS verification of the Go HTTP client, the git CLI and gcloud with a warning
+func (o *TLSOptions) applyInsecureSkipVerify() error {
+	if o.CAFile != "" {
+		return errors.Errorf("cannot specify both --ca-file and --insecure-skip-tls-verify")
+	}
+	log.Logger().Warn(util.ColorWarning("WARNING: TLS verification is disabled via --insecure-skip-tls-verify so connections to git servers, helm repositories and cloud endpoints can be intercepted. Never use this in production!"))
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if ok {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		/* #nosec */
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+	err := os.Setenv("GIT_SSL_NO_VERIFY", "true")
+	if err != nil {
+		return err
+	}
+	return os.Setenv("CLOUDSDK_AUTH_DISABLE_SSL_VALIDATION", "true")
+}
+
+// HelmArgs returns the arguments to pass to helm commands which download charts
+func (o *TLSOptions) HelmArgs() []string {
+	if o.InsecureSkipVerify {
+		return []string{"--insecure-skip-tls-verify"}
+	}
+	if o.CAFile == "" {
+		return nil
+	}
+	return []string{"--ca-file", o.CAFile}
+}
@@ -0,0 +1,84 @@
+package common_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSOptionsApply(t *testing.T) {
+	defer os.Setenv("GIT_SSL_CAINFO", os.Getenv("GIT_SSL_CAINFO"))
+
+	dir, err := ioutil.TempDir("", "test-helmboot-ca-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(dir)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "failed to generate key")
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "helmboot test CA"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err, "failed to create certificate")
+
+	caFile := filepath.Join(dir, "ca.pem")
+	err = ioutil.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), util.DefaultFileWritePermissions)
+	require.NoError(t, err, "failed to save %s", caFile)
+
+	o := &common.TLSOptions{CAFile: caFile}
+	require.NoError(t, o.Apply(), "failed to apply the CA file")
+	assert.Equal(t, caFile, os.Getenv("GIT_SSL_CAINFO"), "$GIT_SSL_CAINFO")
+	assert.Equal(t, []string{"--ca-file", caFile}, o.HelmArgs(), "helm args")
+
+	invalidFile := filepath.Join(dir, "invalid.pem")
+	err = ioutil.WriteFile(invalidFile, []byte("not a certificate"), util.DefaultFileWritePermissions)
+	require.NoError(t, err, "failed to save %s", invalidFile)
+
+	o = &common.TLSOptions{CAFile: invalidFile}
+	assert.Error(t, o.Apply(), "should have failed for an invalid CA file")
+
+	o = &common.TLSOptions{CAFile: filepath.Join(dir, "does-not-exist.pem")}
+	assert.Error(t, o.Apply(), "should have failed for a missing CA file")
+}
+
+func TestTLSOptionsInsecureSkipVerify(t *testing.T) {
+	defer os.Setenv("GIT_SSL_NO_VERIFY", os.Getenv("GIT_SSL_NO_VERIFY"))
+	defer os.Setenv("CLOUDSDK_AUTH_DISABLE_SSL_VALIDATION", os.Getenv("CLOUDSDK_AUTH_DISABLE_SSL_VALIDATION"))
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	require.True(t, ok, "the default transport should be a *http.Transport")
+	tlsConfig := transport.TLSClientConfig
+	defer func() {
+		transport.TLSClientConfig = tlsConfig
+	}()
+	transport.TLSClientConfig = nil
+
+	o := &common.TLSOptions{InsecureSkipVerify: true, CAFile: "ca.pem"}
+	assert.Error(t, o.Apply(), "should have failed with a CA file")
+
+	o = &common.TLSOptions{InsecureSkipVerify: true}
+	require.NoError(t, o.Apply(), "failed to disable TLS verification")
+	assert.True(t, transport.TLSClientConfig.InsecureSkipVerify, "should have disabled TLS verification of the HTTP client")
+	assert.Equal(t, "true", os.Getenv("GIT_SSL_NO_VERIFY"), "$GIT_SSL_NO_VERIFY")
+	assert.Equal(t, "true", os.Getenv("CLOUDSDK_AUTH_DISABLE_SSL_VALIDATION"), "$CLOUDSDK_AUTH_DISABLE_SSL_VALIDATION")
+	assert.Equal(t, []string{"--insecure-skip-tls-verify"}, o.HelmArgs(), "helm args")
+}
@@ -0,0 +1,113 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// TraceAPI the category of a Kubernetes API call
+	TraceAPI = "api"
+
+	// TraceCommand the category of an executed command
+	TraceCommand = "command"
+
+	// TraceDecision the category of a decision taken such as which secret manager to use
+	TraceDecision = "decision"
+
+	// TraceStep the category of a step of the command
+	TraceStep = "step"
+)
+
+// TraceEntry a timestamped entry in the trace file
+type TraceEntry struct {
+	Time     time.Time `json:"time"`
+	Category string    `json:"category"`
+	Message  string    `json:"message"`
+}
+
+var (
+	traceLock sync.Mutex
+	traceOut  io.WriteCloser
+
+	// secretArgRegex matches the value of a flag or key=value argument whose name suggests it holds a secret
+	secretArgRegex = regexp.MustCompile(`(?i)(--[a-z0-9\-]*(?:token|password|secret)[a-z0-9\-]*(?:=|\s+)|[a-z0-9_.\-\[\]]*(?:token|password|secret)[a-z0-9_.\-\[\]]*=)("[^"]*"|\S+)`)
+)
+
+// StartTrace starts writing the trace to the given file returning a function to stop tracing and close the file
+func StartTrace(fileName string) (func(), error) {
+	f, err := os.OpenFile(fileName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, util.DefaultFileWritePermissions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the trace file %s", fileName)
+	}
+	SetTraceOutput(f)
+	return func() {
+		SetTraceOutput(nil)
+	}, nil
+}
+
+// SetTraceOutput sets the output of the trace closing any previous output. Tracing is disabled if the output is nil
+func SetTraceOutput(out io.WriteCloser) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	if traceOut != nil {
+		traceOut.Close()
+	}
+	traceOut = out
+}
+
+// TraceEnabled returns true if a trace is being written
+func TraceEnabled() bool {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	return traceOut != nil
+}
+
+// Tracef writes an entry of the given category to the trace if enabled. Any git URL credentials and the values of
+// secret looking arguments are redacted
+func Tracef(category string, format string, args ...interface{}) {
+	traceLock.Lock()
+	defer traceLock.Unlock()
+	if traceOut == nil {
+		return
+	}
+	entry := TraceEntry{
+		Time:     time.Now().UTC(),
+		Category: category,
+		Message:  RedactTrace(fmt.Sprintf(format, args...)),
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_, err = traceOut.Write(append(data, '\n'))
+	if err != nil {
+		// lets not keep failing on every entry
+		traceOut.Close()
+		traceOut = nil
+	}
+}
+
+// TraceCommand writes the command line of the command to the trace if enabled
+func TraceCommand(c util.Command) {
+	if !TraceEnabled() {
+		return
+	}
+	Tracef(TraceCommand, "running %s %s in directory %s", c.Name, strings.Join(c.Args, " "), c.Dir)
+}
+
+// RedactTrace redacts any git URL credentials and the values of arguments named like a token, password or secret
+func RedactTrace(text string) string {
+	text = githelpers.RedactURLs(text)
+	return secretArgRegex.ReplaceAllString(text, "${1}****")
+}
@@ -0,0 +1,87 @@
+package common
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultUninstallTimeout the default maximum time to wait for a helm release to be uninstalled
+	DefaultUninstallTimeout = 5 * time.Minute
+
+	// DefaultReleaseName the default name of the helm release of the boot Job
+	DefaultReleaseName = "jx-boot"
+
+	// maxReleaseNameLength the maximum length of a helm release name
+	maxReleaseNameLength = 53
+)
+
+// releaseNameRegex matches a legal helm release name
+var releaseNameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+// UninstallOptions the options for uninstalling a helm release which may hang waiting on finalizers
+type UninstallOptions struct {
+	Timeout      time.Duration
+	IgnoreErrors bool
+
+	// ReleaseName the name of the helm release of the boot Job to uninstall
+	ReleaseName string
+}
+
+// AddFlags adds the uninstall flags to the given command
+func (o *UninstallOptions) AddFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVarP(&o.Timeout, "uninstall-timeout", "", DefaultUninstallTimeout, "the maximum time to wait for the boot helm release to be uninstalled")
+	cmd.Flags().BoolVarP(&o.IgnoreErrors, "ignore-uninstall-errors", "", false, "continue if the boot helm release could not be uninstalled within the timeout")
+	cmd.Flags().StringVarP(&o.ReleaseName, "release-name", "", DefaultReleaseName, "the name of the helm release of the boot Job to uninstall such as when the installer chart was previously deployed under a different release name")
+}
+
+// Validate validates the release name
+func (o *UninstallOptions) Validate() error {
+	if o.ReleaseName == "" {
+		o.ReleaseName = DefaultReleaseName
+	}
+	return ValidateReleaseName(o.ReleaseName)
+}
+
+// ValidateReleaseName returns an error if the name is not a legal helm release name
+func ValidateReleaseName(name string) error {
+	if len(name) > maxReleaseNameLength {
+		return util.InvalidOptionf("release-name", name, "the helm release name must be at most %d characters", maxReleaseNameLength)
+	}
+	if !releaseNameRegex.MatchString(name) {
+		return util.InvalidOptionf("release-name", name, "the helm release name must consist of lower case alphanumeric characters, '-' or '.' and start and end with an alphanumeric character")
+	}
+	return nil
+}
+
+// Uninstall runs the helm command to uninstall the release aborting it if it takes longer than the timeout.
+// Any other failure such as the release not existing is only logged
+func (o *UninstallOptions) Uninstall(dir string, env map[string]string, release string) error {
+	if release == "" {
+		release = DefaultReleaseName
+	}
+	c := util.Command{
+		Name: "helm",
+		Args: []string{"delete", release},
+		Dir:  dir,
+		Env:  env,
+	}
+	_, err := RunCommandWithTimeout(c, o.Timeout)
+	if err == nil {
+		return nil
+	}
+	if errors.Cause(err) != ErrCommandTimeout {
+		log.Logger().Debugf("failed to delete the %s chart: %s", release, err.Error())
+		return nil
+	}
+	if o.IgnoreErrors {
+		log.Logger().Warnf("aborted uninstalling the %s chart: %s", release, err.Error())
+		return nil
+	}
+	return errors.Wrapf(err, "aborted uninstalling the %s chart which may be waiting on finalizers. Use --ignore-uninstall-errors to continue anyway or --uninstall-timeout to wait longer", release)
+}
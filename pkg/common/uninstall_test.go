@@ -0,0 +1,22 @@
+package common_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateReleaseName(t *testing.T) {
+	for _, name := range []string{"jx-boot", "my-boot", "boot.v2", "b"} {
+		assert.NoError(t, common.ValidateReleaseName(name), "release name %s", name)
+	}
+	for _, name := range []string{"", "JX-Boot", "-boot", "boot-", "my_boot", "boot..v2", strings.Repeat("a", 54)} {
+		assert.Error(t, common.ValidateReleaseName(name), "release name %s", name)
+	}
+
+	o := &common.UninstallOptions{}
+	assert.NoError(t, o.Validate(), "should have defaulted the release name")
+	assert.Equal(t, common.DefaultReleaseName, o.ReleaseName, "release name")
+}
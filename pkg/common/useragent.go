@@ -0,0 +1,93 @@
+package common
+
+import (
+	"net/http"
+	"os"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/version"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// OptionUserAgent the flag used to override the User-Agent
+	OptionUserAgent = "user-agent"
+
+	// gitUserAgentEnv the environment variable git uses for the User-Agent of its HTTP requests
+	gitUserAgentEnv = "GIT_HTTP_USER_AGENT"
+)
+
+var (
+	// customUserAgent the User-Agent specified via the flag
+	customUserAgent string
+
+	// userAgentRunID the optional ID of the boot run included in the default User-Agent
+	userAgentRunID string
+
+	// gitUserAgentConfigured whether the git User-Agent was set by ConfigureUserAgent
+	gitUserAgentConfigured bool
+)
+
+// AddUserAgentFlags adds the flag to override the User-Agent to the command and all of its sub commands
+func AddUserAgentFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVarP(&customUserAgent, OptionUserAgent, "", "", "the User-Agent of the HTTP requests to cloud secret managers, vault, the Pushgateway and git so the traffic can be attributed to helmboot in audit logs. Defaults to helmboot/<version>")
+}
+
+// ConfigureUserAgent makes git use the User-Agent for its HTTP requests unless $GIT_HTTP_USER_AGENT is already set
+func ConfigureUserAgent() {
+	if os.Getenv(gitUserAgentEnv) == "" {
+		os.Setenv(gitUserAgentEnv, UserAgent())
+		gitUserAgentConfigured = true
+	}
+}
+
+// SetUserAgentRunID includes the ID of the boot run in the default User-Agent
+func SetUserAgentRunID(runID string) {
+	userAgentRunID = runID
+	if gitUserAgentConfigured {
+		os.Setenv(gitUserAgentEnv, UserAgent())
+	}
+}
+
+// UserAgent returns the User-Agent specified via the flag or helmboot/<version> along with the ID of any boot run
+func UserAgent() string {
+	return ResolveUserAgent(customUserAgent, version.Version, userAgentRunID)
+}
+
+// ResolveUserAgent returns the custom User-Agent if specified otherwise helmboot/<version> with the optional run ID
+func ResolveUserAgent(custom string, v string, runID string) string {
+	if custom != "" {
+		return custom
+	}
+	answer := "helmboot/" + v
+	if runID != "" {
+		answer += " run/" + runID
+	}
+	return answer
+}
+
+// userAgentRoundTripper sets the User-Agent header of each request
+type userAgentRoundTripper struct {
+	next http.RoundTripper
+}
+
+// NewUserAgentRoundTripper returns a round tripper which sets the User-Agent of each request. If the next round
+// tripper is nil the default transport is used
+func NewUserAgentRoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &userAgentRoundTripper{next: next}
+}
+
+// RoundTrip performs the request with the User-Agent header
+func (t *userAgentRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// lets not modify the request of the caller
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header)+1)
+	for k, v := range req.Header {
+		r.Header[k] = v
+	}
+	r.Header.Set("User-Agent", UserAgent())
+	return t.next.RoundTrip(r)
+}
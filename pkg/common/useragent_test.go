@@ -0,0 +1,36 @@
+package common_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveUserAgent(t *testing.T) {
+	assert.Equal(t, "helmboot/1.2.3", common.ResolveUserAgent("", "1.2.3", ""))
+	assert.Equal(t, "helmboot/1.2.3 run/20200101-120000", common.ResolveUserAgent("", "1.2.3", "20200101-120000"))
+	assert.Equal(t, "mytool/1.0", common.ResolveUserAgent("mytool/1.0", "1.2.3", "20200101-120000"))
+}
+
+func TestUserAgentRoundTripper(t *testing.T) {
+	userAgent := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.Header.Get("User-Agent")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: common.NewUserAgentRoundTripper(nil)}
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	require.NoError(t, err, "failed to create the request")
+	req.Header.Set("User-Agent", "Go-http-client/1.1")
+	resp, err := client.Do(req)
+	require.NoError(t, err, "failed to send the request")
+	resp.Body.Close()
+
+	assert.Equal(t, common.UserAgent(), userAgent, "User-Agent")
+	assert.Equal(t, "Go-http-client/1.1", req.Header.Get("User-Agent"), "should not have modified the request")
+}
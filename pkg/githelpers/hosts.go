@@ -0,0 +1,67 @@
+package githelpers
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// ParseAllowedGitHosts parses the comma separated git hosts which git URLs are allowed to refer to. Returns nil if
+// no hosts are specified in which case any host is allowed
+func ParseAllowedGitHosts(text string) ([]string, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	var answer []string
+	for _, host := range strings.Split(text, ",") {
+		host = strings.ToLower(strings.TrimSpace(host))
+		if host == "" || strings.ContainsAny(host, "/@: ") {
+			return nil, util.InvalidOptionf("allowed-git-hosts", text, "the allowed git hosts must be comma separated host names such as github.com,gitlab.example.com")
+		}
+		answer = append(answer, host)
+	}
+	return answer, nil
+}
+
+// GitURLHost returns the lower case host name of the git URL which is either a URL such as
+// https://github.com/myorg/env.git or of the scp form git@github.com:myorg/env.git
+func GitURLHost(gitURL string) (string, error) {
+	if strings.Contains(gitURL, "://") {
+		u, err := url.Parse(gitURL)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to parse git URL %s", RedactURLs(gitURL))
+		}
+		return strings.ToLower(u.Hostname()), nil
+	}
+	idx := strings.Index(gitURL, ":")
+	if idx < 0 {
+		return "", nil
+	}
+	host := gitURL[:idx]
+	at := strings.LastIndex(host, "@")
+	if at >= 0 {
+		host = host[at+1:]
+	}
+	return strings.ToLower(host), nil
+}
+
+// VerifyAllowedGitHost returns an error if the host of the git URL is not one of the allowed hosts. If there are no
+// allowed hosts any git URL is allowed. Local repositories have no host so are never allowed by an allow list
+func VerifyAllowedGitHost(name string, gitURL string, allowedHosts []string) error {
+	if len(allowedHosts) == 0 || gitURL == "" {
+		return nil
+	}
+	host, err := GitURLHost(gitURL)
+	if err != nil {
+		return err
+	}
+	if host == "" {
+		return errors.Errorf("the %s %s has no host so is not one of the allowed git hosts %s", name, RedactURLs(gitURL), strings.Join(allowedHosts, ", "))
+	}
+	if util.StringArrayIndex(allowedHosts, host) < 0 {
+		return errors.Errorf("the %s %s is on host %s which is not one of the allowed git hosts %s", name, RedactURLs(gitURL), host, strings.Join(allowedHosts, ", "))
+	}
+	return nil
+}
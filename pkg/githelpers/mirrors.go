@@ -0,0 +1,40 @@
+package githelpers
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// SplitGitURLs splits the comma separated git URLs of the mirrors of a repository
+func SplitGitURLs(text string) ([]string, error) {
+	var answer []string
+	for _, gitURL := range strings.Split(text, ",") {
+		gitURL = strings.TrimSpace(gitURL)
+		if gitURL == "" {
+			return nil, util.InvalidOptionf("git-url", RedactURLs(text), "the comma separated git URLs must not be blank")
+		}
+		answer = append(answer, gitURL)
+	}
+	return answer, nil
+}
+
+// TryGitURLs invokes the function with each of the git URLs in order until one succeeds returning the git URL which
+// succeeded. If they all fail the redacted errors of each git URL are returned
+func TryGitURLs(gitURLs []string, fn func(gitURL string) error) (string, error) {
+	var messages []string
+	for i, gitURL := range gitURLs {
+		err := fn(gitURL)
+		if err == nil {
+			return gitURL, nil
+		}
+		message := RedactError(err).Error()
+		messages = append(messages, RedactURLs(gitURL)+": "+message)
+		if i+1 < len(gitURLs) {
+			log.Logger().Warnf("failed to use the git URL %s so trying the next mirror: %s", RedactURLs(gitURL), message)
+		}
+	}
+	return "", errors.Errorf("failed to use all of the git URLs: %s", strings.Join(messages, "; "))
+}
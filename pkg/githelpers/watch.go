@@ -0,0 +1,41 @@
+package githelpers
+
+import (
+	"os"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/log"
+)
+
+// RefPoller returns the commit SHA a watched git ref currently points to
+type RefPoller func() (string, error)
+
+// NewRefPoller returns a poller of the commit SHA of the ref in the remote git repository
+func NewRefPoller(gitURL, ref string) RefPoller {
+	return func() (string, error) {
+		return GetRemoteRefSHA(gitURL, ref)
+	}
+}
+
+// WaitForStableRef waits until the ref has not changed from the given commit SHA for the debounce period returning
+// the latest commit SHA so that rapid pushes only trigger one change. A blank string is returned if the stop
+// channel receives a signal first
+func WaitForStableRef(poll RefPoller, sha string, debounce time.Duration, stop <-chan os.Signal) (string, error) {
+	for {
+		select {
+		case <-stop:
+			return "", nil
+		case <-time.After(debounce):
+		}
+
+		latest, err := poll()
+		if err != nil {
+			return "", RedactError(err)
+		}
+		if latest == sha {
+			return sha, nil
+		}
+		log.Logger().Debugf("the ref changed again to %s so waiting for it to settle", latest)
+		sha = latest
+	}
+}
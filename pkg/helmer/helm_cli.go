@@ -22,6 +22,12 @@ type HelmCLI struct {
 	CWD    string
 	Runner util.Commander
 	Debug  bool
+
+	// CAFile an optional CA bundle used to verify the TLS certificates of chart repositories
+	CAFile string
+
+	// InsecureSkipTLSVerify disables verifying the TLS certificates of chart repositories
+	InsecureSkipTLSVerify bool
 }
 
 // NewHelmCLI creates a new CLI
@@ -109,6 +115,12 @@ func (h *HelmCLI) AddRepo(repoName, repoURL, username, password string) error {
 	if password != "" {
 		args = append(args, "--password", password)
 	}
+	if h.CAFile != "" {
+		args = append(args, "--ca-file", h.CAFile)
+	}
+	if h.InsecureSkipTLSVerify {
+		args = append(args, "--insecure-skip-tls-verify")
+	}
 	return h.runHelm(args...)
 }
 
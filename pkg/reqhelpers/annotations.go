@@ -0,0 +1,130 @@
+package reqhelpers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// GitOpsControllerArgoCD annotates the boot Job as an ArgoCD sync hook
+	GitOpsControllerArgoCD = "argocd"
+
+	// GitOpsControllerFlux annotates the boot Job so Flux recreates it rather than failing to patch the immutable Job
+	GitOpsControllerFlux = "flux"
+
+	// RunIDAnnotation the annotation of the boot Job recording the ID of the boot run which created it
+	RunIDAnnotation = "helmboot.jenkins-x.io/run-id"
+
+	argoCDHookAnnotation          = "argocd.argoproj.io/hook"
+	argoCDHookDeleteAnnotation    = "argocd.argoproj.io/hook-delete-policy"
+	argoCDSyncWaveAnnotation      = "argocd.argoproj.io/sync-wave"
+	fluxForceAnnotation           = "kustomize.toolkit.fluxcd.io/force"
+	fluxForceAnnotationEnabled    = "enabled"
+	argoCDHookDeletePolicyDefault = "BeforeHookCreation"
+)
+
+// GitOpsControllers the GitOps controllers which have a predefined set of boot Job annotations
+var GitOpsControllers = []string{GitOpsControllerArgoCD, GitOpsControllerFlux}
+
+var (
+	argoCDHooks              = []string{"PreSync", "Sync", "PostSync", "SyncFail", "Skip"}
+	argoCDHookDeletePolicies = []string{"HookSucceeded", "HookFailed", "BeforeHookCreation"}
+)
+
+// GitOpsControllerAnnotations returns the annotations of the boot Job for the given GitOps controller
+func GitOpsControllerAnnotations(controller string) (map[string]string, error) {
+	switch controller {
+	case "":
+		return map[string]string{}, nil
+	case GitOpsControllerArgoCD:
+		return map[string]string{
+			argoCDHookAnnotation:       "Sync",
+			argoCDHookDeleteAnnotation: argoCDHookDeletePolicyDefault,
+		}, nil
+	case GitOpsControllerFlux:
+		return map[string]string{
+			fluxForceAnnotation: fluxForceAnnotationEnabled,
+		}, nil
+	default:
+		return nil, util.InvalidOption("gitops-controller", controller, GitOpsControllers)
+	}
+}
+
+// annotations returns the annotations of the boot Job combining those of the GitOps controller with any explicit
+// annotations which take precedence
+func (o *BootJobOptions) annotations() (map[string]string, error) {
+	answer, err := GitOpsControllerAnnotations(o.GitOpsController)
+	if err != nil {
+		return nil, err
+	}
+	if o.RunID != "" {
+		answer[RunIDAnnotation] = o.RunID
+	}
+	for _, text := range o.Annotations {
+		values := strings.SplitN(text, "=", 2)
+		if len(values) != 2 {
+			return nil, util.InvalidOptionf("job-annotation", text, "the value must be of the form key=value")
+		}
+		key := values[0]
+		messages := validation.IsQualifiedName(key)
+		if len(messages) > 0 {
+			return nil, util.InvalidOptionf("job-annotation", text, "%s is not a valid annotation key: %s", key, strings.Join(messages, ", "))
+		}
+		answer[key] = values[1]
+	}
+	err = validateControllerAnnotations(answer)
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+// validateControllerAnnotations validates the values of the annotations which are understood by GitOps controllers
+func validateControllerAnnotations(annotations map[string]string) error {
+	for key, value := range annotations {
+		switch key {
+		case argoCDHookAnnotation:
+			for _, hook := range strings.Split(value, ",") {
+				if util.StringArrayIndex(argoCDHooks, strings.TrimSpace(hook)) < 0 {
+					return util.InvalidOptionf("job-annotation", fmt.Sprintf("%s=%s", key, value), "the ArgoCD hook must be one of %s", strings.Join(argoCDHooks, ", "))
+				}
+			}
+		case argoCDHookDeleteAnnotation:
+			for _, policy := range strings.Split(value, ",") {
+				if util.StringArrayIndex(argoCDHookDeletePolicies, strings.TrimSpace(policy)) < 0 {
+					return util.InvalidOptionf("job-annotation", fmt.Sprintf("%s=%s", key, value), "the ArgoCD hook delete policy must be one of %s", strings.Join(argoCDHookDeletePolicies, ", "))
+				}
+			}
+		case argoCDSyncWaveAnnotation:
+			_, err := strconv.Atoi(value)
+			if err != nil {
+				return util.InvalidOptionf("job-annotation", fmt.Sprintf("%s=%s", key, value), "the ArgoCD sync wave must be an integer")
+			}
+		case fluxForceAnnotation:
+			if value != fluxForceAnnotationEnabled && value != "disabled" {
+				return util.InvalidOptionf("job-annotation", fmt.Sprintf("%s=%s", key, value), "the Flux force annotation must be enabled or disabled")
+			}
+		}
+	}
+	return nil
+}
+
+// annotationArgs returns the helm arguments for the boot Job annotations in sorted order
+func annotationArgs(annotations map[string]string) []string {
+	keys := make([]string, 0, len(annotations))
+	for k := range annotations {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var args []string
+	for _, k := range keys {
+		// dots in the key would otherwise be treated as nested values by helm
+		args = append(args, "--set-string", fmt.Sprintf("boot.annotations.%s=%s", strings.ReplaceAll(k, ".", "\\."), escapeHelmValue(annotations[k])))
+	}
+	return args
+}
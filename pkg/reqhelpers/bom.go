@@ -0,0 +1,83 @@
+package reqhelpers
+
+import (
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/versionstream"
+	"github.com/pkg/errors"
+)
+
+// BillOfMaterials the charts and images a boot would install so they can be audited before running the boot Job
+type BillOfMaterials struct {
+	// VersionStream the version stream used to resolve the versions
+	VersionStream BOMSource `json:"versionStream"`
+
+	// Charts the charts which are installed
+	Charts []BOMChart `json:"charts"`
+
+	// Images the container images which are overridden rather than coming from the charts
+	Images []BOMImage `json:"images,omitempty"`
+}
+
+// BOMSource the git repository and ref of a source
+type BOMSource struct {
+	URL string `json:"url,omitempty"`
+	Ref string `json:"ref,omitempty"`
+}
+
+// BOMChart a chart which is installed
+type BOMChart struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// BOMImage a container image which is used
+type BOMImage struct {
+	Image  string `json:"image"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// CreateBillOfMaterials creates the bill of materials for the boot chart and the charts used by the requirements
+// resolving their versions from the given version stream directory. Charts missing from the version stream have no version
+func CreateBillOfMaterials(requirements *config.RequirementsConfig, versionsDir string, bootChart string, bootChartVersion string, jobOptions *BootJobOptions) (*BillOfMaterials, error) {
+	resolver := &versionstream.VersionResolver{
+		VersionsDir: versionsDir,
+	}
+	answer := &BillOfMaterials{
+		VersionStream: BOMSource{
+			URL: requirements.VersionStream.URL,
+			Ref: requirements.VersionStream.Ref,
+		},
+		Charts: []BOMChart{
+			{
+				Name:    bootChart,
+				Version: bootChartVersion,
+				Reason:  "the boot Job",
+			},
+		},
+	}
+	for _, rc := range requiredCharts {
+		if !rc.Enabled(requirements) {
+			continue
+		}
+		version, err := resolver.StableVersionNumber(versionstream.KindChart, rc.Chart)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the version of chart %s in the version stream", rc.Chart)
+		}
+		answer.Charts = append(answer.Charts, BOMChart{
+			Name:    rc.Chart,
+			Version: version,
+			Reason:  rc.Reason,
+		})
+	}
+	if jobOptions != nil {
+		image := jobOptions.CustomImage()
+		if image != "" {
+			answer.Images = append(answer.Images, BOMImage{
+				Image:  image,
+				Reason: "the overridden boot Job image",
+			})
+		}
+	}
+	return answer, nil
+}
@@ -0,0 +1,366 @@
+package reqhelpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// KnownBootSteps the names of the steps in the boot pipeline which can be used to resume a boot
+var KnownBootSteps = []string{
+	"validate-git",
+	"verify-preinstall",
+	"install-jx-crds",
+	"helmfile-resolve",
+	"helmfile-system",
+	"verify-ingress",
+	"helmfile-apps",
+	"verify-env",
+	"log-repos",
+	"verify-install",
+}
+
+// JobRestartPolicies the restart policies supported by the boot Job
+var JobRestartPolicies = []string{string(corev1.RestartPolicyNever), string(corev1.RestartPolicyOnFailure)}
+
+// ReservedJobEnvNames the environment variables which are owned by the boot Job and cannot be overridden
+var ReservedJobEnvNames = []string{
+	"BINARY_NAME",
+	"HOME",
+	"JX_BATCH_MODE",
+	"JX_DEBUG_JOB",
+	"JX_SECRETS_YAML",
+	"JXL_SECRET_FILE",
+	"JXL_SECRET_NAME",
+	"PATH",
+	"TOP_LEVEL_COMMAND",
+}
+
+var (
+	envNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// imageTagRegex the valid format of a docker image tag
+	imageTagRegex = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]{0,127}$`)
+
+	// imageRepositoryRegex the valid format of a docker image repository including an optional registry host and port
+	imageRepositoryRegex = regexp.MustCompile(`^[a-z0-9]+([._-][a-z0-9]+)*(:[0-9]+)?(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+)
+
+// BootJobOptions the optional settings used to customise the boot Job
+type BootJobOptions struct {
+	// StartStep the step in the boot pipeline to start from
+	StartStep string
+
+	// EndStep the step in the boot pipeline to end at
+	EndStep string
+
+	// BackoffLimit the number of retries of the Job before it is marked as failed. If nil the chart default is used
+	BackoffLimit *int
+
+	// Deadline the maximum duration the Job may be active for before it is terminated. If zero the chart default is used
+	Deadline time.Duration
+
+	// Env the additional environment variables of the boot container of the form KEY=VALUE
+	Env []string
+
+	// EnvFromSecret the additional environment variables of the boot container populated from a Secret
+	// of the form KEY=SECRET_NAME:SECRET_KEY
+	EnvFromSecret []string
+
+	// Image overrides the image repository of the boot container
+	Image string
+
+	// ImageTag overrides the image tag of the boot container
+	ImageTag string
+
+	// Set additional chart values of the form key=value which helm coerces to numbers and booleans
+	Set []string
+
+	// SetString additional chart values of the form key=value which helm always treats as strings
+	SetString []string
+
+	// BatchMode runs the boot process inside the Job without prompting for user input
+	BatchMode bool
+
+	// RestartPolicy the restartPolicy of the boot pod which must be Never or OnFailure. If blank the chart default is used
+	RestartPolicy string
+
+	// ExtraArgs additional arguments appended verbatim to the boot command without validation
+	ExtraArgs []string
+
+	// GitRef the git ref of the boot configuration the Job boots from. If blank the chart default is used
+	GitRef string
+
+	// CPURequest the CPU request of the boot container such as 500m
+	CPURequest string
+
+	// MemoryRequest the memory request of the boot container such as 512Mi
+	MemoryRequest string
+
+	// CPULimit the CPU limit of the boot container
+	CPULimit string
+
+	// MemoryLimit the memory limit of the boot container
+	MemoryLimit string
+
+	// ImagePullSecrets the names of the docker registry Secrets used to pull the boot image
+	ImagePullSecrets []string
+
+	// Annotations the additional annotations of the boot Job of the form key=value
+	Annotations []string
+
+	// GitOpsController the GitOps controller such as argocd or flux whose annotations are added to the boot Job
+	GitOpsController string
+
+	// RunID the optional ID of the boot run which is recorded in the RunIDAnnotation of the boot Job
+	RunID string
+}
+
+// jobEnvVar an additional environment variable of the boot container
+type jobEnvVar struct {
+	Name       string
+	Value      string
+	SecretName string
+	SecretKey  string
+}
+
+// Validate validates the boot Job options
+func (o *BootJobOptions) Validate() error {
+	if o.StartStep != "" && util.StringArrayIndex(KnownBootSteps, o.StartStep) < 0 {
+		return util.InvalidOption("start-step", o.StartStep, KnownBootSteps)
+	}
+	if o.EndStep != "" && util.StringArrayIndex(KnownBootSteps, o.EndStep) < 0 {
+		return util.InvalidOption("end-step", o.EndStep, KnownBootSteps)
+	}
+	if o.StartStep != "" && o.EndStep != "" && util.StringArrayIndex(KnownBootSteps, o.StartStep) > util.StringArrayIndex(KnownBootSteps, o.EndStep) {
+		return fmt.Errorf("the start-step %s is after the end-step %s", o.StartStep, o.EndStep)
+	}
+	if o.BackoffLimit != nil && *o.BackoffLimit < 0 {
+		return util.InvalidOptionf("job-backoff-limit", *o.BackoffLimit, "the backoff limit must not be negative")
+	}
+	if o.Deadline < 0 {
+		return util.InvalidOptionf("job-deadline", o.Deadline.String(), "the deadline must not be negative")
+	}
+	if o.RestartPolicy != "" && util.StringArrayIndex(JobRestartPolicies, o.RestartPolicy) < 0 {
+		return util.InvalidOption("job-restart-policy", o.RestartPolicy, JobRestartPolicies)
+	}
+	if o.Image != "" && !imageRepositoryRegex.MatchString(o.Image) {
+		return util.InvalidOptionf("boot-image", o.Image, "the image must be an image repository without a tag such as gcr.io/myproject/boot")
+	}
+	if o.ImageTag != "" && !imageTagRegex.MatchString(o.ImageTag) {
+		return util.InvalidOptionf("boot-image-tag", o.ImageTag, "the tag must only contain letters, digits, '_', '.' and '-' and be at most 128 characters")
+	}
+	for _, text := range o.Set {
+		err := validateHelmValue("set", text)
+		if err != nil {
+			return err
+		}
+	}
+	for _, text := range o.SetString {
+		err := validateHelmValue("set-string", text)
+		if err != nil {
+			return err
+		}
+	}
+	err := validateResources("job-cpu", o.CPURequest, "job-cpu-limit", o.CPULimit)
+	if err != nil {
+		return err
+	}
+	err = validateResources("job-memory", o.MemoryRequest, "job-memory-limit", o.MemoryLimit)
+	if err != nil {
+		return err
+	}
+	for _, name := range o.ImagePullSecrets {
+		messages := validation.IsDNS1123Subdomain(name)
+		if len(messages) > 0 {
+			return util.InvalidOptionf("job-image-pull-secret", name, "the value must be a valid Secret name: %s", strings.Join(messages, ", "))
+		}
+	}
+	_, err = o.annotations()
+	if err != nil {
+		return err
+	}
+	_, err = o.envVars()
+	return err
+}
+
+// validateResources validates the request and limit are valid Kubernetes quantities and the request is not above the limit
+func validateResources(requestOption, request, limitOption, limit string) error {
+	var requestQuantity, limitQuantity resource.Quantity
+	var err error
+	if request != "" {
+		requestQuantity, err = resource.ParseQuantity(request)
+		if err != nil {
+			return util.InvalidOptionf(requestOption, request, "the value must be a Kubernetes resource quantity: %s", err.Error())
+		}
+	}
+	if limit != "" {
+		limitQuantity, err = resource.ParseQuantity(limit)
+		if err != nil {
+			return util.InvalidOptionf(limitOption, limit, "the value must be a Kubernetes resource quantity: %s", err.Error())
+		}
+	}
+	if request != "" && limit != "" && requestQuantity.Cmp(limitQuantity) > 0 {
+		return fmt.Errorf("the %s %s must not be greater than the %s %s", requestOption, request, limitOption, limit)
+	}
+	return nil
+}
+
+// validateHelmValue validates the given helm value is of the form key=value
+func validateHelmValue(option, text string) error {
+	values := strings.SplitN(text, "=", 2)
+	if len(values) != 2 || strings.TrimSpace(values[0]) == "" {
+		return util.InvalidOptionf(option, text, "the value must be of the form key=value")
+	}
+	return nil
+}
+
+// IsSingleAttempt returns true if the boot pod is neither restarted nor replaced when it fails so that a single
+// pod failure fails the boot Job
+func (o *BootJobOptions) IsSingleAttempt() bool {
+	return o.RestartPolicy == string(corev1.RestartPolicyNever) && o.BackoffLimit != nil && *o.BackoffLimit == 0
+}
+
+// CustomImage returns a description of the overridden boot image or a blank string if the default image is used
+func (o *BootJobOptions) CustomImage() string {
+	if o.Image == "" && o.ImageTag == "" {
+		return ""
+	}
+	image := o.Image
+	if image == "" {
+		image = "<default image>"
+	}
+	tag := o.ImageTag
+	if tag == "" {
+		tag = "<default tag>"
+	}
+	return image + ":" + tag
+}
+
+// envVars parses the additional environment variables
+func (o *BootJobOptions) envVars() ([]jobEnvVar, error) {
+	var answer []jobEnvVar
+	for _, text := range o.Env {
+		name, value, err := parseJobEnv("job-env", text, "KEY=VALUE")
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, jobEnvVar{Name: name, Value: value})
+	}
+	for _, text := range o.EnvFromSecret {
+		name, value, err := parseJobEnv("job-env-from-secret", text, "KEY=SECRET_NAME:SECRET_KEY")
+		if err != nil {
+			return nil, err
+		}
+		values := strings.SplitN(value, ":", 2)
+		if len(values) != 2 || values[0] == "" || values[1] == "" {
+			return nil, util.InvalidOptionf("job-env-from-secret", text, "the value must be of the form KEY=SECRET_NAME:SECRET_KEY")
+		}
+		answer = append(answer, jobEnvVar{Name: name, SecretName: values[0], SecretKey: values[1]})
+	}
+	return answer, nil
+}
+
+// parseJobEnv parses the given environment variable text of the form KEY=VALUE
+func parseJobEnv(option, text, format string) (string, string, error) {
+	values := strings.SplitN(text, "=", 2)
+	if len(values) != 2 {
+		return "", "", util.InvalidOptionf(option, text, "the value must be of the form %s", format)
+	}
+	name := values[0]
+	if !envNameRegex.MatchString(name) {
+		return "", "", util.InvalidOptionf(option, text, "%s is not a valid environment variable name", name)
+	}
+	if util.StringArrayIndex(ReservedJobEnvNames, name) >= 0 {
+		return "", "", util.InvalidOptionf(option, text, "the environment variable %s is reserved by the boot Job", name)
+	}
+	return name, values[1], nil
+}
+
+// escapeHelmValue escapes the characters which have a special meaning in helm --set values
+func escapeHelmValue(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	return strings.ReplaceAll(value, ",", "\\,")
+}
+
+// Args returns the helm arguments to pass the options into the boot Job chart
+func (o *BootJobOptions) Args() []string {
+	var args []string
+	if o.StartStep != "" {
+		args = append(args, "--set", fmt.Sprintf("boot.startStep=%s", o.StartStep))
+	}
+	if o.EndStep != "" {
+		args = append(args, "--set", fmt.Sprintf("boot.endStep=%s", o.EndStep))
+	}
+	if o.BackoffLimit != nil {
+		args = append(args, "--set", fmt.Sprintf("boot.backoffLimit=%d", *o.BackoffLimit))
+	}
+	if o.Deadline > 0 {
+		args = append(args, "--set", fmt.Sprintf("boot.activeDeadlineSeconds=%d", int64(o.Deadline.Seconds())))
+	}
+	if o.RestartPolicy != "" {
+		args = append(args, "--set", fmt.Sprintf("boot.restartPolicy=%s", o.RestartPolicy))
+	}
+	if o.Image != "" {
+		args = append(args, "--set", fmt.Sprintf("image.repository=%s", o.Image))
+	}
+	if o.ImageTag != "" {
+		args = append(args, "--set-string", fmt.Sprintf("image.tag=%s", o.ImageTag))
+	}
+	if o.BatchMode {
+		args = append(args, "--set", "boot.batchMode=true")
+	}
+	if o.GitRef != "" {
+		args = append(args, "--set-string", fmt.Sprintf("boot.gitRef=%s", escapeHelmValue(o.GitRef)))
+	}
+	resources := []struct {
+		key   string
+		value string
+	}{
+		{key: "resources.requests.cpu", value: o.CPURequest},
+		{key: "resources.requests.memory", value: o.MemoryRequest},
+		{key: "resources.limits.cpu", value: o.CPULimit},
+		{key: "resources.limits.memory", value: o.MemoryLimit},
+	}
+	for _, r := range resources {
+		if r.value != "" {
+			args = append(args, "--set-string", fmt.Sprintf("%s=%s", r.key, r.value))
+		}
+	}
+
+	// the annotations are validated in Validate() so lets ignore any errors here
+	annotations, _ := o.annotations()
+	args = append(args, annotationArgs(annotations)...)
+
+	for i, name := range o.ImagePullSecrets {
+		args = append(args, "--set-string", fmt.Sprintf("imagePullSecrets[%d].name=%s", i, name))
+	}
+
+	// the env vars are validated in Validate() so lets ignore any errors here
+	envVars, _ := o.envVars()
+	for i, e := range envVars {
+		prefix := fmt.Sprintf("boot.env[%d]", i)
+		args = append(args, "--set-string", fmt.Sprintf("%s.name=%s", prefix, e.Name))
+		if e.SecretName != "" {
+			args = append(args, "--set-string", fmt.Sprintf("%s.valueFrom.secretKeyRef.name=%s", prefix, escapeHelmValue(e.SecretName)),
+				"--set-string", fmt.Sprintf("%s.valueFrom.secretKeyRef.key=%s", prefix, escapeHelmValue(e.SecretKey)))
+		} else {
+			args = append(args, "--set-string", fmt.Sprintf("%s.value=%s", prefix, escapeHelmValue(e.Value)))
+		}
+	}
+
+	// lets add the custom values last so that they take precedence
+	for _, text := range o.Set {
+		args = append(args, "--set", text)
+	}
+	for _, text := range o.SetString {
+		args = append(args, "--set-string", text)
+	}
+	return args
+}
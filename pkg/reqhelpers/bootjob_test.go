@@ -0,0 +1,257 @@
+package reqhelpers_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBootJobCommandWithSteps(t *testing.T) {
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.ClusterName = "mycluster"
+
+	jobOptions := &reqhelpers.BootJobOptions{
+		StartStep: "helmfile-system",
+		EndStep:   "verify-install",
+	}
+	require.NoError(t, jobOptions.Validate(), "should have validated the steps")
+
+	c := reqhelpers.GetBootJobCommand(requirements, "https://github.com/myorg/env-mycluster-dev.git", "jx-labs/jxl-boot", "1.2.3", jobOptions)
+	commandLine := strings.Join(c.Args, " ")
+	assert.Equal(t, "helm", c.Name, "command name")
+	assert.Contains(t, commandLine, "--set boot.startStep=helmfile-system", "start step")
+	assert.Contains(t, commandLine, "--set boot.endStep=verify-install", "end step")
+	assert.True(t, strings.HasSuffix(commandLine, "--version 1.2.3 jx-labs/jxl-boot"), "should end with the chart but was: %s", commandLine)
+}
+
+func TestBootJobOptionsValidate(t *testing.T) {
+	zero := 0
+	negative := -1
+	testCases := []struct {
+		name    string
+		options reqhelpers.BootJobOptions
+		valid   bool
+	}{
+		{name: "empty", options: reqhelpers.BootJobOptions{}, valid: true},
+		{name: "unknown start", options: reqhelpers.BootJobOptions{StartStep: "does-not-exist"}},
+		{name: "unknown end", options: reqhelpers.BootJobOptions{EndStep: "does-not-exist"}},
+		{name: "start after end", options: reqhelpers.BootJobOptions{StartStep: "verify-install", EndStep: "validate-git"}},
+		{name: "zero backoff", options: reqhelpers.BootJobOptions{BackoffLimit: &zero}, valid: true},
+		{name: "negative backoff", options: reqhelpers.BootJobOptions{BackoffLimit: &negative}},
+		{name: "negative deadline", options: reqhelpers.BootJobOptions{Deadline: -time.Minute}},
+		{name: "env", options: reqhelpers.BootJobOptions{Env: []string{"FOO=bar", "EMPTY="}}, valid: true},
+		{name: "env missing value", options: reqhelpers.BootJobOptions{Env: []string{"FOO"}}},
+		{name: "env invalid name", options: reqhelpers.BootJobOptions{Env: []string{"1FOO=bar"}}},
+		{name: "env reserved", options: reqhelpers.BootJobOptions{Env: []string{"JX_SECRETS_YAML=/tmp/foo.yaml"}}},
+		{name: "env from secret", options: reqhelpers.BootJobOptions{EnvFromSecret: []string{"TOKEN=mysecret:token"}}, valid: true},
+		{name: "image", options: reqhelpers.BootJobOptions{Image: "gcr.io/myproject/boot", ImageTag: "0.0.1-PR-123"}, valid: true},
+		{name: "image with registry port", options: reqhelpers.BootJobOptions{Image: "localhost:5000/boot"}, valid: true},
+		{name: "image with tag", options: reqhelpers.BootJobOptions{Image: "gcr.io/myproject/boot:1.0.0"}},
+		{name: "invalid image tag", options: reqhelpers.BootJobOptions{ImageTag: "1.0.0/foo"}},
+		{name: "env from secret missing key", options: reqhelpers.BootJobOptions{EnvFromSecret: []string{"TOKEN=mysecret"}}},
+		{name: "restart policy never", options: reqhelpers.BootJobOptions{RestartPolicy: "Never"}, valid: true},
+		{name: "restart policy on failure", options: reqhelpers.BootJobOptions{RestartPolicy: "OnFailure"}, valid: true},
+		{name: "restart policy always", options: reqhelpers.BootJobOptions{RestartPolicy: "Always"}},
+		{name: "resources", options: reqhelpers.BootJobOptions{CPURequest: "500m", MemoryRequest: "512Mi", CPULimit: "2", MemoryLimit: "2Gi"}, valid: true},
+		{name: "invalid cpu", options: reqhelpers.BootJobOptions{CPURequest: "lots"}},
+		{name: "invalid memory limit", options: reqhelpers.BootJobOptions{MemoryLimit: "2GB"}},
+		{name: "request above limit", options: reqhelpers.BootJobOptions{MemoryRequest: "4Gi", MemoryLimit: "2Gi"}},
+		{name: "image pull secrets", options: reqhelpers.BootJobOptions{ImagePullSecrets: []string{"registry-creds", "other.creds"}}, valid: true},
+		{name: "invalid image pull secret", options: reqhelpers.BootJobOptions{ImagePullSecrets: []string{"Registry_Creds"}}},
+		{name: "annotation", options: reqhelpers.BootJobOptions{Annotations: []string{"example.com/owner=platform"}}, valid: true},
+		{name: "annotation missing value", options: reqhelpers.BootJobOptions{Annotations: []string{"owner"}}},
+		{name: "invalid annotation key", options: reqhelpers.BootJobOptions{Annotations: []string{"not a key=value"}}},
+		{name: "argocd", options: reqhelpers.BootJobOptions{GitOpsController: "argocd", Annotations: []string{"argocd.argoproj.io/sync-wave=-1"}}, valid: true},
+		{name: "flux", options: reqhelpers.BootJobOptions{GitOpsController: "flux"}, valid: true},
+		{name: "unknown gitops controller", options: reqhelpers.BootJobOptions{GitOpsController: "spinnaker"}},
+		{name: "invalid argocd hook", options: reqhelpers.BootJobOptions{Annotations: []string{"argocd.argoproj.io/hook=Sometimes"}}},
+		{name: "invalid argocd sync wave", options: reqhelpers.BootJobOptions{Annotations: []string{"argocd.argoproj.io/sync-wave=first"}}},
+	}
+	for _, tc := range testCases {
+		err := tc.options.Validate()
+		if tc.valid {
+			assert.NoError(t, err, "for %s", tc.name)
+		} else {
+			assert.Error(t, err, "for %s", tc.name)
+		}
+	}
+}
+
+func TestBootJobOptionsBackoffAndDeadlineArgs(t *testing.T) {
+	backoffLimit := 3
+	jobOptions := &reqhelpers.BootJobOptions{
+		BackoffLimit: &backoffLimit,
+		Deadline:     2 * time.Hour,
+	}
+	commandLine := strings.Join(jobOptions.Args(), " ")
+	assert.Contains(t, commandLine, "--set boot.backoffLimit=3", "backoff limit")
+	assert.Contains(t, commandLine, "--set boot.activeDeadlineSeconds=7200", "deadline")
+
+	assert.Empty(t, (&reqhelpers.BootJobOptions{}).Args(), "should have no args by default")
+}
+
+func TestBootJobOptionsRestartPolicy(t *testing.T) {
+	zero := 0
+	three := 3
+	jobOptions := &reqhelpers.BootJobOptions{RestartPolicy: "Never"}
+	assert.Equal(t, []string{"--set", "boot.restartPolicy=Never"}, jobOptions.Args(), "restart policy args")
+	assert.False(t, jobOptions.IsSingleAttempt(), "should not be a single attempt with the chart default backoff limit")
+
+	jobOptions.BackoffLimit = &zero
+	assert.True(t, jobOptions.IsSingleAttempt(), "should be a single attempt with a zero backoff limit")
+
+	jobOptions.BackoffLimit = &three
+	assert.False(t, jobOptions.IsSingleAttempt(), "should not be a single attempt with retries")
+
+	jobOptions = &reqhelpers.BootJobOptions{RestartPolicy: "OnFailure", BackoffLimit: &zero}
+	assert.False(t, jobOptions.IsSingleAttempt(), "should not be a single attempt when the container is restarted")
+}
+
+func TestBootJobOptionsResourceArgs(t *testing.T) {
+	jobOptions := &reqhelpers.BootJobOptions{
+		CPURequest:  "500m",
+		MemoryLimit: "2Gi",
+	}
+	assert.Equal(t, []string{
+		"--set-string", "resources.requests.cpu=500m",
+		"--set-string", "resources.limits.memory=2Gi",
+	}, jobOptions.Args())
+}
+
+func TestBootJobOptionsGitRefArgs(t *testing.T) {
+	jobOptions := &reqhelpers.BootJobOptions{GitRef: "v1.2.3"}
+	assert.Equal(t, []string{"--set-string", "boot.gitRef=v1.2.3"}, jobOptions.Args(), "git ref args")
+}
+
+func TestBootJobOptionsEnvArgs(t *testing.T) {
+	jobOptions := &reqhelpers.BootJobOptions{
+		Env:           []string{"FOO=a,b", "ENABLED=true"},
+		EnvFromSecret: []string{"TOKEN=mysecret:token"},
+	}
+	require.NoError(t, jobOptions.Validate(), "should have validated the env vars")
+
+	assert.Equal(t, []string{
+		"--set-string", "boot.env[0].name=FOO",
+		"--set-string", `boot.env[0].value=a\,b`,
+		"--set-string", "boot.env[1].name=ENABLED",
+		"--set-string", "boot.env[1].value=true",
+		"--set-string", "boot.env[2].name=TOKEN",
+		"--set-string", "boot.env[2].valueFrom.secretKeyRef.name=mysecret",
+		"--set-string", "boot.env[2].valueFrom.secretKeyRef.key=token",
+	}, jobOptions.Args(), "env args")
+}
+
+func TestBootJobOptionsImageArgs(t *testing.T) {
+	jobOptions := &reqhelpers.BootJobOptions{
+		Image:    "gcr.io/myproject/boot",
+		ImageTag: "0.0.1-PR-123",
+	}
+	assert.Equal(t, []string{"--set", "image.repository=gcr.io/myproject/boot", "--set-string", "image.tag=0.0.1-PR-123"}, jobOptions.Args(), "image args")
+	assert.Equal(t, "gcr.io/myproject/boot:0.0.1-PR-123", jobOptions.CustomImage(), "custom image")
+
+	assert.Equal(t, "", (&reqhelpers.BootJobOptions{}).CustomImage(), "should not have a custom image by default")
+}
+
+func TestBootJobOptionsImagePullSecretArgs(t *testing.T) {
+	jobOptions := &reqhelpers.BootJobOptions{
+		ImagePullSecrets: []string{"registry-creds", "other-creds"},
+	}
+	assert.Equal(t, []string{
+		"--set-string", "imagePullSecrets[0].name=registry-creds",
+		"--set-string", "imagePullSecrets[1].name=other-creds",
+	}, jobOptions.Args(), "image pull secret args")
+}
+
+func TestBootJobOptionsAnnotationArgs(t *testing.T) {
+	jobOptions := &reqhelpers.BootJobOptions{
+		GitOpsController: "argocd",
+		Annotations:      []string{"argocd.argoproj.io/hook=PreSync", "owner=a,b"},
+	}
+	require.NoError(t, jobOptions.Validate(), "should have validated the annotations")
+	assert.Equal(t, []string{
+		"--set-string", "boot.annotations.argocd\\.argoproj\\.io/hook=PreSync",
+		"--set-string", "boot.annotations.argocd\\.argoproj\\.io/hook-delete-policy=BeforeHookCreation",
+		"--set-string", "boot.annotations.owner=a\\,b",
+	}, jobOptions.Args(), "annotation args")
+
+	jobOptions = &reqhelpers.BootJobOptions{GitOpsController: "flux"}
+	assert.Equal(t, []string{
+		"--set-string", "boot.annotations.kustomize\\.toolkit\\.fluxcd\\.io/force=enabled",
+	}, jobOptions.Args(), "flux annotation args")
+
+	jobOptions = &reqhelpers.BootJobOptions{RunID: "20200401-120000"}
+	assert.Equal(t, []string{
+		"--set-string", "boot.annotations.helmboot\\.jenkins-x\\.io/run-id=20200401-120000",
+	}, jobOptions.Args(), "run ID annotation args")
+}
+
+func TestBootJobOptionsSetArgs(t *testing.T) {
+	jobOptions := &reqhelpers.BootJobOptions{
+		Set:       []string{"boot.replicas=2"},
+		SetString: []string{"boot.token=0123", "boot.enabled=true"},
+	}
+	require.NoError(t, jobOptions.Validate(), "should have validated the values")
+
+	assert.Equal(t, []string{
+		"--set", "boot.replicas=2",
+		"--set-string", "boot.token=0123",
+		"--set-string", "boot.enabled=true",
+	}, jobOptions.Args(), "set args")
+
+	assert.Error(t, (&reqhelpers.BootJobOptions{SetString: []string{"0123"}}).Validate(), "should have failed without a key")
+	assert.Error(t, (&reqhelpers.BootJobOptions{Set: []string{"=2"}}).Validate(), "should have failed with a blank key")
+}
+
+func TestGetBootJobCommandWithExtraArgs(t *testing.T) {
+	requirements := config.NewRequirementsConfig()
+
+	jobOptions := &reqhelpers.BootJobOptions{ExtraArgs: []string{"--timeout", "20m", "--atomic"}}
+	c := reqhelpers.GetBootJobCommand(requirements, "https://github.com/myorg/env-mycluster-dev.git", "jx-labs/jxl-boot", "1.2.3", jobOptions)
+	commandLine := strings.Join(c.Args, " ")
+	assert.True(t, strings.HasSuffix(commandLine, "jx-labs/jxl-boot --timeout 20m --atomic"), "should end with the extra args but was: %s", commandLine)
+}
+
+func TestGetBootJobCommandWithBatchMode(t *testing.T) {
+	requirements := config.NewRequirementsConfig()
+
+	jobOptions := &reqhelpers.BootJobOptions{BatchMode: true}
+	c := reqhelpers.GetBootJobCommand(requirements, "https://github.com/myorg/env-mycluster-dev.git", "jx-labs/jxl-boot", "1.2.3", jobOptions)
+	assert.Contains(t, strings.Join(c.Args, " "), "--set boot.batchMode=true", "batch mode")
+
+	jobOptions.BatchMode = false
+	c = reqhelpers.GetBootJobCommand(requirements, "https://github.com/myorg/env-mycluster-dev.git", "jx-labs/jxl-boot", "1.2.3", jobOptions)
+	assert.NotContains(t, strings.Join(c.Args, " "), "boot.batchMode", "should not pass batch mode by default")
+}
+
+func TestVerifyInstallerDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-installer-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	defer os.RemoveAll(dir)
+
+	_, err = reqhelpers.VerifyInstallerDir(dir)
+	require.Error(t, err, "should have failed for an empty directory")
+
+	for _, name := range []string{"Chart.yaml", "values.yaml"} {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte("name: jxl-boot\n"), 0644)
+		require.NoError(t, err, "failed to write %s", name)
+	}
+	_, err = reqhelpers.VerifyInstallerDir(dir)
+	require.Error(t, err, "should have failed without a templates directory")
+	assert.Contains(t, err.Error(), "templates")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "templates"), 0755), "failed to create templates dir")
+	actual, err := reqhelpers.VerifyInstallerDir(dir)
+	require.NoError(t, err, "should have verified the installer dir")
+	assert.Equal(t, dir, actual, "installer dir")
+
+	_, err = reqhelpers.VerifyInstallerDir(filepath.Join(dir, "missing"))
+	assert.Error(t, err, "should have failed for a missing directory")
+}
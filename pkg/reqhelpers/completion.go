@@ -0,0 +1,136 @@
+package reqhelpers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// CompletionCheckCommandPrefix the prefix of a completion check which runs a command
+const CompletionCheckCommandPrefix = "cmd:"
+
+// CompletionCheck a custom check used to decide when the boot has completed rather than the boot Job status.
+// It is either a command which completes successfully or a condition on a Kubernetes resource which is true
+type CompletionCheck struct {
+	Command   string
+	Kind      string
+	Name      string
+	Namespace string
+	Condition string
+}
+
+// ParseCompletionCheck parses a completion check which is either a command prefixed with 'cmd:' or a
+// condition expression of the form kind/name[@namespace]=Condition such as 'deployment/jenkins=Available'.
+// The supported kinds are deployment, job and pod
+func ParseCompletionCheck(text string) (*CompletionCheck, error) {
+	if strings.HasPrefix(text, CompletionCheckCommandPrefix) {
+		command := strings.TrimSpace(strings.TrimPrefix(text, CompletionCheckCommandPrefix))
+		if command == "" {
+			return nil, util.InvalidOptionf("completion-check", text, "no command specified")
+		}
+		return &CompletionCheck{Command: command}, nil
+	}
+	invalid := func() error {
+		return util.InvalidOptionf("completion-check", text, "must be a command prefixed with '%s' or of the form kind/name[@namespace]=Condition", CompletionCheckCommandPrefix)
+	}
+	idx := strings.LastIndex(text, "=")
+	if idx < 0 {
+		return nil, invalid()
+	}
+	check := &CompletionCheck{
+		Condition: strings.TrimSpace(text[idx+1:]),
+	}
+	paths := strings.Split(strings.TrimSpace(text[0:idx]), "/")
+	if len(paths) != 2 || check.Condition == "" {
+		return nil, invalid()
+	}
+	check.Kind = strings.ToLower(paths[0])
+	check.Name = paths[1]
+	idx = strings.Index(check.Name, "@")
+	if idx >= 0 {
+		check.Namespace = check.Name[idx+1:]
+		check.Name = check.Name[0:idx]
+	}
+	if check.Name == "" {
+		return nil, invalid()
+	}
+	switch check.Kind {
+	case "deployment", "job", "pod":
+	default:
+		return nil, util.InvalidOption("completion-check", check.Kind, []string{"deployment", "job", "pod"})
+	}
+	return check, nil
+}
+
+// String returns a description of the check
+func (c *CompletionCheck) String() string {
+	if c.Command != "" {
+		return fmt.Sprintf("command '%s'", c.Command)
+	}
+	name := c.Name
+	if c.Namespace != "" {
+		name += "@" + c.Namespace
+	}
+	return fmt.Sprintf("condition %s on %s/%s", c.Condition, c.Kind, name)
+}
+
+// IsComplete evaluates the check returning true if the boot is complete. If a resource
+// does not specify a namespace the given namespace is used
+func (c *CompletionCheck) IsComplete(kubeClient kubernetes.Interface, ns string) (bool, error) {
+	if c.Command != "" {
+		cmd := util.Command{
+			Name: "sh",
+			Args: []string{"-c", c.Command},
+		}
+		_, err := cmd.RunWithoutRetry()
+		if err != nil {
+			log.Logger().Debugf("completion check %s has not passed: %s", c.String(), err.Error())
+			return false, nil
+		}
+		return true, nil
+	}
+	if c.Namespace != "" {
+		ns = c.Namespace
+	}
+	conditions := map[string]corev1.ConditionStatus{}
+	switch c.Kind {
+	case "deployment":
+		resource, err := kubeClient.AppsV1().Deployments(ns).Get(c.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get Deployment %s in namespace %s", c.Name, ns)
+		}
+		for _, cond := range resource.Status.Conditions {
+			conditions[string(cond.Type)] = cond.Status
+		}
+	case "job":
+		resource, err := kubeClient.BatchV1().Jobs(ns).Get(c.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get Job %s in namespace %s", c.Name, ns)
+		}
+		for _, cond := range resource.Status.Conditions {
+			conditions[string(cond.Type)] = cond.Status
+		}
+	case "pod":
+		resource, err := kubeClient.CoreV1().Pods(ns).Get(c.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to get Pod %s in namespace %s", c.Name, ns)
+		}
+		for _, cond := range resource.Status.Conditions {
+			conditions[string(cond.Type)] = cond.Status
+		}
+	default:
+		return false, errors.Errorf("unsupported completion check kind %s", c.Kind)
+	}
+	for k, v := range conditions {
+		if strings.EqualFold(k, c.Condition) {
+			return v == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,63 @@
+package reqhelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestParseCompletionCheck(t *testing.T) {
+	check, err := reqhelpers.ParseCompletionCheck("deployment/jenkins@jenkins=Available")
+	require.NoError(t, err, "failed to parse condition check")
+	assert.Equal(t, reqhelpers.CompletionCheck{Kind: "deployment", Name: "jenkins", Namespace: "jenkins", Condition: "Available"}, *check)
+
+	check, err = reqhelpers.ParseCompletionCheck("cmd: curl -f https://jenkins.example.com/login")
+	require.NoError(t, err, "failed to parse command check")
+	assert.Equal(t, "curl -f https://jenkins.example.com/login", check.Command)
+
+	for _, text := range []string{"deployment/jenkins", "service/jenkins=Ready", "cmd:", "jenkins=Available"} {
+		_, err = reqhelpers.ParseCompletionCheck(text)
+		assert.Error(t, err, "should have failed to parse %s", text)
+	}
+}
+
+func TestCompletionCheckIsComplete(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jenkins",
+			Namespace: "jx",
+		},
+		Status: appsv1.DeploymentStatus{
+			Conditions: []appsv1.DeploymentCondition{
+				{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue},
+				{Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionFalse},
+			},
+		},
+	})
+
+	testCases := map[string]bool{
+		"deployment/jenkins=Available":      true,
+		"deployment/jenkins=ReplicaFailure": false,
+		"deployment/jenkins=Progressing":    false,
+		"cmd: true":                         true,
+		"cmd: false":                        false,
+	}
+	for text, expected := range testCases {
+		check, err := reqhelpers.ParseCompletionCheck(text)
+		require.NoError(t, err, "failed to parse %s", text)
+		actual, err := check.IsComplete(kubeClient, "jx")
+		require.NoError(t, err, "failed to evaluate %s", text)
+		assert.Equal(t, expected, actual, "for %s", text)
+	}
+
+	check, err := reqhelpers.ParseCompletionCheck("deployment/missing=Available")
+	require.NoError(t, err, "failed to parse check")
+	_, err = check.IsComplete(kubeClient, "jx")
+	assert.Error(t, err, "should have failed for a missing deployment")
+}
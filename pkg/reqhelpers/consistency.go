@@ -0,0 +1,85 @@
+package reqhelpers
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/jxfactory"
+	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// RequirementsConflict a field which has a different value in the local requirements and the dev Environment
+type RequirementsConflict struct {
+	Field       string
+	Local       string
+	Environment string
+}
+
+// String returns a description of the conflict
+func (c RequirementsConflict) String() string {
+	return fmt.Sprintf("%s is '%s' locally but '%s' in the dev Environment", c.Field, c.Local, c.Environment)
+}
+
+// FindRequirementsConflicts loads the requirements from both the local directory and the dev Environment
+// returning the key fields which disagree. If either source is missing then there are no conflicts
+func FindRequirementsConflicts(jxFactory jxfactory.Factory, gitURLOption string, gitter gits.Gitter, dir string) ([]RequirementsConflict, error) {
+	local, fileName, err := config.LoadRequirementsConfig(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load requirements from %s", dir)
+	}
+	if fileName == "" {
+		return nil, nil
+	}
+
+	jxClient, ns, err := jxFactory.CreateJXClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the Jenkins X client")
+	}
+	devEnv, err := kube.GetDevEnvironment(jxClient, ns)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to find the 'dev' Environment in namespace %s", ns)
+	}
+	if devEnv == nil {
+		return nil, nil
+	}
+	envRequirements, err := config.GetRequirementsConfigFromTeamSettings(&devEnv.Spec.TeamSettings)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load requirements from the 'dev' Environment in namespace %s", ns)
+	}
+
+	var answer []RequirementsConflict
+	addConflict := func(field, localValue, envValue string) {
+		if localValue != "" && envValue != "" && localValue != envValue {
+			answer = append(answer, RequirementsConflict{Field: field, Local: localValue, Environment: envValue})
+		}
+	}
+	if envRequirements != nil {
+		addConflict("cluster.clusterName", local.Cluster.ClusterName, envRequirements.Cluster.ClusterName)
+		addConflict("cluster.provider", local.Cluster.Provider, envRequirements.Cluster.Provider)
+	}
+
+	localGitURL := gitURLOption
+	if localGitURL == "" {
+		localGitURL, _ = findGitURLFromDir(gitter, dir)
+	}
+	addConflict("git URL", normalizeGitURL(localGitURL), normalizeGitURL(devEnv.Spec.Source.URL))
+	return answer, nil
+}
+
+// normalizeGitURL removes any user, password and .git suffix so that git URLs can be compared
+func normalizeGitURL(gitURL string) string {
+	if gitURL == "" {
+		return ""
+	}
+	u, err := url.Parse(gitURL)
+	if err == nil {
+		u.User = nil
+		gitURL = u.String()
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(gitURL, "/"), ".git")
+}
@@ -0,0 +1,93 @@
+package reqhelpers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FailureSignature a known reason for a boot Job failing which can be detected from its log output
+type FailureSignature struct {
+	Name        string
+	Pattern     *regexp.Regexp
+	Diagnosis   string
+	Remediation string
+}
+
+// String returns a description of the failure and how to fix it
+func (s FailureSignature) String() string {
+	return fmt.Sprintf("%s: %s. %s", s.Name, s.Diagnosis, s.Remediation)
+}
+
+// BootJobFailureSignatures the known reasons for a boot Job to fail. Add new signatures here
+var BootJobFailureSignatures = []FailureSignature{
+	{
+		Name:        "authentication failure",
+		Pattern:     regexp.MustCompile(`(?i)(authentication failed|invalid username or password|401 unauthorized|could not read username|permission denied \(publickey\))`),
+		Diagnosis:   "the boot Job could not authenticate with the git provider or registry",
+		Remediation: "Please check the git user and token in the boot secrets via 'helmboot secrets edit'",
+	},
+	{
+		Name:        "quota exceeded",
+		Pattern:     regexp.MustCompile(`(?i)(quota exceeded|exceeded quota|insufficient regional quota|QUOTA_EXCEEDED)`),
+		Diagnosis:   "a cloud or Kubernetes resource quota has been exceeded",
+		Remediation: "Please increase the quota in your cloud project or namespace or remove unused resources then run again",
+	},
+	{
+		Name:        "CRD conflict",
+		Pattern:     regexp.MustCompile(`(?i)(customresourcedefinitions?\.apiextensions\.k8s\.io "[^"]+" already exists|rendered manifests contain a resource that already exists)`),
+		Diagnosis:   "a CustomResourceDefinition or resource already exists and is not owned by this installation",
+		Remediation: "Please remove the conflicting resource or adopt it into the helm release then run again",
+	},
+	{
+		Name:        "webhook timeout",
+		Pattern:     regexp.MustCompile(`(?i)failed calling webhook .*(timeout|deadline exceeded|connection refused)`),
+		Diagnosis:   "an admission webhook did not respond in time",
+		Remediation: "Please check the webhook pods are running (e.g. with 'kubectl get validatingwebhookconfigurations,mutatingwebhookconfigurations') and that the API server can reach them",
+	},
+}
+
+// DiagnoseBootJobFailure returns the known failure signatures which match the given boot Job log output
+func DiagnoseBootJobFailure(logs string) []FailureSignature {
+	var answer []FailureSignature
+	for _, s := range BootJobFailureSignatures {
+		if s.Pattern.MatchString(logs) {
+			answer = append(answer, s)
+		}
+	}
+	return answer
+}
+
+// WithFailureDiagnosis appends any diagnosis of the boot Job log output to the given error
+func WithFailureDiagnosis(err error, logs string) error {
+	if err == nil {
+		return nil
+	}
+	signatures := DiagnoseBootJobFailure(logs)
+	if len(signatures) == 0 {
+		return err
+	}
+	var lines []string
+	for _, s := range signatures {
+		lines = append(lines, s.String())
+	}
+	return errors.Wrapf(err, "possible cause: %s", strings.Join(lines, "; "))
+}
+
+// TruncateLogs truncates the log output to at most the given number of bytes keeping the tail, which usually
+// contains the error, along with a marker of how much was removed. A limit of zero means no limit
+func TruncateLogs(logs string, maxBytes int64) string {
+	if maxBytes <= 0 || int64(len(logs)) <= maxBytes {
+		return logs
+	}
+	tail := logs[int64(len(logs))-maxBytes:]
+
+	// lets avoid starting part way through a line
+	idx := strings.Index(tail, "\n")
+	if idx >= 0 && idx < len(tail)-1 {
+		tail = tail[idx+1:]
+	}
+	return fmt.Sprintf("... truncated %d bytes of log output ...\n%s", len(logs)-len(tail), tail)
+}
@@ -0,0 +1,51 @@
+package reqhelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnoseBootJobFailure(t *testing.T) {
+	testCases := []struct {
+		logs     string
+		expected string
+	}{
+		{logs: "fatal: Authentication failed for 'https://github.com/myorg/env-mycluster-dev.git/'", expected: "authentication failure"},
+		{logs: "Error: googleapi: Error 403: Quota exceeded for quota metric 'CPUS'", expected: "quota exceeded"},
+		{logs: `Error: customresourcedefinitions.apiextensions.k8s.io "environments.jenkins.io" already exists`, expected: "CRD conflict"},
+		{logs: `Internal error occurred: failed calling webhook "validate.nginx.ingress.kubernetes.io": context deadline exceeded`, expected: "webhook timeout"},
+	}
+	for _, tc := range testCases {
+		signatures := reqhelpers.DiagnoseBootJobFailure(tc.logs)
+		if assert.Len(t, signatures, 1, "for logs: %s", tc.logs) {
+			assert.Equal(t, tc.expected, signatures[0].Name, "for logs: %s", tc.logs)
+		}
+	}
+
+	assert.Empty(t, reqhelpers.DiagnoseBootJobFailure("step verify-install completed"), "should not diagnose successful logs")
+}
+
+func TestWithFailureDiagnosis(t *testing.T) {
+	err := errors.New("the boot Job has failed")
+
+	assert.Equal(t, err, reqhelpers.WithFailureDiagnosis(err, "nothing to see here"), "should not modify the error without a diagnosis")
+
+	diagnosed := reqhelpers.WithFailureDiagnosis(err, "remote: Invalid username or password.")
+	assert.Contains(t, diagnosed.Error(), "authentication failure", "diagnosis")
+	assert.Contains(t, diagnosed.Error(), "the boot Job has failed", "original error")
+	assert.Equal(t, err, errors.Cause(diagnosed), "cause")
+
+	assert.NoError(t, reqhelpers.WithFailureDiagnosis(nil, "Authentication failed"), "should not create an error")
+}
+
+func TestTruncateLogs(t *testing.T) {
+	logs := "line 1\nline 2\nline 3\nerror: failed\n"
+	assert.Equal(t, logs, reqhelpers.TruncateLogs(logs, 0), "no limit")
+	assert.Equal(t, logs, reqhelpers.TruncateLogs(logs, 1000), "under the limit")
+
+	actual := reqhelpers.TruncateLogs(logs, 20)
+	assert.Equal(t, "... truncated 21 bytes of log output ...\nerror: failed\n", actual, "truncated logs")
+}
@@ -0,0 +1,28 @@
+package reqhelpers
+
+import (
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"sigs.k8s.io/yaml"
+)
+
+// RequirementsDiff returns a unified diff of the YAML of the original and overridden requirements
+// or a blank string if they are the same
+func RequirementsDiff(original *config.RequirementsConfig, overridden *config.RequirementsConfig) (string, error) {
+	from, err := yaml.Marshal(original)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the original requirements to YAML")
+	}
+	to, err := yaml.Marshal(overridden)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the overridden requirements to YAML")
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(from)),
+		B:        difflib.SplitLines(string(to)),
+		FromFile: "original/" + config.RequirementsConfigFileName,
+		ToFile:   "overridden/" + config.RequirementsConfigFileName,
+		Context:  3,
+	})
+}
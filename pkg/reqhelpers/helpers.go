@@ -5,6 +5,8 @@ import (
 	"io/ioutil"
 	"os"
 
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
 	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/pkg/cloud"
@@ -41,9 +43,26 @@ func GetDevEnvironmentConfig(requirements *config.RequirementsConfig) *config.En
 }
 
 // GetBootJobCommand returns the boot job command
-func GetBootJobCommand(requirements *config.RequirementsConfig, gitURL string, chartName string, version string) util.Command {
+func GetBootJobCommand(requirements *config.RequirementsConfig, gitURL string, chartName string, version string, jobOptions *BootJobOptions) util.Command {
 	args := []string{"install", "jx-boot"}
+	args = append(args, GetBootJobValueArgs(requirements, gitURL, jobOptions)...)
+	if version != "" {
+		args = append(args, "--version", version)
+	}
+	args = append(args, chartName)
+	if jobOptions != nil {
+		args = append(args, jobOptions.ExtraArgs...)
+	}
+
+	return util.Command{
+		Name: "helm",
+		Args: args,
+	}
+}
 
+// GetBootJobValueArgs returns the helm value arguments of the boot Job chart derived from the requirements
+func GetBootJobValueArgs(requirements *config.RequirementsConfig, gitURL string, jobOptions *BootJobOptions) []string {
+	var args []string
 	provider := requirements.Cluster.Provider
 	if provider != "" {
 		args = append(args, "--set", fmt.Sprintf("jxRequirements.cluster.provider=%s", provider))
@@ -62,15 +81,10 @@ func GetBootJobCommand(requirements *config.RequirementsConfig, gitURL string, c
 	if gitURL != "" {
 		args = append(args, "--set", fmt.Sprintf("jxRequirements.bootConfigURL=%s", gitURL))
 	}
-	if version != "" {
-		args = append(args, "--version", version)
-	}
-	args = append(args, chartName)
-
-	return util.Command{
-		Name: "helm",
-		Args: args,
+	if jobOptions != nil {
+		args = append(args, jobOptions.Args()...)
 	}
+	return args
 }
 
 // GetRequirementsFromEnvironment tries to find the development environment then the requirements from it
@@ -105,19 +119,67 @@ func GetRequirementsFromEnvironment(kubeClient kubernetes.Interface, jxClient ve
 	return devEnv, requirements, nil
 }
 
-// GetRequirementsFromGit clones the given git repository to get the requirements
-func GetRequirementsFromGit(gitURL string) (*config.RequirementsConfig, error) {
+// GetRequirementsFromGit clones the given git repository to get the requirements using the given gitter
+// or the git CLI if none is specified
+func GetRequirementsFromGit(gitter gits.Gitter, gitURL string) (*config.RequirementsConfig, error) {
+	return GetRequirementsFromGitRef(gitter, gitURL, "")
+}
+
+// GetRequirementsFromGitInWorkDir gets the requirements from the given ref of the git repository reusing any existing
+// clone inside the work directory. If no work directory is specified a temporary clone is used. If no ref is specified
+// the default branch is used
+func GetRequirementsFromGitInWorkDir(gitter gits.Gitter, gitURL string, ref string, workDir string) (*config.RequirementsConfig, error) {
+	if workDir == "" {
+		return GetRequirementsFromGitRef(gitter, gitURL, ref)
+	}
+	if gitter == nil {
+		gitter = gits.NewGitCLI()
+	}
+	dir, err := githelpers.CloneOrFetchInWorkDir(gitter, gitURL, ref, workDir)
+	if err != nil {
+		return nil, err
+	}
+	requirements, _, err := config.LoadRequirementsConfig(dir)
+	if err != nil {
+		return requirements, errors.Wrapf(err, "failed to requirements YAML file from %s", dir)
+	}
+	return requirements, nil
+}
+
+// GetRequirementsFromGitRef clones the given ref of the git repository to get the requirements using the given gitter
+// or the git CLI if none is specified. The ref can be a branch, tag or commit SHA which is checked out as a detached HEAD
+func GetRequirementsFromGitRef(gitter gits.Gitter, gitURL string, ref string) (*config.RequirementsConfig, error) {
 	tempDir, err := ioutil.TempDir("", "jx-boot-")
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create temp dir")
 	}
+	defer os.RemoveAll(tempDir)
 
-	log.Logger().Debugf("cloning %s to %s", gitURL, tempDir)
+	log.Logger().Debugf("cloning %s to %s", githelpers.RedactURLs(gitURL), tempDir)
 
-	gitter := gits.NewGitCLI()
-	err = gitter.Clone(gitURL, tempDir)
+	if gitter == nil {
+		gitter = gits.NewGitCLI()
+	}
+	if ref != "" {
+		// a shallow clone fetches the branch, tag or full commit SHA then checks it out as a detached HEAD
+		err = gitter.ShallowClone(tempDir, gitURL, ref, "")
+		if err != nil && githelpers.IsCommitSHA(ref) && !githelpers.IsFullCommitSHA(ref) {
+			// the ref is not a branch or tag and an abbreviated commit SHA cannot be fetched directly so lets clone
+			// then checkout the detached commit
+			log.Logger().Debugf("could not fetch ref %s so cloning to find the commit: %s", ref, githelpers.RedactError(err).Error())
+			err = recreateDir(tempDir)
+			if err == nil {
+				err = githelpers.CloneWithProgress(gitter, gitURL, tempDir)
+			}
+			if err == nil {
+				err = gitter.Checkout(tempDir, ref)
+			}
+		}
+	} else {
+		err = githelpers.CloneWithProgress(gitter, gitURL, tempDir)
+	}
 	if err != nil {
-		return nil, errors.Wrapf(err, "failed to git clone %s to dir %s", gitURL, tempDir)
+		return nil, githelpers.RedactError(errors.Wrapf(err, "failed to git clone %s to dir %s", gitURL, tempDir))
 	}
 
 	requirements, _, err := config.LoadRequirementsConfig(tempDir)
@@ -127,6 +189,19 @@ func GetRequirementsFromGit(gitURL string) (*config.RequirementsConfig, error) {
 	return requirements, nil
 }
 
+// recreateDir removes the contents of the directory so that it can be cloned into again
+func recreateDir(dir string) error {
+	err := os.RemoveAll(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove dir %s", dir)
+	}
+	err = os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create dir %s", dir)
+	}
+	return nil
+}
+
 // OverrideRequirements allows CLI overrides
 func OverrideRequirements(cmd *cobra.Command, args []string, dir string, outputRequirements *config.RequirementsConfig, flags *RequirementFlags) error {
 	requirements, fileName, err := config.LoadRequirementsConfig(dir)
@@ -356,21 +431,27 @@ func defaultStorage(storage *config.StorageEntryConfig) {
 	}
 }
 
-// FindRequirementsAndGitURL tries to find the requirements and git URL via either environment or directory
-func FindRequirementsAndGitURL(jxFactory jxfactory.Factory, gitURLOption string, gitter gits.Gitter, dir string) (*config.RequirementsConfig, string, error) {
+// FindRequirementsAndGitURL tries to find the requirements and git URL via either environment or directory.
+// The requirements are loaded from the given ref of the git URL option or its default branch if the ref is blank.
+// If a work directory is specified any clone of the git URL is kept there to be reused on the next run. If the factory
+// is nil the dev Environment is not consulted so only the git URL option and the directory are used
+func FindRequirementsAndGitURL(jxFactory jxfactory.Factory, gitURLOption string, gitRef string, gitter gits.Gitter, dir string, workDir string) (*config.RequirementsConfig, string, error) {
 	var requirements *config.RequirementsConfig
 	gitURL := gitURLOption
 
 	var err error
 	if gitURLOption != "" {
 		if requirements == nil {
-			requirements, err = GetRequirementsFromGit(gitURL)
+			common.Tracef(common.TraceDecision, "loading the requirements from the git URL %s", gitURL)
+			requirements, err = GetRequirementsFromGitInWorkDir(gitter, gitURL, gitRef, workDir)
 			if err != nil {
-				return requirements, gitURL, errors.Wrapf(err, "failed to get requirements from git URL %s", gitURL)
+				return requirements, gitURL, errors.Wrapf(err, "failed to get requirements from git URL %s", githelpers.RedactURLs(gitURL))
 			}
 		}
 	}
-	if gitURL == "" || requirements == nil {
+	if jxFactory == nil {
+		common.Tracef(common.TraceDecision, "ignoring the dev Environment")
+	} else if gitURL == "" || requirements == nil {
 		jxClient, ns, err := jxFactory.CreateJXClient()
 		if err != nil {
 			return requirements, gitURL, err
@@ -382,14 +463,21 @@ func FindRequirementsAndGitURL(jxFactory jxfactory.Factory, gitURLOption string,
 		if devEnv != nil {
 			if gitURL == "" {
 				gitURL = devEnv.Spec.Source.URL
+				common.Tracef(common.TraceDecision, "using the git URL %s of the dev Environment in namespace %s", gitURL, ns)
 			}
 			requirements, err = config.GetRequirementsConfigFromTeamSettings(&devEnv.Spec.TeamSettings)
 			if err != nil {
 				log.Logger().Debugf("failed to load requirements from team settings %s", err.Error())
 			}
+			if requirements != nil {
+				common.Tracef(common.TraceDecision, "using the requirements of the dev Environment in namespace %s", ns)
+			}
+		} else {
+			common.Tracef(common.TraceDecision, "there is no dev Environment in namespace %s", ns)
 		}
 	}
 	if requirements == nil {
+		common.Tracef(common.TraceDecision, "loading the requirements from directory %s", dir)
 		requirements, _, err = config.LoadRequirementsConfig(dir)
 		if err != nil {
 			return requirements, gitURL, err
@@ -402,6 +490,7 @@ func FindRequirementsAndGitURL(jxFactory jxfactory.Factory, gitURLOption string,
 		if err != nil {
 			return requirements, gitURL, errors.Wrapf(err, "your cluster has not been booted before and you are not inside a git clone of your dev environment repository so you need to pass in the URL of the git repository as --git-url")
 		}
+		common.Tracef(common.TraceDecision, "using the git URL %s of the git clone in directory %s", gitURL, dir)
 	}
 	return requirements, gitURL, nil
 }
@@ -0,0 +1,40 @@
+package reqhelpers
+
+import (
+	"path/filepath"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// installerFiles the files and directories a local installer chart directory must contain
+var installerFiles = []string{"Chart.yaml", "values.yaml", "templates"}
+
+// VerifyInstallerDir verifies the directory looks like a local copy of the boot installer chart returning its absolute path
+func VerifyInstallerDir(dir string) (string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the absolute path of %s", dir)
+	}
+	exists, err := util.DirExists(absDir)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to check if directory %s exists", absDir)
+	}
+	if !exists {
+		return "", util.InvalidOptionf("installer-dir", dir, "the directory does not exist")
+	}
+	for _, name := range installerFiles {
+		path := filepath.Join(absDir, name)
+		exists, err = util.FileExists(path)
+		if err == nil && !exists {
+			exists, err = util.DirExists(path)
+		}
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to check if %s exists", path)
+		}
+		if !exists {
+			return "", util.InvalidOptionf("installer-dir", dir, "the directory does not look like the boot installer chart as it does not contain %s", name)
+		}
+	}
+	return absDir, nil
+}
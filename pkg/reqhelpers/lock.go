@@ -0,0 +1,84 @@
+package reqhelpers
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// BootLockFile the default name of the file recording the exact commit a boot was pinned to
+const BootLockFile = ".helmboot-lock.yaml"
+
+// BootLock records the commit the git ref of the boot configuration resolved to so that a boot can be reproduced
+type BootLock struct {
+	// GitURL the git URL of the boot configuration without any credentials
+	GitURL string `json:"gitURL"`
+
+	// GitRef the git ref such as a branch which was resolved
+	GitRef string `json:"gitRef"`
+
+	// GitCommit the full commit SHA the git ref resolved to
+	GitCommit string `json:"gitCommit"`
+
+	// LockedAt the time the git ref was resolved
+	LockedAt time.Time `json:"lockedAt"`
+}
+
+// NewBootLock creates a lock of the git ref to the commit removing any credentials from the git URL
+func NewBootLock(gitURL string, gitRef string, gitCommit string) *BootLock {
+	return &BootLock{
+		GitURL:    githelpers.StripCredentials(gitURL),
+		GitRef:    gitRef,
+		GitCommit: gitCommit,
+		LockedAt:  time.Now().UTC(),
+	}
+}
+
+// LoadBootLock loads the lock file
+func LoadBootLock(fileName string) (*BootLock, error) {
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if lock file %s exists", fileName)
+	}
+	if !exists {
+		return nil, errors.Errorf("lock file %s does not exist. Please run the boot with --lock to create it", fileName)
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load lock file %s", fileName)
+	}
+	lock := &BootLock{}
+	err = yaml.UnmarshalStrict(data, lock)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal lock file %s", fileName)
+	}
+	if !githelpers.IsFullCommitSHA(lock.GitCommit) {
+		return nil, errors.Errorf("the lock file %s does not contain a full commit SHA but %q", fileName, lock.GitCommit)
+	}
+	return lock, nil
+}
+
+// SaveBootLock saves the lock file
+func SaveBootLock(fileName string, lock *BootLock) error {
+	data, err := yaml.Marshal(lock)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the lock to YAML")
+	}
+	err = ioutil.WriteFile(fileName, data, util.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save lock file %s", fileName)
+	}
+	return nil
+}
+
+// VerifyGitURL returns an error if the lock is for a different git URL
+func (l *BootLock) VerifyGitURL(gitURL string) error {
+	if githelpers.StripCredentials(gitURL) != l.GitURL {
+		return errors.Errorf("the lock is for the git URL %s but the boot is of %s", l.GitURL, githelpers.StripCredentials(gitURL))
+	}
+	return nil
+}
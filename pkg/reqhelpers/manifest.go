@@ -0,0 +1,58 @@
+package reqhelpers
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// GetBootJobTemplateCommand converts the helm command to install the boot Job into one which renders
+// the chart templates to stdout without applying them
+func GetBootJobTemplateCommand(c util.Command) util.Command {
+	args := append([]string{}, c.Args...)
+	if len(args) > 0 && args[0] == "install" {
+		args[0] = "template"
+	}
+	c.Args = args
+	return c
+}
+
+// GetBootJobLintCommands returns the helm lint and helm template commands which validate the boot Job chart in the
+// given directory using the same values as the boot Job
+func GetBootJobLintCommands(requirements *config.RequirementsConfig, gitURL string, chartDir string, jobOptions *BootJobOptions) []util.Command {
+	values := GetBootJobValueArgs(requirements, gitURL, jobOptions)
+	return []util.Command{
+		{
+			Name: "helm",
+			Args: append([]string{"lint", chartDir}, values...),
+		},
+		{
+			Name: "helm",
+			Args: append([]string{"template", "jx-boot", chartDir}, values...),
+		},
+	}
+}
+
+// FilterManifestsByKind returns the YAML documents in the given multi document manifest which are of the given kind
+func FilterManifestsByKind(manifest string, kind string) (string, error) {
+	var docs []string
+	for _, doc := range strings.Split("\n"+manifest, "\n---") {
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		header := struct {
+			Kind string `json:"kind"`
+		}{}
+		err := yaml.Unmarshal([]byte(doc), &header)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to parse the YAML manifest")
+		}
+		if header.Kind == kind {
+			docs = append(docs, strings.TrimSpace(doc)+"\n")
+		}
+	}
+	return strings.Join(docs, "---\n"), nil
+}
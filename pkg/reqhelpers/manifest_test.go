@@ -0,0 +1,60 @@
+package reqhelpers_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBootJobTemplateCommand(t *testing.T) {
+	c := util.Command{
+		Name: "helm",
+		Args: []string{"install", "jx-boot", "--version", "1.2.3", "jx-labs/jxl-boot"},
+	}
+	tc := reqhelpers.GetBootJobTemplateCommand(c)
+	assert.Equal(t, []string{"template", "jx-boot", "--version", "1.2.3", "jx-labs/jxl-boot"}, tc.Args, "template args")
+	assert.Equal(t, "install", c.Args[0], "should not have modified the original command")
+}
+
+func TestGetBootJobLintCommands(t *testing.T) {
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.Provider = "gke"
+	jobOptions := &reqhelpers.BootJobOptions{ExtraArgs: []string{"--wait"}}
+
+	commands := reqhelpers.GetBootJobLintCommands(requirements, "https://github.com/myorg/env-dev.git", "/tmp/jxl-boot", jobOptions)
+	require.Len(t, commands, 2, "commands")
+
+	values := reqhelpers.GetBootJobValueArgs(requirements, "https://github.com/myorg/env-dev.git", jobOptions)
+	assert.Contains(t, values, "jxRequirements.cluster.provider=gke", "values")
+	assert.Equal(t, append([]string{"lint", "/tmp/jxl-boot"}, values...), commands[0].Args, "lint args")
+	assert.Equal(t, append([]string{"template", "jx-boot", "/tmp/jxl-boot"}, values...), commands[1].Args, "template args")
+	assert.NotContains(t, commands[1].Args, "--wait", "should not pass the extra install arguments")
+}
+
+func TestFilterManifestsByKind(t *testing.T) {
+	manifest := `---
+# Source: jxl-boot/templates/sa.yaml
+apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: jx-boot
+---
+# Source: jxl-boot/templates/job.yaml
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: jx-boot
+`
+	actual, err := reqhelpers.FilterManifestsByKind(manifest, "Job")
+	require.NoError(t, err, "failed to filter manifest")
+	assert.Equal(t, `# Source: jxl-boot/templates/job.yaml
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: jx-boot
+`, actual, "filtered manifest")
+}
@@ -0,0 +1,60 @@
+package reqhelpers
+
+import (
+	"io/ioutil"
+
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// MergeRequirementsFile returns the requirements with the given requirements YAML file deep merged on top of them.
+// Maps are merged recursively while any other value, including lists, in the file replaces the original value
+func MergeRequirementsFile(requirements *config.RequirementsConfig, fileName string) (*config.RequirementsConfig, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load requirements file %s", fileName)
+	}
+	overlay := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &overlay)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse requirements file %s", fileName)
+	}
+
+	data, err = yaml.Marshal(requirements)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the requirements to YAML")
+	}
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the requirements YAML")
+	}
+	mergeValues(values, overlay)
+
+	data, err = yaml.Marshal(values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the merged requirements to YAML")
+	}
+	answer := &config.RequirementsConfig{}
+	err = yaml.UnmarshalStrict(data, answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid requirements after merging file %s", fileName)
+	}
+	return answer, nil
+}
+
+// mergeValues recursively merges the overlay into the target with the overlay values winning
+func mergeValues(target map[string]interface{}, overlay map[string]interface{}) {
+	for k, v := range overlay {
+		overlayMap, ok := v.(map[string]interface{})
+		if ok {
+			targetMap, ok := target[k].(map[string]interface{})
+			if ok {
+				mergeValues(targetMap, overlayMap)
+				continue
+			}
+		}
+		target[k] = v
+	}
+}
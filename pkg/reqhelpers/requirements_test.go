@@ -0,0 +1,216 @@
+package reqhelpers_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoadRequirementsWithAnchors verifies that YAML anchors and aliases are resolved when loading requirements
+func TestLoadRequirementsWithAnchors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-requirements-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, config.RequirementsConfigFileName)
+	err = ioutil.WriteFile(fileName, []byte(`cluster:
+  clusterName: &name mycluster
+  project: *name
+  environmentGitOwner: myorg
+`), util.DefaultFileWritePermissions)
+	require.NoError(t, err, "failed to save %s", fileName)
+
+	requirements, _, err := config.LoadRequirementsConfig(dir)
+	require.NoError(t, err, "failed to load requirements from %s", dir)
+	assert.Equal(t, "mycluster", requirements.Cluster.ClusterName, "clusterName")
+	assert.Equal(t, "mycluster", requirements.Cluster.ProjectID, "project")
+
+	err = ioutil.WriteFile(fileName, []byte("cluster:\n  clusterName: *doesNotExist\n"), util.DefaultFileWritePermissions)
+	require.NoError(t, err, "failed to save %s", fileName)
+
+	_, _, err = config.LoadRequirementsConfig(dir)
+	assert.Error(t, err, "should have failed for an undefined anchor")
+}
+
+func TestRequirementsDiff(t *testing.T) {
+	original := config.NewRequirementsConfig()
+	original.Cluster.ClusterName = "mycluster"
+
+	overridden := config.NewRequirementsConfig()
+	overridden.Cluster.ClusterName = "othercluster"
+
+	diff, err := reqhelpers.RequirementsDiff(original, overridden)
+	require.NoError(t, err, "failed to diff the requirements")
+	t.Logf("got diff:\n%s", diff)
+	assert.Contains(t, diff, "-  clusterName: mycluster", "removed line")
+	assert.Contains(t, diff, "+  clusterName: othercluster", "added line")
+
+	diff, err = reqhelpers.RequirementsDiff(original, original)
+	require.NoError(t, err, "failed to diff the requirements")
+	assert.Empty(t, diff, "should have no diff for the same requirements")
+}
+
+func TestMergeRequirementsFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-requirements-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(dir)
+
+	writeFile := func(name string, text string) string {
+		fileName := filepath.Join(dir, name)
+		err := ioutil.WriteFile(fileName, []byte(text), 0600)
+		require.NoError(t, err, "failed to write %s", fileName)
+		return fileName
+	}
+	base := writeFile("base.yml", "cluster:\n  clusterName: mycluster\n  zone: europe-west1-b\n")
+	staging := writeFile("staging.yml", "cluster:\n  clusterName: mycluster-staging\ningress:\n  domain: staging.example.com\n")
+
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.Provider = "gke"
+	requirements.Cluster.ProjectID = "myproject"
+
+	for _, fileName := range []string{base, staging} {
+		requirements, err = reqhelpers.MergeRequirementsFile(requirements, fileName)
+		require.NoError(t, err, "failed to merge %s", fileName)
+	}
+	assert.Equal(t, "mycluster-staging", requirements.Cluster.ClusterName, "later files should override earlier ones")
+	assert.Equal(t, "europe-west1-b", requirements.Cluster.Zone, "values only in earlier files should be kept")
+	assert.Equal(t, "myproject", requirements.Cluster.ProjectID, "values not in any file should be kept")
+	assert.Equal(t, "gke", requirements.Cluster.Provider, "values not in any file should be kept")
+	assert.Equal(t, "staging.example.com", requirements.Ingress.Domain, "ingress domain")
+
+	_, err = reqhelpers.MergeRequirementsFile(requirements, writeFile("typo.yml", "cluster:\n  clusterNmae: oops\n"))
+	assert.Error(t, err, "should have failed for an unknown key")
+
+	_, err = reqhelpers.MergeRequirementsFile(requirements, filepath.Join(dir, "missing.yml"))
+	assert.Error(t, err, "should have failed for a missing file")
+}
+
+func TestFindMissingChartVersions(t *testing.T) {
+	versionsDir, err := ioutil.TempDir("", "test-helmboot-versions-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(versionsDir)
+
+	for _, chart := range []string{"stable/nginx-ingress", "jenkins-x/lighthouse"} {
+		fileName := filepath.Join(versionsDir, "charts", chart+".yml")
+		err = os.MkdirAll(filepath.Dir(fileName), util.DefaultWritePermissions)
+		require.NoError(t, err, "failed to create dir for %s", fileName)
+		err = ioutil.WriteFile(fileName, []byte("version: 1.2.3\n"), util.DefaultFileWritePermissions)
+		require.NoError(t, err, "failed to save %s", fileName)
+	}
+
+	requirements := config.NewRequirementsConfig()
+	requirements.Webhook = config.WebhookTypeLighthouse
+	requirements.Ingress.TLS.Enabled = false
+	requirements.Ingress.ExternalDNS = false
+	requirements.Repository = config.RepositoryTypeNone
+	requirements.SecretStorage = config.SecretStorageTypeLocal
+	requirements.Velero.Namespace = ""
+
+	missing, err := reqhelpers.FindMissingChartVersions(requirements, versionsDir)
+	require.NoError(t, err, "failed to find missing chart versions")
+	assert.Empty(t, missing, "should have found all the chart versions")
+
+	requirements.Ingress.TLS.Enabled = true
+	missing, err = reqhelpers.FindMissingChartVersions(requirements, versionsDir)
+	require.NoError(t, err, "failed to find missing chart versions")
+	require.Len(t, missing, 1, "missing charts")
+	assert.Equal(t, "jetstack/cert-manager", missing[0].Chart, "missing chart")
+
+	bom, err := reqhelpers.CreateBillOfMaterials(requirements, versionsDir, "jx-labs/jxl-boot", "0.0.1", &reqhelpers.BootJobOptions{ImageTag: "latest"})
+	require.NoError(t, err, "failed to create the bill of materials")
+	assert.Equal(t, []reqhelpers.BOMChart{
+		{Name: "jx-labs/jxl-boot", Version: "0.0.1", Reason: "the boot Job"},
+		{Name: "stable/nginx-ingress", Version: "1.2.3", Reason: "the ingress controller"},
+		{Name: "jetstack/cert-manager", Reason: "TLS via ingress.tls.enabled"},
+		{Name: "jenkins-x/lighthouse", Version: "1.2.3", Reason: "the lighthouse webhook"},
+	}, bom.Charts, "charts")
+	require.Len(t, bom.Images, 1, "images")
+	assert.Equal(t, "<default image>:latest", bom.Images[0].Image, "image")
+}
+
+func TestGetRequirementsFromGitRefWithTagAndSHA(t *testing.T) {
+	repoDir, err := ioutil.TempDir("", "test-helmboot-repo-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(repoDir)
+
+	git := func(args ...string) string {
+		c := util.Command{Dir: repoDir, Name: "git", Args: append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)}
+		text, err := c.RunWithoutRetry()
+		require.NoError(t, err, "failed to run git %v", args)
+		return strings.TrimSpace(text)
+	}
+	commitRequirements := func(clusterName string) {
+		requirements := config.NewRequirementsConfig()
+		requirements.Cluster.ClusterName = clusterName
+		err := requirements.SaveConfig(filepath.Join(repoDir, config.RequirementsConfigFileName))
+		require.NoError(t, err, "failed to save requirements")
+		git("add", config.RequirementsConfigFileName)
+		git("commit", "-m", clusterName)
+	}
+	git("init")
+	commitRequirements("tagged")
+	git("tag", "v1.0.0")
+	// a branch which looks like an abbreviated commit SHA
+	git("branch", "cafe1234")
+	commitRequirements("latest")
+	sha := git("rev-parse", "HEAD")
+	commitRequirements("newer")
+
+	gitURL := "file://" + repoDir
+	testCases := map[string]string{
+		"v1.0.0":           "tagged",
+		"refs/tags/v1.0.0": "tagged",
+		sha:                "latest",
+		sha[0:8]:           "latest",
+		"cafe1234":         "tagged",
+	}
+	for ref, expected := range testCases {
+		requirements, err := reqhelpers.GetRequirementsFromGitRef(nil, gitURL, ref)
+		require.NoError(t, err, "failed to get requirements from ref %s", ref)
+		assert.Equal(t, expected, requirements.Cluster.ClusterName, "cluster name for ref %s", ref)
+	}
+
+	// the clone in the work directory is reused and reset to each ref in turn
+	workDir, err := ioutil.TempDir("", "test-helmboot-work-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(workDir)
+	testCases[""] = "newer"
+	for ref, expected := range testCases {
+		requirements, err := reqhelpers.GetRequirementsFromGitInWorkDir(nil, gitURL, ref, workDir)
+		require.NoError(t, err, "failed to get requirements from ref %s in the work dir", ref)
+		assert.Equal(t, expected, requirements.Cluster.ClusterName, "cluster name for ref %s in the work dir", ref)
+	}
+}
+
+func TestFindRequirementsAndGitURLIgnoringDevEnvironment(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-dev-repo-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(dir)
+
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.ClusterName = "local"
+	err = requirements.SaveConfig(filepath.Join(dir, config.RequirementsConfigFileName))
+	require.NoError(t, err, "failed to save requirements")
+
+	gitURL := "https://github.com/myorg/env-mycluster-dev.git"
+	for _, args := range [][]string{{"init"}, {"remote", "add", "origin", gitURL}} {
+		c := util.Command{Dir: dir, Name: "git", Args: args}
+		_, err = c.RunWithoutRetry()
+		require.NoError(t, err, "failed to run git %v", args)
+	}
+
+	// a nil factory means the dev Environment is not consulted
+	actual, actualGitURL, err := reqhelpers.FindRequirementsAndGitURL(nil, "", "", gits.NewGitCLI(), dir, "")
+	require.NoError(t, err, "failed to find the requirements in %s", dir)
+	assert.Equal(t, "local", actual.Cluster.ClusterName, "cluster name")
+	assert.Equal(t, gitURL, actualGitURL, "git URL")
+}
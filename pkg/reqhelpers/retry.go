@@ -0,0 +1,77 @@
+package reqhelpers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// maxRetryBackoff the maximum delay between retries of the boot Job
+const maxRetryBackoff = 10 * time.Minute
+
+// JobFailedError indicates the boot Job reached a terminal failed state so that the whole boot can be retried
+type JobFailedError struct {
+	err error
+}
+
+// NewJobFailedError marks the given error as a terminal failure of the boot Job
+func NewJobFailedError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &JobFailedError{err: err}
+}
+
+// Error returns the message of the underlying error
+func (e *JobFailedError) Error() string {
+	return e.err.Error()
+}
+
+// IsJobFailed returns true if the error, or its cause, is a terminal failure of the boot Job
+func IsJobFailed(err error) bool {
+	_, ok := errors.Cause(err).(*JobFailedError)
+	return ok
+}
+
+// RetryDelays returns the delay before each of the given number of retries doubling the backoff each time up to a maximum
+func RetryDelays(retries int, backoff time.Duration) []time.Duration {
+	var answer []time.Duration
+	delay := backoff
+	for i := 0; i < retries; i++ {
+		answer = append(answer, delay)
+		delay *= 2
+		if delay > maxRetryBackoff {
+			delay = maxRetryBackoff
+		}
+	}
+	return answer
+}
+
+// DescribeRetryPlan returns a description of the retries of the boot Job for the given delays
+func DescribeRetryPlan(delays []time.Duration) string {
+	if len(delays) == 0 {
+		return "the boot Job will not be retried if it fails"
+	}
+	var waits []string
+	for _, d := range delays {
+		waits = append(waits, d.String())
+	}
+	return fmt.Sprintf("if the boot Job fails it will be deleted and retried up to %d times after waiting %s", len(delays), strings.Join(waits, ", "))
+}
+
+// AttemptsError returns an error combining the errors of each failed attempt of the boot Job
+func AttemptsError(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	var lines []string
+	for i, err := range errs {
+		lines = append(lines, fmt.Sprintf("attempt %d: %s", i+1, err.Error()))
+	}
+	return errors.Errorf("the boot Job failed after %d attempts:\n%s", len(errs), strings.Join(lines, "\n"))
+}
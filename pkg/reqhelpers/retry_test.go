@@ -0,0 +1,36 @@
+package reqhelpers_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelays(t *testing.T) {
+	assert.Empty(t, reqhelpers.RetryDelays(0, time.Minute), "no retries")
+	assert.Equal(t, []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute}, reqhelpers.RetryDelays(3, 30*time.Second), "delays")
+	assert.Equal(t, []time.Duration{8 * time.Minute, 10 * time.Minute, 10 * time.Minute}, reqhelpers.RetryDelays(3, 8*time.Minute), "capped delays")
+
+	assert.Equal(t, "if the boot Job fails it will be deleted and retried up to 2 times after waiting 30s, 1m0s", reqhelpers.DescribeRetryPlan(reqhelpers.RetryDelays(2, 30*time.Second)), "plan")
+}
+
+func TestIsJobFailed(t *testing.T) {
+	err := reqhelpers.NewJobFailedError(fmt.Errorf("the boot Job has failed: BackoffLimitExceeded"))
+	assert.True(t, reqhelpers.IsJobFailed(err), "job failed error")
+	assert.True(t, reqhelpers.IsJobFailed(errors.Wrap(err, "possible cause: quota")), "wrapped job failed error")
+	assert.False(t, reqhelpers.IsJobFailed(fmt.Errorf("missing option: --git-url")), "other error")
+	assert.NoError(t, reqhelpers.NewJobFailedError(nil))
+}
+
+func TestAttemptsError(t *testing.T) {
+	assert.NoError(t, reqhelpers.AttemptsError(nil))
+
+	err := fmt.Errorf("first")
+	assert.Equal(t, err, reqhelpers.AttemptsError([]error{err}), "single attempt")
+
+	assert.EqualError(t, reqhelpers.AttemptsError([]error{err, fmt.Errorf("second")}), "the boot Job failed after 2 attempts:\nattempt 1: first\nattempt 2: second")
+}
@@ -0,0 +1,79 @@
+package reqhelpers
+
+import (
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/versionstream"
+	"github.com/pkg/errors"
+)
+
+// RequiredChart a chart which must be in the version stream as the requirements use it
+type RequiredChart struct {
+	Chart string
+
+	// Reason the feature of the requirements which needs the chart
+	Reason string
+}
+
+// requiredCharts the charts which are installed when a feature is enabled in the requirements
+var requiredCharts = []struct {
+	RequiredChart
+	Enabled func(r *config.RequirementsConfig) bool
+}{
+	{
+		RequiredChart: RequiredChart{Chart: "stable/nginx-ingress", Reason: "the ingress controller"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return true },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "jetstack/cert-manager", Reason: "TLS via ingress.tls.enabled"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.Ingress.TLS.Enabled },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "bitnami/external-dns", Reason: "external DNS via ingress.externalDNS"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.Ingress.ExternalDNS },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "jenkins-x/lighthouse", Reason: "the lighthouse webhook"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.Webhook == config.WebhookTypeLighthouse },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "jenkins-x/prow", Reason: "the prow webhook"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.Webhook == config.WebhookTypeProw },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "banzaicloud-stable/vault-operator", Reason: "vault secret storage"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.SecretStorage == config.SecretStorageTypeVault },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "jenkins-x/nexus", Reason: "the nexus artifact repository"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.Repository == config.RepositoryTypeNexus },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "jenkins-x/bucketrepo", Reason: "the bucketrepo artifact repository"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.Repository == config.RepositoryTypeBucketRepo },
+	},
+	{
+		RequiredChart: RequiredChart{Chart: "vmware-tanzu/velero", Reason: "velero backups"},
+		Enabled:       func(r *config.RequirementsConfig) bool { return r.Velero.Namespace != "" },
+	},
+}
+
+// FindMissingChartVersions returns the charts used by the requirements which have no version in the given version stream directory
+func FindMissingChartVersions(requirements *config.RequirementsConfig, versionsDir string) ([]RequiredChart, error) {
+	resolver := &versionstream.VersionResolver{
+		VersionsDir: versionsDir,
+	}
+	var answer []RequiredChart
+	for _, rc := range requiredCharts {
+		if !rc.Enabled(requirements) {
+			continue
+		}
+		version, err := resolver.StableVersionNumber(versionstream.KindChart, rc.Chart)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the version of chart %s in the version stream", rc.Chart)
+		}
+		if version == "" {
+			answer = append(answer, rc.RequiredChart)
+		}
+	}
+	return answer, nil
+}
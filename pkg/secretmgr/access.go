@@ -0,0 +1,90 @@
+package secretmgr
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// AccessAllowed the current identity is allowed the access
+	AccessAllowed = "allowed"
+
+	// AccessDenied the current identity is not allowed the access
+	AccessDenied = "denied"
+
+	// AccessUnknown the access could not be checked without storing anything
+	AccessUnknown = "unknown"
+)
+
+// Access whether the current identity can read and write the secrets of a secret manager
+type Access struct {
+	Read  string
+	Write string
+
+	// Details the optional descriptions of how the access was checked or why it was denied
+	Details []string
+}
+
+// AccessChecker is implemented by secret managers which can check whether the current identity can read and write
+// the secrets without storing anything
+type AccessChecker interface {
+	CheckAccess() (Access, error)
+}
+
+// AccessOf returns AccessAllowed if the access is allowed otherwise AccessDenied
+func AccessOf(allowed bool) string {
+	if allowed {
+		return AccessAllowed
+	}
+	return AccessDenied
+}
+
+// CombineAccess returns the combination of the two accesses where the first denied or unknown access wins
+func CombineAccess(a string, b string) string {
+	if a == AccessDenied || b == AccessDenied {
+		return AccessDenied
+	}
+	if a == AccessUnknown || b == AccessUnknown {
+		return AccessUnknown
+	}
+	return AccessAllowed
+}
+
+// CheckAccess checks whether the current identity can read and write the secrets of the secret manager without
+// storing anything. If the secret manager cannot check its access the secrets are read without modifying them
+// and the write access is unknown
+func CheckAccess(sm SecretManager) (Access, error) {
+	checker, ok := sm.(AccessChecker)
+	if ok {
+		return checker.CheckAccess()
+	}
+	access := Access{
+		Read:  AccessAllowed,
+		Write: AccessUnknown,
+	}
+	err := sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		return secretsYaml, nil
+	}, "")
+	if err != nil {
+		access.Read = AccessDenied
+		access.Details = append(access.Details, "failed to read the secrets: "+strings.TrimSpace(err.Error()))
+	}
+	access.Details = append(access.Details, "the write access cannot be checked without storing the secrets")
+	return access, nil
+}
+
+// VerifyAccess returns an error if the read or write access is denied
+func VerifyAccess(access Access, name string) error {
+	var denied []string
+	if access.Read == AccessDenied {
+		denied = append(denied, "read")
+	}
+	if access.Write == AccessDenied {
+		denied = append(denied, "write")
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+	return errors.Errorf("the current identity cannot %s the secrets in %s", strings.Join(denied, " or "), name)
+}
@@ -0,0 +1,29 @@
+package secretmgr_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckAccess(t *testing.T) {
+	sm := &fake.FakeSecretManager{SecretsYAML: secretmgr.DefaultSecretsYaml}
+	access, err := secretmgr.CheckAccess(sm)
+	require.NoError(t, err, "failed to check the access")
+	assert.Equal(t, secretmgr.AccessAllowed, access.Read, "read access")
+	assert.Equal(t, secretmgr.AccessUnknown, access.Write, "write access")
+	assert.Equal(t, secretmgr.DefaultSecretsYaml, sm.SecretsYAML, "should not have modified the secrets")
+	assert.NoError(t, secretmgr.VerifyAccess(access, sm.String()), "unknown access should not fail")
+
+	access.Write = secretmgr.AccessDenied
+	err = secretmgr.VerifyAccess(access, sm.String())
+	require.Error(t, err, "should have failed as write access is denied")
+	assert.Equal(t, "the current identity cannot write the secrets in fake", err.Error(), "error message")
+
+	assert.Equal(t, secretmgr.AccessAllowed, secretmgr.CombineAccess(secretmgr.AccessAllowed, secretmgr.AccessAllowed))
+	assert.Equal(t, secretmgr.AccessUnknown, secretmgr.CombineAccess(secretmgr.AccessAllowed, secretmgr.AccessUnknown))
+	assert.Equal(t, secretmgr.AccessDenied, secretmgr.CombineAccess(secretmgr.AccessUnknown, secretmgr.AccessDenied))
+}
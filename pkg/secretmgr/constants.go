@@ -13,6 +13,15 @@ const (
 	// KindVault for a vault based secret manager
 	KindVault = "vault"
 
+	// KindExec for delegating to an external command to load and store the secrets
+	KindExec = "exec"
+
+	// KindFile for using a file such as one mounted by an external secret operator
+	KindFile = "file"
+
+	// KindNone for skipping secret handling entirely such as for experimental boots
+	KindNone = "none"
+
 	// BootGitURLSecret the name of the Kubernetes Secret used to store the git clone URL
 	/* #nosec */
 	BootGitURLSecret = "jx-boot-git-url"
@@ -28,6 +37,19 @@ const (
 	// LocalSecretKey the key in the local Secret to store the YAML secrets
 	LocalSecretKey = "secrets.yaml"
 
+	// DefaultSecretsRootKey the root key of the secrets inside the secrets YAML
+	DefaultSecretsRootKey = "secrets"
+
+	// EnvironmentsRootKey the root key inside the secrets YAML of the secrets scoped to a named environment
+	// which are stored under environments.<name>.secrets
+	EnvironmentsRootKey = "environments"
+
+	// LabelManagedBy the standard label used to indicate the tool which manages a resource
+	LabelManagedBy = "app.kubernetes.io/managed-by"
+
+	// ManagedByHelmboot the value of the managed by label for resources created by helmboot
+	ManagedByHelmboot = "helmboot"
+
 	// DefaultSecretsYaml the default YAML
 	DefaultSecretsYaml = `secrets:
   adminUser:
@@ -43,5 +65,5 @@ const (
 
 var (
 	// KindValues the kind of secret managers we support
-	KindValues = []string{KindGoogleSecretManager, KindLocal}
+	KindValues = []string{KindGoogleSecretManager, KindLocal, KindExec, KindFile, KindNone}
 )
@@ -0,0 +1,65 @@
+package secretmgr
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/yaml"
+)
+
+// ValidateEnvironmentName validates the name of an environment used to scope secrets is a DNS-1123 label so that it
+// can be used in both a YAML key and a Kubernetes Secret name
+func ValidateEnvironmentName(env string) error {
+	if env == "" {
+		return nil
+	}
+	errs := validation.IsDNS1123Label(env)
+	if len(errs) > 0 {
+		return util.InvalidOptionf("environment", env, "the environment name must be a lower case DNS label: %s", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// EnvironmentSecrets returns the secrets of the environment stored under environments.<name>.secrets in the
+// given secrets YAML values or nil if there are none
+func EnvironmentSecrets(values map[string]interface{}, env string) map[string]interface{} {
+	environments, ok := values[EnvironmentsRootKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	envValues, ok := environments[env].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	secrets, ok := envValues[DefaultSecretsRootKey].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	return secrets
+}
+
+// EnvironmentSecretsYAML returns the secrets YAML of the environment with its secrets under the default secrets
+// root key. If there is no environment the secrets YAML is returned unchanged
+func EnvironmentSecretsYAML(secretsYaml []byte, env string) ([]byte, error) {
+	if env == "" {
+		return secretsYaml, nil
+	}
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal(secretsYaml, &values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the secrets YAML")
+	}
+	secrets := EnvironmentSecrets(values, env)
+	if secrets == nil {
+		return nil, errors.Errorf("no secrets for environment %s under %s.%s.%s", env, EnvironmentsRootKey, env, DefaultSecretsRootKey)
+	}
+	data, err := yaml.Marshal(map[string]interface{}{
+		DefaultSecretsRootKey: secrets,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal the secrets of environment %s", env)
+	}
+	return data, nil
+}
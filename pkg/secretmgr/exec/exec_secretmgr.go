@@ -0,0 +1,132 @@
+package exec
+
+import (
+	"bytes"
+	"fmt"
+	osexec "os/exec"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ReadCommand the sub command passed on the first line of stdin to the external command to read the secrets YAML
+	ReadCommand = "read"
+
+	// WriteCommand the sub command passed on the first line of stdin to the external command to write the secrets YAML
+	WriteCommand = "write"
+)
+
+// ExecSecretManager delegates to an external command to load and store the secrets YAML.
+//
+// The protocol is:
+// * to read the secrets the command is passed the line 'read' on stdin and should write the secrets YAML to stdout
+// * to write the secrets the command is passed the line 'write' on stdin followed by the secrets YAML
+//
+// Only stdout is parsed as the secrets YAML. A non zero exit code fails the read or write and includes stderr in the error
+type ExecSecretManager struct {
+	Name string
+	Args []string
+}
+
+// NewExecSecretManager creates a secret manager which invokes the given command line
+func NewExecSecretManager(commandLine string) (secretmgr.SecretManager, error) {
+	fields := strings.Fields(commandLine)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("no secret command specified for the %s secret manager", secretmgr.KindExec)
+	}
+	return &ExecSecretManager{Name: fields[0], Args: fields[1:]}, nil
+}
+
+// UpsertSecrets upserts the secrets
+func (f *ExecSecretManager) UpsertSecrets(callback secretmgr.SecretCallback, defaultYaml string) error {
+	secretYaml, err := f.getSecretYaml()
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(secretYaml) == "" {
+		secretYaml = defaultYaml
+	}
+
+	updatedYaml, err := callback(secretYaml)
+	if err != nil {
+		return err
+	}
+	if updatedYaml != secretYaml {
+		return f.updateSecretYaml(updatedYaml)
+	}
+	return nil
+}
+
+// Kind returns the kind of the secret manager
+func (f *ExecSecretManager) Kind() string {
+	return secretmgr.KindExec
+}
+
+// String returns the description of the secret manager including the command line
+func (f *ExecSecretManager) String() string {
+	return fmt.Sprintf("%s using command %s", f.Kind(), strings.Join(append([]string{f.Name}, f.Args...), " "))
+}
+
+func (f *ExecSecretManager) getSecretYaml() (string, error) {
+	c := f.command(ReadCommand, "")
+	log.Logger().Debugf("running %s %s to %s the secrets", c.Name, strings.Join(c.Args, " "), ReadCommand)
+
+	text, err := runCommand(c)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read secrets via command %s", f.Name)
+	}
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(text), &values)
+	if err != nil {
+		return "", errors.Wrapf(err, "the command %s returned invalid secrets YAML", f.Name)
+	}
+	return text, nil
+}
+
+func (f *ExecSecretManager) updateSecretYaml(newYaml string) error {
+	c := f.command(WriteCommand, newYaml)
+	log.Logger().Debugf("running %s %s to %s the secrets", c.Name, strings.Join(c.Args, " "), WriteCommand)
+
+	_, err := runCommand(c)
+	if err != nil {
+		return errors.Wrapf(err, "failed to write secrets via command %s", f.Name)
+	}
+	return nil
+}
+
+// command returns the command passing the sub command as the first line of stdin followed by the input
+func (f *ExecSecretManager) command(subCommand string, input string) util.Command {
+	return util.Command{
+		Name: f.Name,
+		Args: append([]string{}, f.Args...),
+		In:   strings.NewReader(subCommand + "\n" + input),
+	}
+}
+
+// runCommand runs the command returning its stdout. Any stderr is logged rather than mixed into the output and is
+// included in the error if the command fails
+func runCommand(c util.Command) (string, error) {
+	cmd := osexec.Command(c.Name, c.Args...)
+	cmd.Stdin = c.In
+	var stdout bytes.Buffer
+	var stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	errText := strings.TrimSpace(stderr.String())
+	if err != nil {
+		if errText != "" {
+			return "", errors.Wrapf(err, "stderr: %s", errText)
+		}
+		return "", err
+	}
+	if errText != "" {
+		log.Logger().Debugf("%s wrote to stderr: %s", c.Name, errText)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,134 @@
+package exec_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/exec"
+	"github.com/jenkins-x-labs/helmboot/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubScript reads the sub command from the first line of stdin and reads or writes the store file
+const stubScript = `#!/bin/sh
+read cmd
+case "$cmd" in
+read) cat "$1" ;;
+write) cat > "$1" ;;
+*) echo "unknown command $cmd" >&2; exit 2 ;;
+esac
+`
+
+const storedYaml = `secrets:
+  hmacToken: TODO
+`
+
+func createScript(t *testing.T, dir string, name string, script string) string {
+	fileName := filepath.Join(dir, name)
+	err := ioutil.WriteFile(fileName, []byte(script), 0700)
+	require.NoError(t, err, "failed to save script %s", fileName)
+	return fileName
+}
+
+func TestExecSecretManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-exec-secrets-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	defer os.RemoveAll(dir)
+
+	storeFile := filepath.Join(dir, "store.yaml")
+	err = ioutil.WriteFile(storeFile, []byte(storedYaml), 0600)
+	require.NoError(t, err, "failed to save file %s", storeFile)
+	script := createScript(t, dir, "secrets.sh", stubScript)
+
+	sm, err := exec.NewExecSecretManager(script + " " + storeFile)
+	require.NoError(t, err, "failed to create the exec secret manager")
+	assert.Equal(t, secretmgr.KindExec, sm.Kind(), "kind")
+	assert.Equal(t, "exec using command "+script+" "+storeFile, sm.String(), "description")
+
+	actualYaml := ""
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		actualYaml = secretsYaml
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to read the secrets")
+	testhelpers.AssertYamlEqual(t, storedYaml, actualYaml, "should have read the secrets")
+
+	modifiedYaml := storedYaml + "  extra: value\n"
+	err = sm.UpsertSecrets(func(string) (string, error) {
+		return modifiedYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to write the secrets")
+
+	data, err := ioutil.ReadFile(storeFile)
+	require.NoError(t, err, "failed to load file %s", storeFile)
+	assert.Equal(t, modifiedYaml, string(data), "should have piped the secrets YAML after the sub command")
+
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		actualYaml = secretsYaml
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to read the secrets")
+	testhelpers.AssertYamlEqual(t, modifiedYaml, actualYaml, "should have read the updated secrets")
+}
+
+func TestExecSecretManagerFailures(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-exec-secrets-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	defer os.RemoveAll(dir)
+
+	testCases := []struct {
+		name     string
+		script   string
+		expected string
+	}{
+		{
+			name:     "failing",
+			script:   "#!/bin/sh\necho 'access denied' >&2\nexit 3\n",
+			expected: "access denied",
+		},
+		{
+			name:     "malformed",
+			script:   "#!/bin/sh\nread cmd\necho 'secrets: [not valid'\n",
+			expected: "returned invalid secrets YAML",
+		},
+	}
+	for _, tc := range testCases {
+		script := createScript(t, dir, tc.name+".sh", tc.script)
+		sm, err := exec.NewExecSecretManager(script)
+		require.NoError(t, err, "failed to create the exec secret manager for %s", tc.name)
+
+		called := false
+		err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+			called = true
+			return secretsYaml, nil
+		}, secretmgr.DefaultSecretsYaml)
+		require.Error(t, err, "should have failed for the %s command", tc.name)
+		assert.Contains(t, err.Error(), tc.expected, "error message for the %s command", tc.name)
+		assert.False(t, called, "should not have invoked the callback for the %s command", tc.name)
+	}
+
+	_, err = exec.NewExecSecretManager("  ")
+	assert.Error(t, err, "should have failed without a command")
+}
+
+func TestExecSecretManagerIgnoresStderr(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-exec-secrets-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	defer os.RemoveAll(dir)
+
+	script := createScript(t, dir, "noisy.sh", "#!/bin/sh\nread cmd\necho 'warning: using cached credentials' >&2\nprintf 'secrets:\\n  hmacToken: TODO\\n'\n")
+	sm, err := exec.NewExecSecretManager(script)
+	require.NoError(t, err, "failed to create the exec secret manager")
+
+	actualYaml := ""
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		actualYaml = secretsYaml
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "should not have parsed stderr as the secrets YAML")
+	testhelpers.AssertYamlEqual(t, storedYaml, actualYaml, "should have read the secrets from stdout")
+}
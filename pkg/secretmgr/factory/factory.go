@@ -7,6 +7,7 @@ import (
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/fake"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/gsm"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/local"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/none"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/proxy"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/vault"
 	"github.com/jenkins-x/jx/pkg/config"
@@ -34,6 +35,8 @@ func NewSecretManager(kind string, f jxfactory.Factory, requirements *config.Req
 		return local.NewLocalSecretManager(f, requirements.Cluster.Namespace)
 	case secretmgr.KindFake:
 		return fake.NewFakeSecretManager(), nil
+	case secretmgr.KindNone:
+		return none.NewNoneSecretManager(), nil
 	case secretmgr.KindVault:
 		return vault.NewVaultSecretManagerFromJXFactory(f)
 	default:
@@ -1,7 +1,9 @@
 package factory_test
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/jenkins-x-labs/helmboot/pkg/fakes/fakejxfactory"
@@ -10,10 +12,12 @@ import (
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/fake"
 	vaultfake "github.com/jenkins-x-labs/helmboot/pkg/secretmgr/vault/client/fake"
 	"github.com/jenkins-x-labs/helmboot/pkg/testhelpers"
+	"github.com/jenkins-x/jx/pkg/cloud"
 	"github.com/jenkins-x/jx/pkg/config"
 	"github.com/jenkins-x/jx/pkg/jxfactory"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -68,6 +72,70 @@ func TestLocalSecretManager(t *testing.T) {
 	testhelpers.AssertYamlEqual(t, modifiedYaml, secretYaml, "should have got the YAML from the vault secret manager")
 }
 
+func TestSecretStorageMismatch(t *testing.T) {
+	ns := "jx"
+	f := fakejxfactory.NewFakeFactoryWithObjects(nil, nil, ns)
+
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.Provider = cloud.GKE
+	requirements.SecretStorage = config.SecretStorageTypeLocal
+
+	r := &factory.KindResolver{
+		Factory:      f,
+		GitURL:       "https://github.com/myorg/environment-mycluster-dev.git",
+		Requirements: requirements,
+		BatchMode:    true,
+	}
+	_, err := r.CreateSecretManager("")
+	require.Error(t, err, "should have failed as there is no local Secret on GKE")
+	assert.Contains(t, err.Error(), "the requirements specify local secret storage but the cluster provider is gke", "error message")
+
+	kubeClient, _, err := f.CreateKubeClient()
+	require.NoError(t, err, "failed to create KubeClient")
+	_, err = kubeClient.CoreV1().Secrets(ns).Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretmgr.LocalSecret,
+			Namespace: ns,
+		},
+	})
+	require.NoError(t, err, "failed to create Secret %s", secretmgr.LocalSecret)
+
+	r.Kind = ""
+	sm, err := r.CreateSecretManager("")
+	require.NoError(t, err, "should use the existing local Secret")
+	assert.Equal(t, secretmgr.KindLocal, sm.Kind(), "secret manager kind")
+}
+
+func TestNoClusterSecretManager(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "test-no-cluster-")
+	require.NoError(t, err, "failed to create temp dir")
+	defer os.RemoveAll(tmpDir)
+
+	r := &factory.KindResolver{
+		SecretPath:   filepath.Join(tmpDir, "secrets.yaml"),
+		Requirements: config.NewRequirementsConfig(),
+		NoCluster:    true,
+	}
+	sm, err := r.CreateSecretManager("")
+	require.NoError(t, err, "should create the secret manager without a cluster")
+	assert.Equal(t, secretmgr.KindFile, sm.Kind(), "secret manager kind")
+
+	err = r.SaveBootRunGitCloneSecret("")
+	require.NoError(t, err, "should not save the boot git URL Secret without a cluster")
+
+	r.Kind = secretmgr.KindLocal
+	_, err = r.CreateSecretManager("")
+	require.Error(t, err, "should have failed to use the local secret manager without a cluster")
+	assert.Contains(t, err.Error(), "requires cluster access", "error message")
+
+	r = &factory.KindResolver{
+		Requirements: config.NewRequirementsConfig(),
+		NoCluster:    true,
+	}
+	_, err = r.CreateSecretManager("")
+	require.Error(t, err, "should have failed as there is no kind")
+}
+
 func AssertSecretsManager(t *testing.T, kind string, f jxfactory.Factory) secretmgr.SecretManager {
 	requirements := config.NewRequirementsConfig()
 	sm, err := factory.NewSecretManager(kind, f, requirements)
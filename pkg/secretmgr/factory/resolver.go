@@ -4,14 +4,27 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
 	"github.com/jenkins-x-labs/helmboot/pkg/reqhelpers"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/exec"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/file"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/gsm"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/kms"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/local"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/proxy"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/scoped"
 	v1 "github.com/jenkins-x/jx/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/pkg/cloud"
 	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/jenkins-x/jx/pkg/gits"
 	"github.com/jenkins-x/jx/pkg/jxfactory"
 	"github.com/jenkins-x/jx/pkg/kube"
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
 	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,13 +37,78 @@ type KindResolver struct {
 	Dir     string
 	GitURL  string
 
+	// GitRef the optional ref of the boot git repository to load the requirements from
+	GitRef string
+
+	// Gitter the gitter used to clone the boot git repository
+	Gitter gits.Gitter
+
+	// Command the external command used by the exec secret manager
+	Command string
+
+	// SecretPath the path of the file used by the file secret manager
+	SecretPath string
+
+	// WorkDir the optional directory to keep git clones in so they can be reused
+	WorkDir string
+
+	// JXNamespace the optional namespace to find the dev Environment in if it differs from the current namespace.
+	// The Secrets are still found in the current namespace
+	JXNamespace string
+
+	// AsUser the optional user to impersonate for all Kubernetes API calls like 'kubectl --as'
+	AsUser string
+
+	// AsGroups the optional groups to impersonate for all Kubernetes API calls like 'kubectl --as-group'
+	AsGroups []string
+
+	// Environment the optional name of the environment to scope the secrets to. The secrets of an environment are
+	// stored under environments.<name>.secrets in the secrets YAML of the secret manager
+	Environment string
+
+	// IgnoreDevEnvironment if enabled the requirements and git URL are never loaded from the dev Environment
+	IgnoreDevEnvironment bool
+
+	// KMS if enabled the secrets YAML in the local Secret is encrypted with the KMS key configured for vault in the
+	// requirements unless KMSKey is specified
+	KMS bool
+
+	// KMSKey the optional KMS key used to encrypt the secrets YAML in the local Secret
+	KMSKey string
+
+	// NoCluster if enabled the secret manager is created from the explicit kind and the requirements in Dir
+	// without accessing the cluster
+	NoCluster bool
+
+	// BatchMode if enabled a mismatch between the provider and the secret storage fails rather than prompting
+	BatchMode bool
+
+	// IOFileHandles the optional terminal handles used to prompt
+	IOFileHandles *util.IOFileHandles
+
 	// outputs which can be useful
 	DevEnvironment *v1.Environment
 	Requirements   *config.RequirementsConfig
 }
 
 // CreateSecretManager detects from the current cluster which kind of SecretManager to use and then creates it
+// scoping the secrets to the environment if one is specified
 func (r *KindResolver) CreateSecretManager(secretsYAML string) (secretmgr.SecretManager, error) {
+	sm, err := r.createSecretManager(secretsYAML)
+	if err != nil {
+		return nil, err
+	}
+	if r.Environment != "" {
+		common.Tracef(common.TraceDecision, "scoping the secrets to environment %s", r.Environment)
+	}
+	return scoped.NewScopedSecretManager(sm, r.Environment)
+}
+
+func (r *KindResolver) createSecretManager(secretsYAML string) (secretmgr.SecretManager, error) {
+	if r.NoCluster {
+		return r.createSecretManagerWithoutCluster()
+	}
+
 	// lets try find the requirements from the cluster or locally
 	requirements, ns, err := r.resolveRequirements(secretsYAML)
 	if err != nil {
@@ -41,6 +119,9 @@ func (r *KindResolver) CreateSecretManager(secretsYAML string) (secretmgr.Secret
 	if requirements == nil {
 		return nil, fmt.Errorf("failed to resolve the jx-requirements.yml from the file system or the 'dev' Environment in namespace %s", ns)
 	}
+	if requirements.Cluster.Provider == "" {
+		r.detectProvider(requirements)
+	}
 	if r.Kind == "" {
 		var err error
 		r.Kind, err = r.resolveKind(requirements)
@@ -52,8 +133,87 @@ func (r *KindResolver) CreateSecretManager(secretsYAML string) (secretmgr.Secret
 		if r.Kind == "" {
 			r.Kind = secretmgr.KindLocal
 		}
+		common.Tracef(common.TraceDecision, "resolved the secret manager kind %s for secret storage %q on provider %q", r.Kind, requirements.SecretStorage, requirements.Cluster.Provider)
+	} else {
+		common.Tracef(common.TraceDecision, "using the secret manager kind %s specified via --kind", r.Kind)
+	}
+	return r.newSecretManager(requirements)
+}
+
+// createSecretManagerWithoutCluster creates the secret manager of the explicit kind using the requirements in the
+// directory without creating any Kubernetes or Jenkins X clients
+func (r *KindResolver) createSecretManagerWithoutCluster() (secretmgr.SecretManager, error) {
+	if r.Kind == "" {
+		if r.Command != "" {
+			r.Kind = secretmgr.KindExec
+		} else if r.SecretPath != "" {
+			r.Kind = secretmgr.KindFile
+		} else {
+			return nil, errors.Wrap(util.MissingOption("kind"), "the secret manager kind cannot be detected without cluster access")
+		}
+	}
+	if r.Requirements == nil {
+		requirements, _, err := config.LoadRequirementsConfig(r.Dir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load the requirements YAML file from %s", r.Dir)
+		}
+		r.Requirements = requirements
+	}
+	switch r.Kind {
+	case secretmgr.KindLocal, secretmgr.KindVault:
+		return nil, util.InvalidOptionf("kind", r.Kind, "the %s secret manager requires cluster access so cannot be used with --no-cluster", r.Kind)
+	case secretmgr.KindGoogleSecretManager:
+		// without a cluster there is no local Secret to keep in sync so lets use Google Secret Manager directly
+		return gsm.NewGoogleSecretManager(r.Requirements)
+	default:
+		return r.newSecretManager(r.Requirements)
 	}
-	return NewSecretManager(r.Kind, r.GetFactory(), requirements)
+}
+
+// newSecretManager creates the secret manager for the resolved kind using any resolver specific configuration
+func (r *KindResolver) newSecretManager(requirements *config.RequirementsConfig) (secretmgr.SecretManager, error) {
+	switch r.Kind {
+	case secretmgr.KindExec:
+		return exec.NewExecSecretManager(r.Command)
+	case secretmgr.KindFile:
+		return file.NewFileSecretManager(r.SecretPath)
+	default:
+		sm, err := NewSecretManager(r.Kind, r.GetFactory(), requirements)
+		if err != nil {
+			return nil, err
+		}
+		return r.encryptWithKMS(sm, requirements)
+	}
+}
+
+// encryptWithKMS wraps the local Secret of the secret manager so that it is encrypted with the KMS key if enabled
+func (r *KindResolver) encryptWithKMS(sm secretmgr.SecretManager, requirements *config.RequirementsConfig) (secretmgr.SecretManager, error) {
+	if !r.KMS && r.KMSKey == "" {
+		return sm, nil
+	}
+	var key kms.Key
+	var err error
+	if r.KMSKey != "" {
+		key, err = kms.ParseKey(r.KMSKey, requirements.Cluster.Region)
+	} else {
+		key, err = kms.KeyFromRequirements(requirements)
+	}
+	if err != nil {
+		return nil, err
+	}
+	common.Tracef(common.TraceDecision, "encrypting the local Secret with the %s", key.String())
+
+	switch s := sm.(type) {
+	case *local.LocalSecretManager:
+		return kms.NewKMSSecretManager(s, key, nil), nil
+	case *proxy.ProxySecretManager:
+		// lets encrypt the local copy of the secrets
+		if _, ok := s.Second.(*local.LocalSecretManager); ok {
+			s.Second = kms.NewKMSSecretManager(s.Second, key, nil)
+			return s, nil
+		}
+	}
+	return nil, util.InvalidOptionf("kms-key", r.KMSKey, "KMS encryption is only supported for the local Secret but the secret manager is %s", sm.String())
 }
 
 // GetFactory lazy creates the factory if required
@@ -61,15 +221,35 @@ func (r *KindResolver) GetFactory() jxfactory.Factory {
 	if r.Factory == nil {
 		r.Factory = jxfactory.NewFactory()
 	}
-	return r.Factory
+	return clienthelpers.WithTracing(clienthelpers.WithJXNamespace(clienthelpers.WithImpersonation(r.Factory, r.AsUser, r.AsGroups), r.JXNamespace))
+}
+
+// AddImpersonationFlags adds the CLI arguments for impersonating a user and groups for all Kubernetes API calls
+func (r *KindResolver) AddImpersonationFlags(cmd *cobra.Command) {
+	clienthelpers.AddImpersonationFlags(cmd, &r.AsUser, &r.AsGroups)
 }
 
 // VerifySecrets verifies that the secrets are valid
 func (r *KindResolver) VerifySecrets() error {
+	secretsYAML, err := r.LoadSecretsYAML()
+	if err != nil {
+		return err
+	}
+	if secretsYAML == "" {
+		return errors.Errorf("empty secrets YAML")
+	}
+	return secretmgr.VerifyBootSecrets(secretsYAML)
+}
+
+// LoadSecretsYAML loads the current secrets YAML from the secret manager returning a blank string if there are none
+func (r *KindResolver) LoadSecretsYAML() (string, error) {
 	secretsYAML := ""
 	sm, err := r.CreateSecretManager("")
 	if err != nil {
-		return err
+		return "", err
+	}
+	if sm.Kind() == secretmgr.KindNone {
+		return "", errors.Errorf("no secrets are available as the secret manager kind is %s. Please specify a different secret manager kind", secretmgr.KindNone)
 	}
 
 	cb := func(currentYAML string) (string, error) {
@@ -78,21 +258,52 @@ func (r *KindResolver) VerifySecrets() error {
 	}
 	err = sm.UpsertSecrets(cb, secretmgr.DefaultSecretsYaml)
 	if err != nil {
-		return errors.Wrapf(err, "failed to load Secrets YAML from secret manager %s", sm.String())
+		return "", errors.Wrapf(err, "failed to load Secrets YAML from secret manager %s", sm.String())
 	}
+	return strings.TrimSpace(secretsYAML), nil
+}
 
-	secretsYAML = strings.TrimSpace(secretsYAML)
-	if secretsYAML == "" {
-		return errors.Errorf("empty secrets YAML")
+// detectProvider populates the provider of the requirements from the nodes of the cluster so that
+// we can choose the right kind of secret manager when the requirements are incomplete
+func (r *KindResolver) detectProvider(requirements *config.RequirementsConfig) {
+	kubeClient, _, err := r.GetFactory().CreateKubeClient()
+	if err != nil {
+		log.Logger().Warnf("failed to create Kubernetes client to detect the cloud provider: %s", err.Error())
+		return
+	}
+	provider, err := clienthelpers.DetectProvider(kubeClient)
+	if err != nil {
+		log.Logger().Warnf("failed to detect the cloud provider: %s", err.Error())
+		return
+	}
+	if provider != "" {
+		log.Logger().Infof("detected the cloud provider %s as it is not specified in the requirements", util.ColorInfo(provider))
+		requirements.Cluster.Provider = provider
 	}
-	return secretmgr.VerifyBootSecrets(secretsYAML)
 }
 
 func (r *KindResolver) resolveKind(requirements *config.RequirementsConfig) (string, error) {
+	if r.Command != "" {
+		common.Tracef(common.TraceDecision, "using the %s secret manager as a secret command is specified", secretmgr.KindExec)
+		return secretmgr.KindExec, nil
+	}
+	if r.SecretPath != "" {
+		common.Tracef(common.TraceDecision, "using the %s secret manager as a secret path is specified", secretmgr.KindFile)
+		return secretmgr.KindFile, nil
+	}
 	switch requirements.SecretStorage {
 	case config.SecretStorageTypeVault:
 		return secretmgr.KindVault, nil
 
+	case secretmgr.KindExec:
+		return "", fmt.Errorf("the %s secret storage requires a secret command to be specified", secretmgr.KindExec)
+
+	case secretmgr.KindFile:
+		return "", fmt.Errorf("the %s secret storage requires a secret path to be specified", secretmgr.KindFile)
+
+	case secretmgr.KindNone:
+		return secretmgr.KindNone, nil
+
 	case config.SecretStorageTypeGSM:
 		if requirements.Cluster.Provider != cloud.GKE {
 			return "", fmt.Errorf("google secret manager (GSM) secret store is only supported on the GKE provider")
@@ -111,10 +322,41 @@ func (r *KindResolver) resolveKind(requirements *config.RequirementsConfig) (str
 			if !apierrors.IsNotFound(err) {
 				return "", errors.Wrapf(err, "failed to get Secret %s in namespace %s", name, ns)
 			}
+			if requirements.SecretStorage == config.SecretStorageTypeLocal {
+				common.Tracef(common.TraceDecision, "the requirements specify local secret storage on GKE but there is no Secret %s in namespace %s", name, ns)
+				return r.resolveSecretStorageMismatch(requirements, ns)
+			}
 			// no secret so lets assume gcloud
+			common.Tracef(common.TraceDecision, "defaulting to Google Secret Manager on GKE as there is no Secret %s in namespace %s", name, ns)
 			return secretmgr.KindGoogleSecretManager, nil
 		}
+		common.Tracef(common.TraceDecision, "using the local secret manager on GKE as the Secret %s exists in namespace %s", name, ns)
+	}
+	return secretmgr.KindLocal, nil
+}
+
+// resolveSecretStorageMismatch asks which secret manager to use when the requirements specify local secret storage
+// on GKE but there is no local Secret. In batch mode it fails rather than guessing
+func (r *KindResolver) resolveSecretStorageMismatch(requirements *config.RequirementsConfig, ns string) (string, error) {
+	message := fmt.Sprintf("the requirements specify %s secret storage but the cluster provider is %s and there is no Secret %s in namespace %s",
+		requirements.SecretStorage, requirements.Cluster.Provider, secretmgr.LocalSecret, ns)
+	if r.BatchMode {
+		return "", errors.Errorf("%s. Please either create the Secret via '%s secrets edit --kind %s', use Google Secret Manager via '--kind %s' or change the secretStorage in the jx-requirements.yml",
+			message, common.BinaryName, secretmgr.KindLocal, secretmgr.KindGoogleSecretManager)
+	}
+	log.Logger().Warnf("%s", message)
+
+	createLocal := fmt.Sprintf("create the local Secret %s", secretmgr.LocalSecret)
+	useGSM := "switch to Google Secret Manager"
+	handles := common.GetIOFileHandles(r.IOFileHandles)
+	answer, err := util.PickNameWithDefault([]string{createLocal, useGSM}, "which secret manager do you want to use:", createLocal, message, handles)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to choose the secret manager")
 	}
+	if answer == useGSM {
+		return secretmgr.KindGoogleSecretManager, nil
+	}
+	log.Logger().Infof("the Secret %s will be created in namespace %s when the secrets are saved", util.ColorInfo(secretmgr.LocalSecret), ns)
 	return secretmgr.KindLocal, nil
 }
 
@@ -131,9 +373,14 @@ func (r *KindResolver) resolveRequirements(secretsYAML string) (*config.Requirem
 		}
 	}
 
-	dev, err := kube.GetDevEnvironment(jxClient, ns)
-	if err != nil && !apierrors.IsNotFound(err) {
-		return nil, ns, errors.Wrap(err, "failed to find the 'dev' Environment resource")
+	var dev *v1.Environment
+	if r.IgnoreDevEnvironment {
+		common.Tracef(common.TraceDecision, "ignoring the dev Environment in namespace %s", ns)
+	} else {
+		dev, err = kube.GetDevEnvironment(jxClient, ns)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return nil, ns, errors.Wrap(err, "failed to find the 'dev' Environment resource")
+		}
 	}
 	r.DevEnvironment = dev
 	if r.Requirements != nil {
@@ -158,7 +405,7 @@ func (r *KindResolver) resolveRequirements(secretsYAML string) (*config.Requirem
 				return nil, "", errors.Wrap(err, "failed to enrich git URL with user and token from the secrets YAML")
 			}
 		}
-		requirements, err := reqhelpers.GetRequirementsFromGit(r.GitURL)
+		requirements, err := reqhelpers.GetRequirementsFromGitInWorkDir(r.Gitter, r.GitURL, r.GitRef, r.WorkDir)
 		return requirements, ns, err
 	}
 
@@ -172,6 +419,10 @@ func (r *KindResolver) resolveRequirements(secretsYAML string) (*config.Requirem
 // SaveBootRunGitCloneSecret saves the git URL used to clone the git repository with the necessary user and token
 // so that we can clone private repositories
 func (r *KindResolver) SaveBootRunGitCloneSecret(secretsYAML string) error {
+	if r.NoCluster {
+		log.Logger().Debugf("not saving the boot git URL Secret as there is no cluster access")
+		return nil
+	}
 	if r.GitURL == "" {
 		return fmt.Errorf("no development environment git URL detected")
 	}
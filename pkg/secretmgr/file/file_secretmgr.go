@@ -0,0 +1,136 @@
+package file
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// FileSecretManager uses a file such as one mounted into the pod by an external secret operator
+type FileSecretManager struct {
+	Path string
+}
+
+// NewFileSecretManager uses the file at the given path to manage secrets
+func NewFileSecretManager(path string) (secretmgr.SecretManager, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no secret path specified for the %s secret manager", secretmgr.KindFile)
+	}
+	return &FileSecretManager{Path: path}, nil
+}
+
+// UpsertSecrets upserts the secrets
+func (f *FileSecretManager) UpsertSecrets(callback secretmgr.SecretCallback, defaultYaml string) error {
+	secretYaml, err := f.getSecretYaml()
+	if err != nil {
+		return err
+	}
+	if secretYaml == "" {
+		secretYaml = defaultYaml
+	}
+
+	updatedYaml, err := callback(secretYaml)
+	if err != nil {
+		return err
+	}
+	if updatedYaml != secretYaml {
+		return f.updateSecretYaml(updatedYaml)
+	}
+	return nil
+}
+
+// CheckAccess checks whether the file can be read and written by opening it without modifying it
+func (f *FileSecretManager) CheckAccess() (secretmgr.Access, error) {
+	access := secretmgr.Access{
+		Read:  secretmgr.AccessAllowed,
+		Write: secretmgr.AccessAllowed,
+	}
+	exists, err := util.FileExists(f.Path)
+	if err != nil {
+		return access, errors.Wrapf(err, "failed to check if secret file %s exists", f.Path)
+	}
+	if !exists {
+		access.Write = secretmgr.AccessUnknown
+		access.Details = append(access.Details, fmt.Sprintf("the secret file %s does not exist yet so can only be checked by creating it", f.Path))
+		return access, nil
+	}
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		access.Read = secretmgr.AccessDenied
+		access.Details = append(access.Details, err.Error())
+	} else if secretmgr.IsSopsEncrypted(data) {
+		access.Write = secretmgr.AccessDenied
+		access.Details = append(access.Details, fmt.Sprintf("the secret file %s is encrypted with sops so can only be modified via sops", f.Path))
+		return access, nil
+	}
+	w, err := os.OpenFile(f.Path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		access.Write = secretmgr.AccessDenied
+		access.Details = append(access.Details, err.Error())
+	} else {
+		w.Close()
+	}
+	return access, nil
+}
+
+func (f *FileSecretManager) Kind() string {
+	return secretmgr.KindFile
+}
+
+func (f *FileSecretManager) String() string {
+	return fmt.Sprintf("%s at %s", f.Kind(), f.Path)
+}
+
+// getSecretYaml loads the secrets YAML from the file which may either be a secrets YAML document
+// or lines of the form "foo.bar: value" and may be encrypted with sops
+func (f *FileSecretManager) getSecretYaml() (string, error) {
+	exists, err := util.FileExists(f.Path)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to check if secret file %s exists", f.Path)
+	}
+	if !exists {
+		return "", nil
+	}
+	data, err := secretmgr.ReadSecretFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	secretsYAML, err := secretmgr.ExpandSecretsYAML(data, f.Path)
+	if err != nil {
+		return "", err
+	}
+	if secretsYAML != nil {
+		return string(secretsYAML), nil
+	}
+	secretData := secretmgr.ParseSecretFile(data, f.Path)
+	if len(secretData) == 0 {
+		return "", nil
+	}
+	data, err = secretmgr.SecretDataToYAML(secretData)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to convert secret file %s to YAML", f.Path)
+	}
+	return string(data), nil
+}
+
+func (f *FileSecretManager) updateSecretYaml(newYaml string) error {
+	data, err := ioutil.ReadFile(f.Path)
+	if err == nil && secretmgr.IsSopsEncrypted(data) {
+		return errors.Errorf("cannot save the modified secrets as the secret file %s is encrypted with sops. Please modify it via sops instead", f.Path)
+	}
+	dir := filepath.Dir(f.Path)
+	err = os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create parent directory %s", dir)
+	}
+	err = ioutil.WriteFile(f.Path, []byte(newYaml), util.DefaultFileWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save secret file %s", f.Path)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package file_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/file"
+	"github.com/jenkins-x-labs/helmboot/pkg/testhelpers"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	flatSecrets = `# a comment
+adminUser.username: admin
+adminUser.password: dummypwd
+hmacToken: TODO
+`
+
+	expectedYaml = `secrets:
+  adminUser:
+    username: admin
+    password: dummypwd
+  hmacToken: TODO
+`
+)
+
+func TestFileSecretManager(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-helmboot-file-secrets-")
+	require.NoError(t, err, "failed to create a temporary dir")
+	defer os.RemoveAll(dir)
+
+	fileName := filepath.Join(dir, "secrets.txt")
+	err = ioutil.WriteFile(fileName, []byte(flatSecrets), util.DefaultFileWritePermissions)
+	require.NoError(t, err, "failed to save file %s", fileName)
+
+	sm, err := file.NewFileSecretManager(fileName)
+	require.NoError(t, err, "failed to create the file secret manager")
+
+	actualYaml := ""
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		actualYaml = secretsYaml
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to load the secrets")
+	testhelpers.AssertYamlEqual(t, expectedYaml, actualYaml, "should have converted the flat secrets file")
+
+	// now lets modify the secrets and check they are saved as YAML
+	modifiedYaml := expectedYaml + "  extra: value\n"
+	err = sm.UpsertSecrets(func(string) (string, error) {
+		return modifiedYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to save the secrets")
+
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		actualYaml = secretsYaml
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to reload the secrets")
+	testhelpers.AssertYamlEqual(t, modifiedYaml, actualYaml, "should have loaded the saved secrets YAML")
+}
@@ -0,0 +1,83 @@
+package secretmgr
+
+import (
+	"crypto/rand"
+	"math/big"
+	"sort"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// alphanumericCharset the characters used for generated passwords
+	alphanumericCharset = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+	// hexCharset the characters used for generated tokens
+	hexCharset = "0123456789abcdef"
+)
+
+// GeneratableSecret a secret which can be generated if it is missing
+type GeneratableSecret struct {
+	// Path the dot separated path of the secret inside the secrets
+	Path string
+
+	// Length the number of characters to generate
+	Length int
+
+	// Charset the characters to generate the value from
+	Charset string
+}
+
+// GeneratableSecrets the secrets which can be generated rather than entered by the user
+var GeneratableSecrets = []GeneratableSecret{
+	{Path: "adminUser.password", Length: 20, Charset: alphanumericCharset},
+	{Path: "hmacToken", Length: 40, Charset: hexCharset},
+}
+
+// GenerateMissingSecrets generates random values for any of the GeneratableSecrets which are missing or blank in the
+// given secrets map leaving any existing values intact. Returns the sorted paths of the generated secrets
+func GenerateMissingSecrets(secrets map[string]interface{}) ([]string, error) {
+	return generateSecrets(secrets, true)
+}
+
+// RegenerateSecrets generates fresh random values for all of the GeneratableSecrets in the given secrets map
+// replacing any existing values. Any other secrets are left untouched. Returns the sorted paths of the regenerated
+// secrets
+func RegenerateSecrets(secrets map[string]interface{}) ([]string, error) {
+	return generateSecrets(secrets, false)
+}
+
+func generateSecrets(secrets map[string]interface{}, onlyMissing bool) ([]string, error) {
+	var answer []string
+	for _, s := range GeneratableSecrets {
+		if onlyMissing {
+			value := util.GetMapValueViaPath(secrets, s.Path)
+			if value != nil && value != "" {
+				continue
+			}
+		}
+		generated, err := RandomString(s.Length, s.Charset)
+		if err != nil {
+			return answer, errors.Wrapf(err, "failed to generate secret %s", s.Path)
+		}
+		util.SetMapValueViaPath(secrets, s.Path, generated)
+		answer = append(answer, s.Path)
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+// RandomString generates a cryptographically random string of the given length from the characters of the charset
+func RandomString(length int, charset string) (string, error) {
+	max := big.NewInt(int64(len(charset)))
+	buf := make([]byte, length)
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to read random number")
+		}
+		buf[i] = charset[n.Int64()]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,67 @@
+package secretmgr_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateMissingSecrets(t *testing.T) {
+	secrets := map[string]interface{}{
+		"adminUser": map[string]interface{}{
+			"username": "admin",
+			"password": "",
+		},
+		"hmacToken": "existing",
+	}
+	generated, err := secretmgr.GenerateMissingSecrets(secrets)
+	require.NoError(t, err, "failed to generate secrets")
+	assert.Equal(t, []string{"adminUser.password"}, generated, "generated secrets")
+
+	adminUser := secrets["adminUser"].(map[string]interface{})
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9]{20}$`), adminUser["password"], "generated password")
+	assert.Equal(t, "admin", adminUser["username"], "should have kept the username")
+	assert.Equal(t, "existing", secrets["hmacToken"], "should have kept the existing token")
+
+	secrets = map[string]interface{}{}
+	generated, err = secretmgr.GenerateMissingSecrets(secrets)
+	require.NoError(t, err, "failed to generate secrets")
+	assert.Equal(t, []string{"adminUser.password", "hmacToken"}, generated, "generated secrets")
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{40}$`), secrets["hmacToken"], "generated token")
+}
+
+func TestRegenerateSecrets(t *testing.T) {
+	secrets := map[string]interface{}{
+		"adminUser": map[string]interface{}{
+			"username": "admin",
+			"password": "oldpassword",
+		},
+		"hmacToken": "oldtoken",
+		"pipelineUser": map[string]interface{}{
+			"token": "usertoken",
+		},
+	}
+	regenerated, err := secretmgr.RegenerateSecrets(secrets)
+	require.NoError(t, err, "failed to regenerate secrets")
+	assert.Equal(t, []string{"adminUser.password", "hmacToken"}, regenerated, "regenerated secrets")
+
+	adminUser := secrets["adminUser"].(map[string]interface{})
+	assert.Regexp(t, regexp.MustCompile(`^[A-Za-z0-9]{20}$`), adminUser["password"], "regenerated password")
+	assert.NotEqual(t, "oldpassword", adminUser["password"], "should have replaced the password")
+	assert.Equal(t, "admin", adminUser["username"], "should have kept the username")
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{40}$`), secrets["hmacToken"], "regenerated token")
+	assert.Equal(t, map[string]interface{}{"token": "usertoken"}, secrets["pipelineUser"], "should have kept the user supplied token")
+}
+
+func TestRandomString(t *testing.T) {
+	a, err := secretmgr.RandomString(32, "ab")
+	require.NoError(t, err, "failed to generate random string")
+	assert.Regexp(t, regexp.MustCompile(`^[ab]{32}$`), a)
+
+	b, err := secretmgr.RandomString(32, "ab")
+	require.NoError(t, err, "failed to generate random string")
+	assert.NotEqual(t, a, b, "random strings should differ")
+}
@@ -0,0 +1,134 @@
+package gsm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// PermissionAccess the permission to read the versions of a secret
+	PermissionAccess = "secretmanager.versions.access"
+
+	// PermissionAdd the permission to add a new version of a secret
+	PermissionAdd = "secretmanager.versions.add"
+
+	// PermissionCreate the permission to create a secret
+	PermissionCreate = "secretmanager.secrets.create"
+)
+
+var (
+	// secretPermissionsURL the URL to test the permissions on a secret in a project
+	secretPermissionsURL = "https://secretmanager.googleapis.com/v1/projects/%s/secrets/%s:testIamPermissions"
+
+	// projectPermissionsURL the URL to test the permissions on a project
+	projectPermissionsURL = "https://cloudresourcemanager.googleapis.com/v1/projects/%s:testIamPermissions"
+
+	testPermissionsTimeout = 30 * time.Second
+)
+
+// testPermissions the body of a testIamPermissions request and response
+type testPermissions struct {
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// CheckAccess checks whether the current gcloud identity can read and write the secret via testIamPermissions
+// which does not store anything. If the secret does not exist yet the permissions on the project are checked
+func (f *GoogleSecretManager) CheckAccess() (secretmgr.Access, error) {
+	if f.ProjectID == "" {
+		return secretmgr.Access{}, errors.Errorf("no cluster.project in the requirements so cannot check the access to google secret %s", f.SecretName)
+	}
+	c := util.Command{
+		Name: "gcloud",
+		Args: []string{"auth", "print-access-token"},
+	}
+	token, err := c.RunWithoutRetry()
+	if err != nil {
+		return secretmgr.Access{}, errors.Wrap(err, "failed to get the gcloud access token")
+	}
+	token = strings.TrimSpace(token)
+	client := &http.Client{Timeout: testPermissionsTimeout, Transport: common.NewUserAgentRoundTripper(nil)}
+
+	permissions := []string{PermissionAccess, PermissionAdd}
+	granted, found, err := TestIAMPermissions(client, fmt.Sprintf(secretPermissionsURL, f.ProjectID, f.SecretName), token, permissions)
+	if err != nil {
+		return secretmgr.Access{}, err
+	}
+	if found {
+		return AccessFromPermissions(permissions, granted), nil
+	}
+	permissions = append(permissions, PermissionCreate)
+	granted, _, err = TestIAMPermissions(client, fmt.Sprintf(projectPermissionsURL, f.ProjectID), token, permissions)
+	if err != nil {
+		return secretmgr.Access{}, err
+	}
+	access := AccessFromPermissions(permissions, granted)
+	access.Details = append([]string{fmt.Sprintf("the google secret %s does not exist yet so the permissions on project %s were checked", f.SecretName, f.ProjectID)}, access.Details...)
+	return access, nil
+}
+
+// TestIAMPermissions returns which of the permissions the token is granted on the resource of the testIamPermissions
+// URL along with false if the resource does not exist
+func TestIAMPermissions(client *http.Client, u string, token string, permissions []string) ([]string, bool, error) {
+	body, err := json.Marshal(&testPermissions{Permissions: permissions})
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to marshal the permissions")
+	}
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to create the request to %s", u)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to test the permissions via %s", u)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to read the response of %s", u)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, true, errors.Errorf("failed to test the permissions via %s: status %d %s", u, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	result := &testPermissions{}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return nil, true, errors.Wrapf(err, "failed to unmarshal the response of %s", u)
+	}
+	return result.Permissions, true, nil
+}
+
+// AccessFromPermissions returns the access given which of the tested permissions are granted. Reading requires the
+// access permission and writing requires all of the others
+func AccessFromPermissions(tested []string, granted []string) secretmgr.Access {
+	access := secretmgr.Access{
+		Read:  secretmgr.AccessAllowed,
+		Write: secretmgr.AccessAllowed,
+	}
+	for _, p := range tested {
+		if util.StringArrayIndex(granted, p) >= 0 {
+			continue
+		}
+		if p == PermissionAccess {
+			access.Read = secretmgr.AccessDenied
+		} else {
+			access.Write = secretmgr.AccessDenied
+		}
+		access.Details = append(access.Details, "missing the permission "+p)
+	}
+	return access
+}
@@ -0,0 +1,45 @@
+package gsm_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/gsm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestIAMPermissions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer mytoken", r.Header.Get("Authorization"), "authorization header")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body := map[string][]string{}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		require.NoError(t, err, "failed to decode the request")
+		assert.Equal(t, []string{gsm.PermissionAccess, gsm.PermissionAdd}, body["permissions"], "tested permissions")
+		_, err = w.Write([]byte(`{"permissions": ["secretmanager.versions.access"]}`))
+		require.NoError(t, err, "failed to write the response")
+	}))
+	defer server.Close()
+
+	permissions := []string{gsm.PermissionAccess, gsm.PermissionAdd}
+	granted, found, err := gsm.TestIAMPermissions(server.Client(), server.URL+"/secret", "mytoken", permissions)
+	require.NoError(t, err, "failed to test the permissions")
+	assert.True(t, found, "should have found the secret")
+	assert.Equal(t, []string{gsm.PermissionAccess}, granted, "granted permissions")
+
+	access := gsm.AccessFromPermissions(permissions, granted)
+	assert.Equal(t, secretmgr.AccessAllowed, access.Read, "read access")
+	assert.Equal(t, secretmgr.AccessDenied, access.Write, "write access")
+	assert.Equal(t, []string{"missing the permission secretmanager.versions.add"}, access.Details, "details")
+
+	_, found, err = gsm.TestIAMPermissions(server.Client(), server.URL+"/missing", "mytoken", permissions)
+	require.NoError(t, err, "should not fail if the secret does not exist")
+	assert.False(t, found, "should not have found the secret")
+}
@@ -0,0 +1,77 @@
+package gsm
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxSecretSize the maximum size in bytes of a Google Secret Manager secret version
+	MaxSecretSize = 64 * 1024
+
+	// MaxSecretChunks the maximum number of secrets we split a large secrets YAML across
+	MaxSecretChunks = 16
+
+	// chunkManifestPrefix the prefix of the main secret when the secrets YAML is split across chunk secrets
+	chunkManifestPrefix = "# helmboot-chunks: "
+)
+
+// ChunkSecretName returns the name of the secret used to store the given 1 based chunk
+func ChunkSecretName(secretName string, chunk int) string {
+	return fmt.Sprintf("%s-chunk-%d", secretName, chunk)
+}
+
+// ChunkManifest returns the text stored in the main secret to indicate the secrets are split across the given number of chunks
+func ChunkManifest(count int) string {
+	return chunkManifestPrefix + strconv.Itoa(count)
+}
+
+// ParseChunkManifest returns the number of chunks and true if the given main secret text is a chunk manifest
+func ParseChunkManifest(text string) (int, bool) {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, chunkManifestPrefix) {
+		return 0, false
+	}
+	count, err := strconv.Atoi(strings.TrimPrefix(text, chunkManifestPrefix))
+	if err != nil || count <= 0 {
+		return 0, false
+	}
+	return count, true
+}
+
+// SplitChunks splits the data into base64 encoded chunks of at most the given size so that
+// any whitespace at the chunk boundaries is preserved. Returns nil if the data fits in a single secret
+// or an error if it needs more than the maximum number of chunks
+func SplitChunks(data string, size int, maxChunks int) ([]string, error) {
+	if len(data) <= size {
+		return nil, nil
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(data))
+	var chunks []string
+	for len(encoded) > size {
+		chunks = append(chunks, encoded[:size])
+		encoded = encoded[size:]
+	}
+	chunks = append(chunks, encoded)
+	if len(chunks) > maxChunks {
+		return nil, errors.Errorf("the secrets are %d bytes which is too large to store even when split across %d google secrets of %d bytes", len(data), maxChunks, size)
+	}
+	return chunks, nil
+}
+
+// JoinChunks reassembles the data split via SplitChunks
+func JoinChunks(chunks []string) (string, error) {
+	var buf strings.Builder
+	for _, c := range chunks {
+		buf.WriteString(strings.TrimSpace(c))
+	}
+	data, err := base64.StdEncoding.DecodeString(buf.String())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode the secret chunks")
+	}
+	return string(data), nil
+}
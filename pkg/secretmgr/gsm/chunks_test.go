@@ -0,0 +1,55 @@
+package gsm_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/gsm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitChunks(t *testing.T) {
+	size := 12
+	maxChunks := 3
+
+	// the data fits in a single secret
+	for _, data := range []string{"", "secrets:\n  a", strings.Repeat("x", size)} {
+		chunks, err := gsm.SplitChunks(data, size, maxChunks)
+		require.NoError(t, err, "for data %q", data)
+		assert.Nil(t, chunks, "should not split data %q", data)
+	}
+
+	// the data needs splitting and the chunks preserve whitespace at their boundaries
+	for _, data := range []string{strings.Repeat("x", size+1), "secrets:\n  a: b  \n  c: d\n", strings.Repeat(" \n", 12)} {
+		chunks, err := gsm.SplitChunks(data, size, maxChunks)
+		require.NoError(t, err, "for data %q", data)
+		require.True(t, len(chunks) > 1, "should have split data %q", data)
+		for _, c := range chunks {
+			assert.True(t, len(c) <= size, "chunk %q should be at most %d bytes", c, size)
+		}
+		actual, err := gsm.JoinChunks(chunks)
+		require.NoError(t, err, "failed to join chunks for data %q", data)
+		assert.Equal(t, data, actual, "joined chunks")
+	}
+
+	// exactly fills the maximum number of chunks when base64 encoded
+	chunks, err := gsm.SplitChunks(strings.Repeat("x", 27), size, maxChunks)
+	require.NoError(t, err, "should fit in the maximum number of chunks")
+	assert.Len(t, chunks, maxChunks, "chunks")
+
+	_, err = gsm.SplitChunks(strings.Repeat("x", 28), size, maxChunks)
+	require.Error(t, err, "should not fit in the maximum number of chunks")
+	assert.Contains(t, err.Error(), "too large", "error message")
+}
+
+func TestChunkManifest(t *testing.T) {
+	count, ok := gsm.ParseChunkManifest(gsm.ChunkManifest(3) + "\n")
+	assert.True(t, ok, "should have parsed the manifest")
+	assert.Equal(t, 3, count, "chunk count")
+
+	_, ok = gsm.ParseChunkManifest("secrets:\n  hmacToken: abc\n")
+	assert.False(t, ok, "should not parse secrets YAML as a manifest")
+
+	assert.Equal(t, "jx-boot-secret-chunk-2", gsm.ChunkSecretName("jx-boot-secret", 2), "chunk secret name")
+}
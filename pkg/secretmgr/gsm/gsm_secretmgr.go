@@ -16,6 +16,7 @@ import (
 // GoogleSecretManager uses a Kubernetes Secret
 type GoogleSecretManager struct {
 	SecretName string
+	ProjectID  string
 }
 
 // NewGoogleSecretManager uses a Kubernetes Secret to manage secrets
@@ -28,14 +29,14 @@ func NewGoogleSecretManager(requirements *config.RequirementsConfig) (secretmgr.
 
 	// TODO should we verify we have gcloud beta setup?
 
-	sm := &GoogleSecretManager{SecretName: secretName}
+	sm := &GoogleSecretManager{SecretName: secretName, ProjectID: requirements.Cluster.ProjectID}
 
 	return sm, nil
 }
 
 // UpsertSecrets upserts the secrets
 func (f *GoogleSecretManager) UpsertSecrets(callback secretmgr.SecretCallback, defaultYaml string) error {
-	err := f.ensureSecretExists()
+	err := f.ensureSecretExists(f.SecretName)
 	if err != nil {
 		return err
 	}
@@ -68,10 +69,32 @@ func (f *GoogleSecretManager) String() string {
 	return fmt.Sprintf("Google Secret Manager for secret %s", f.SecretName)
 }
 
+// getSecret loads the secrets YAML reassembling it from the chunk secrets if it was too large for a single secret
 func (f *GoogleSecretManager) getSecret() (string, error) {
+	text, err := f.accessSecret(f.SecretName)
+	if err != nil {
+		return "", err
+	}
+	count, chunked := ParseChunkManifest(text)
+	if !chunked {
+		return text, nil
+	}
+	var chunks []string
+	for i := 1; i <= count; i++ {
+		name := ChunkSecretName(f.SecretName, i)
+		chunk, err := f.accessSecret(name)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to load chunk %d of %d from google secret %s", i, count, name)
+		}
+		chunks = append(chunks, chunk)
+	}
+	return JoinChunks(chunks)
+}
+
+func (f *GoogleSecretManager) accessSecret(name string) (string, error) {
 	c := util.Command{
 		Name: "gcloud",
-		Args: []string{"beta", "secrets", "versions", "access", "latest", "--secret=" + f.SecretName, "-q"},
+		Args: []string{"beta", "secrets", "versions", "access", "latest", "--secret=" + name, "-q"},
 	}
 	log.Logger().Debugf("running gcloud %s", strings.Join(c.Args, " "))
 
@@ -82,10 +105,10 @@ func (f *GoogleSecretManager) getSecret() (string, error) {
 	return text, nil
 }
 
-func (f *GoogleSecretManager) secretExists() bool {
+func (f *GoogleSecretManager) secretExists(name string) bool {
 	c := util.Command{
 		Name: "gcloud",
-		Args: []string{"beta", "secrets", "list", "--filter=" + f.SecretName},
+		Args: []string{"beta", "secrets", "list", "--filter=" + name},
 	}
 
 	log.Logger().Debugf("running gcloud %s", strings.Join(c.Args, " "))
@@ -99,15 +122,40 @@ func (f *GoogleSecretManager) secretExists() bool {
 	lines = lines[1:]
 	for _, line := range lines {
 		fields := strings.Fields(line)
-		if fields[0] == f.SecretName {
+		if fields[0] == name {
 			return true
 		}
-		log.Logger().Infof("unknown secret name '%s'", fields[0])
+		log.Logger().Debugf("ignoring secret name '%s'", fields[0])
 	}
 	return false
 }
 
+// updateSecretYaml saves the secrets YAML splitting it across numbered chunk secrets if it is too large for a single secret.
+// The chunks are written before the main secret so that readers never see a manifest for missing chunks
 func (f *GoogleSecretManager) updateSecretYaml(newYaml string) error {
+	chunks, err := SplitChunks(newYaml, MaxSecretSize, MaxSecretChunks)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save google secret %s", f.SecretName)
+	}
+	if chunks == nil {
+		return f.addSecretVersion(f.SecretName, newYaml)
+	}
+	log.Logger().Debugf("splitting the secrets across %d google secrets as they are larger than %d bytes", len(chunks), MaxSecretSize)
+	for i, chunk := range chunks {
+		name := ChunkSecretName(f.SecretName, i+1)
+		err = f.ensureSecretExists(name)
+		if err != nil {
+			return err
+		}
+		err = f.addSecretVersion(name, chunk)
+		if err != nil {
+			return errors.Wrapf(err, "failed to save chunk %d of %d to google secret %s", i+1, len(chunks), name)
+		}
+	}
+	return f.addSecretVersion(f.SecretName, ChunkManifest(len(chunks)))
+}
+
+func (f *GoogleSecretManager) addSecretVersion(name string, data string) error {
 	tmpFile, err := ioutil.TempFile("", "gsm-secret-")
 	if err != nil {
 		return errors.Wrap(err, "failed to create temp file")
@@ -115,14 +163,14 @@ func (f *GoogleSecretManager) updateSecretYaml(newYaml string) error {
 	fileName := tmpFile.Name()
 	defer os.Remove(fileName)
 
-	err = ioutil.WriteFile(fileName, []byte(newYaml), util.DefaultFileWritePermissions)
+	err = ioutil.WriteFile(fileName, []byte(data), util.DefaultFileWritePermissions)
 	if err != nil {
 		return errors.Wrapf(err, "failed to save secrets to temp file %s", fileName)
 	}
 
 	c := util.Command{
 		Name: "gcloud",
-		Args: []string{"beta", "secrets", "versions", "add", f.SecretName, "--data-file", fileName},
+		Args: []string{"beta", "secrets", "versions", "add", name, "--data-file", fileName},
 	}
 	log.Logger().Debugf("running gcloud %s", strings.Join(c.Args, " "))
 
@@ -130,19 +178,19 @@ func (f *GoogleSecretManager) updateSecretYaml(newYaml string) error {
 	return err
 }
 
-func (f *GoogleSecretManager) ensureSecretExists() error {
-	exists := f.secretExists()
+func (f *GoogleSecretManager) ensureSecretExists(name string) error {
+	exists := f.secretExists(name)
 	if exists {
 		return nil
 	}
 	c := util.Command{
 		Name: "gcloud",
-		Args: []string{"beta", "secrets", "create", f.SecretName, "--replication-policy", "automatic"},
+		Args: []string{"beta", "secrets", "create", name, "--replication-policy", "automatic"},
 	}
 	log.Logger().Debugf("running gcloud %s", strings.Join(c.Args, " "))
 	_, err := c.RunWithoutRetry()
 	if err != nil {
-		return errors.Wrapf(err, "failed to ensure the google secret %s exists", f.SecretName)
+		return errors.Wrapf(err, "failed to ensure the google secret %s exists", name)
 	}
 	return nil
 }
@@ -2,6 +2,7 @@ package secretmgr
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/jenkins-x-labs/helmboot/pkg/githelpers"
@@ -22,21 +23,34 @@ var expectedSecretPaths = []string{
 
 // VerifyBootSecrets verifies the boot secrets
 func VerifyBootSecrets(secretsYAML string) error {
+	missing, err := MissingBootSecrets(secretsYAML)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return errors.Errorf("missing secret entry: %s", missing[0])
+	}
+	return nil
+}
+
+// MissingBootSecrets returns the paths of the required boot secrets which are missing or empty
+func MissingBootSecrets(secretsYAML string) ([]string, error) {
 	data := map[string]interface{}{}
 
 	err := yaml.Unmarshal([]byte(secretsYAML), &data)
 	if err != nil {
-		return errors.Wrap(err, "failed to unmarshal secrets YAML")
+		return nil, errors.Wrap(err, "failed to unmarshal secrets YAML")
 	}
 
 	// simple validation for now, using presence of a string value
+	var missing []string
 	for _, path := range expectedSecretPaths {
 		value := util.GetMapValueAsStringViaPath(data, path)
 		if value == "" {
-			return errors.Errorf("missing secret entry: %s", path)
+			missing = append(missing, path)
 		}
 	}
-	return nil
+	return missing, nil
 }
 
 // ToSecretsYAML converts the data to secrets YAML
@@ -94,6 +108,185 @@ func AddUserTokenToGitURLFromSecretsYAML(gitURL string, secretsYAML string) (str
 	return gitURL, nil
 }
 
+// MismatchedSecrets returns the sorted paths of the secrets in the desired secrets YAML which are missing from or have
+// a different value in the actual secrets YAML. The values themselves are never returned so they are safe to log
+func MismatchedSecrets(desiredYAML string, actualYAML string) ([]string, error) {
+	desired := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(desiredYAML), &desired)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the desired secrets YAML")
+	}
+	actual := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(actualYAML), &actual)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the actual secrets YAML")
+	}
+	desiredValues := map[string]string{}
+	flattenSecrets(desiredValues, "", desired)
+	actualValues := map[string]string{}
+	flattenSecrets(actualValues, "", actual)
+
+	var answer []string
+	for path, value := range desiredValues {
+		actualValue, ok := actualValues[path]
+		if !ok || actualValue != value {
+			answer = append(answer, path)
+		}
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+// StaleSecrets returns the sorted paths of the non empty secrets in the current secrets YAML which are not in the
+// input secrets YAML. The values themselves are never returned so they are safe to log
+func StaleSecrets(inputYAML string, currentYAML string) ([]string, error) {
+	input := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(inputYAML), &input)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the input secrets YAML")
+	}
+	current := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(currentYAML), &current)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the current secrets YAML")
+	}
+	inputValues := map[string]string{}
+	flattenSecrets(inputValues, "", input)
+	currentValues := map[string]string{}
+	flattenSecrets(currentValues, "", current)
+
+	var answer []string
+	for path, value := range currentValues {
+		_, ok := inputValues[path]
+		if !ok && value != "" {
+			answer = append(answer, path)
+		}
+	}
+	sort.Strings(answer)
+	return answer, nil
+}
+
+// MergeSecretsYAML merges the input secrets YAML into the current secrets YAML with the input values taking precedence
+func MergeSecretsYAML(currentYAML string, inputYAML string) (string, error) {
+	current := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(currentYAML), &current)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal the current secrets YAML")
+	}
+	input := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(inputYAML), &input)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal the input secrets YAML")
+	}
+	mergeSecrets(current, input)
+	data, err := yaml.Marshal(current)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the merged secrets YAML")
+	}
+	return string(data), nil
+}
+
+// SecretChange a change to a secret with the values masked so that it is safe to log
+type SecretChange struct {
+	Path    string
+	Before  string
+	After   string
+	Changed bool
+}
+
+// PatchSecretChanges returns the changes the patch secrets YAML would make to the current secrets YAML when merged
+// sorted by path. Only the secrets in the patch are returned and their values are masked
+func PatchSecretChanges(currentYAML string, patchYAML string) ([]SecretChange, error) {
+	current := map[string]interface{}{}
+	err := yaml.Unmarshal([]byte(currentYAML), &current)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the current secrets YAML")
+	}
+	patch := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(patchYAML), &patch)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the patch secrets YAML")
+	}
+	currentValues := map[string]string{}
+	flattenSecrets(currentValues, "", current)
+	patchValues := map[string]string{}
+	flattenSecrets(patchValues, "", patch)
+
+	var answer []SecretChange
+	for path, after := range patchValues {
+		before, exists := currentValues[path]
+		change := SecretChange{
+			Path:    path,
+			Before:  MaskSecret(before, exists),
+			After:   MaskSecret(after, true),
+			Changed: !exists || before != after,
+		}
+		answer = append(answer, change)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Path < answer[j].Path
+	})
+	return answer, nil
+}
+
+// MaskSecret returns a masked secret value which only shows whether the secret exists and is blank
+func MaskSecret(value string, exists bool) string {
+	if !exists {
+		return "<unset>"
+	}
+	if value == "" {
+		return "<empty>"
+	}
+	return "****"
+}
+
+// mergeSecrets recursively merges the values into the target map
+func mergeSecrets(target map[string]interface{}, values map[string]interface{}) {
+	for k, v := range values {
+		childMap, ok := v.(map[string]interface{})
+		if ok {
+			targetMap, ok := target[k].(map[string]interface{})
+			if ok {
+				mergeSecrets(targetMap, childMap)
+				continue
+			}
+		}
+		target[k] = v
+	}
+}
+
+// SecretPaths returns the sorted dot separated paths of the leaf values of the secrets
+func SecretPaths(secrets map[string]interface{}) []string {
+	values := map[string]string{}
+	flattenSecrets(values, "", secrets)
+	var answer []string
+	for k := range values {
+		answer = append(answer, k)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+// flattenSecrets adds the leaf values of the map to the values keyed by their dot separated path
+func flattenSecrets(values map[string]string, prefix string, m map[string]interface{}) {
+	for k, v := range m {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		childMap, ok := v.(map[string]interface{})
+		if ok {
+			flattenSecrets(values, path, childMap)
+			continue
+		}
+		if v == nil {
+			values[path] = ""
+			continue
+		}
+		values[path] = strings.TrimSpace(fmt.Sprintf("%v", v))
+	}
+}
+
 // RemoveMapEmptyValues recursively removes all empty string or nil entries
 func RemoveMapEmptyValues(m map[string]interface{}) {
 	for k, v := range m {
@@ -0,0 +1,83 @@
+package secretmgr_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMismatchedSecrets(t *testing.T) {
+	desired := `secrets:
+  adminUser:
+    username: admin
+    password: secret
+  hmacToken: abc
+`
+	actual := `secrets:
+  adminUser:
+    username: admin
+    password: changed
+  pipelineUser:
+    token: extra
+`
+	mismatched, err := secretmgr.MismatchedSecrets(desired, actual)
+	require.NoError(t, err, "failed to compare secrets")
+	assert.Equal(t, []string{"secrets.adminUser.password", "secrets.hmacToken"}, mismatched)
+
+	mismatched, err = secretmgr.MismatchedSecrets(desired, desired+"  extra: value\n")
+	require.NoError(t, err, "failed to compare secrets")
+	assert.Empty(t, mismatched, "additional actual secrets should be ignored")
+}
+
+func TestStaleAndMergeSecrets(t *testing.T) {
+	current := `secrets:
+  adminUser:
+    username: admin
+    password: old
+  oldToken: stale
+  emptyToken: ""
+`
+	input := `secrets:
+  adminUser:
+    password: new
+  hmacToken: abc
+`
+	stale, err := secretmgr.StaleSecrets(input, current)
+	require.NoError(t, err, "failed to find stale secrets")
+	assert.Equal(t, []string{"secrets.adminUser.username", "secrets.oldToken"}, stale)
+
+	merged, err := secretmgr.MergeSecretsYAML(current, input)
+	require.NoError(t, err, "failed to merge secrets")
+	mismatched, err := secretmgr.MismatchedSecrets(input, merged)
+	require.NoError(t, err, "failed to compare secrets")
+	assert.Empty(t, mismatched, "the merged secrets should contain the input")
+
+	stale, err = secretmgr.StaleSecrets(merged, current)
+	require.NoError(t, err, "failed to find stale secrets")
+	assert.Empty(t, stale, "the merged secrets should keep the current secrets")
+}
+
+func TestPatchSecretChanges(t *testing.T) {
+	current := `secrets:
+  adminUser:
+    username: admin
+    password: old
+  hmacToken: abc
+`
+	patch := `secrets:
+  adminUser:
+    password: new
+  hmacToken: abc
+  pipelineUser:
+    token: mytoken
+`
+	changes, err := secretmgr.PatchSecretChanges(current, patch)
+	require.NoError(t, err, "failed to find the patch changes")
+	assert.Equal(t, []secretmgr.SecretChange{
+		{Path: "secrets.adminUser.password", Before: "****", After: "****", Changed: true},
+		{Path: "secrets.hmacToken", Before: "****", After: "****", Changed: false},
+		{Path: "secrets.pipelineUser.token", Before: "<unset>", After: "****", Changed: true},
+	}, changes, "changes")
+}
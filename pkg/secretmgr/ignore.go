@@ -0,0 +1,133 @@
+package secretmgr
+
+import (
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// SecretsIgnoreFile the default name of the file of glob patterns of secret keys which must never be output
+const SecretsIgnoreFile = ".secretsignore"
+
+// LoadSecretsIgnore loads the glob patterns from the given ignore file. Returns no patterns if the file does not exist
+func LoadSecretsIgnore(fileName string) ([]string, error) {
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if ignore file %s exists", fileName)
+	}
+	if !exists {
+		return nil, nil
+	}
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load ignore file %s", fileName)
+	}
+	return ParseSecretsIgnore(data, fileName)
+}
+
+// ParseSecretsIgnore parses the lines of glob patterns of dot separated secret keys such as 'pipelineUser.*'
+// ignoring blank lines and '#' comments
+func ParseSecretsIgnore(data []byte, fileName string) ([]string, error) {
+	var answer []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		_, err := path.Match(toIgnorePath(line), "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %s in ignore file %s", line, fileName)
+		}
+		answer = append(answer, line)
+	}
+	return answer, nil
+}
+
+// IsIgnoredSecretKey returns true if the dot separated key or any of its parent keys matches one of the patterns
+// so that 'pipelineUser' ignores 'pipelineUser.token' like a directory in a .gitignore file
+func IsIgnoredSecretKey(key string, patterns []string) bool {
+	segments := strings.Split(key, ".")
+	for _, pattern := range patterns {
+		p := toIgnorePath(pattern)
+		for i := len(segments); i > 0; i-- {
+			matched, err := path.Match(p, strings.Join(segments[:i], "/"))
+			if err == nil && matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RemoveIgnoredSecrets returns the secret data without the keys matching the patterns along with the sorted
+// keys which were omitted. A secrets YAML document in the data has its matching values removed
+func RemoveIgnoredSecrets(secretData map[string][]byte, patterns []string) (map[string][]byte, []string, error) {
+	if len(patterns) == 0 {
+		return secretData, nil, nil
+	}
+	var omitted []string
+	answer := map[string][]byte{}
+	for k, v := range secretData {
+		if k != LocalSecretKey && IsIgnoredSecretKey(k, patterns) {
+			omitted = append(omitted, k)
+			continue
+		}
+		answer[k] = v
+	}
+
+	data := answer[LocalSecretKey]
+	if len(data) > 0 {
+		values := map[string]interface{}{}
+		err := yaml.Unmarshal(data, &values)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to unmarshal secrets YAML")
+		}
+		secrets, ok := values[DefaultSecretsRootKey].(map[string]interface{})
+		if ok {
+			removed := false
+			for _, k := range SecretPaths(secrets) {
+				if IsIgnoredSecretKey(k, patterns) {
+					removeMapPath(secrets, strings.Split(k, "."))
+					omitted = append(omitted, k)
+					removed = true
+				}
+			}
+			if removed {
+				data, err = yaml.Marshal(values)
+				if err != nil {
+					return nil, nil, errors.Wrap(err, "failed to marshal secrets YAML")
+				}
+				answer[LocalSecretKey] = data
+			}
+		}
+	}
+	sort.Strings(omitted)
+	return answer, omitted, nil
+}
+
+// removeMapPath removes the value at the given path along with any parent maps which become empty
+func removeMapPath(m map[string]interface{}, paths []string) {
+	k := paths[0]
+	if len(paths) == 1 {
+		delete(m, k)
+		return
+	}
+	childMap, ok := m[k].(map[string]interface{})
+	if !ok {
+		return
+	}
+	removeMapPath(childMap, paths[1:])
+	if len(childMap) == 0 {
+		delete(m, k)
+	}
+}
+
+// toIgnorePath converts the dot separated pattern into a slash separated path so that wildcards match a single key
+func toIgnorePath(pattern string) string {
+	return strings.ReplaceAll(pattern, ".", "/")
+}
@@ -0,0 +1,59 @@
+package secretmgr_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSecretsIgnore(t *testing.T) {
+	patterns, err := secretmgr.ParseSecretsIgnore([]byte("# never share these\n\npipelineUser.token\n  adminUser.*  \n"), secretmgr.SecretsIgnoreFile)
+	require.NoError(t, err, "failed to parse ignore file")
+	assert.Equal(t, []string{"pipelineUser.token", "adminUser.*"}, patterns, "patterns")
+
+	_, err = secretmgr.ParseSecretsIgnore([]byte("adminUser.[\n"), secretmgr.SecretsIgnoreFile)
+	assert.Error(t, err, "should have failed for an invalid pattern")
+}
+
+func TestIsIgnoredSecretKey(t *testing.T) {
+	patterns := []string{"adminUser.*", "pipelineUser", "*Token"}
+	testCases := map[string]bool{
+		"adminUser.password":    true,
+		"adminUser":             false,
+		"pipelineUser.token":    true,
+		"pipelineUser.username": true,
+		"hmacToken":             true,
+		"mavenSettings.token":   false,
+	}
+	for key, expected := range testCases {
+		assert.Equal(t, expected, secretmgr.IsIgnoredSecretKey(key, patterns), "for key %s", key)
+	}
+}
+
+func TestRemoveIgnoredSecrets(t *testing.T) {
+	patterns := []string{"pipelineUser.token", "hmacToken"}
+
+	data, omitted, err := secretmgr.RemoveIgnoredSecrets(map[string][]byte{
+		"adminUser.password": []byte("dummypwd"),
+		"hmacToken":          []byte("TODO"),
+		"pipelineUser.token": []byte("dummytoken"),
+	}, patterns)
+	require.NoError(t, err, "failed to remove ignored secrets")
+	assert.Equal(t, []string{"hmacToken", "pipelineUser.token"}, omitted, "omitted keys")
+	assert.Equal(t, map[string][]byte{"adminUser.password": []byte("dummypwd")}, data, "remaining data")
+
+	secretsYAML := `secrets:
+  adminUser:
+    password: dummypwd
+  hmacToken: TODO
+  pipelineUser:
+    token: dummytoken
+`
+	data, omitted, err = secretmgr.RemoveIgnoredSecrets(map[string][]byte{secretmgr.LocalSecretKey: []byte(secretsYAML)}, patterns)
+	require.NoError(t, err, "failed to remove ignored secrets from the secrets YAML")
+	assert.Equal(t, []string{"hmacToken", "pipelineUser.token"}, omitted, "omitted keys")
+	testhelpers.AssertYamlEqual(t, "secrets:\n  adminUser:\n    password: dummypwd\n", string(data[secretmgr.LocalSecretKey]), "remaining secrets YAML")
+}
@@ -0,0 +1,129 @@
+package kms
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// Encrypter encrypts and decrypts data with a KMS key
+type Encrypter interface {
+	Encrypt(key Key, plaintext []byte) ([]byte, error)
+	Decrypt(key Key, ciphertext []byte) ([]byte, error)
+}
+
+// CLIEncrypter encrypts and decrypts data with the gcloud or aws CLI using the current identity
+type CLIEncrypter struct {
+}
+
+// NewCLIEncrypter creates an encrypter using the gcloud or aws CLI
+func NewCLIEncrypter() Encrypter {
+	return &CLIEncrypter{}
+}
+
+// Encrypt encrypts the plain text with the key
+func (e *CLIEncrypter) Encrypt(key Key, plaintext []byte) ([]byte, error) {
+	answer, err := e.run(key, "encrypt", plaintext)
+	if err != nil {
+		return nil, kmsError(err, key, "encrypt the secrets")
+	}
+	return answer, nil
+}
+
+// Decrypt decrypts the cipher text with the key
+func (e *CLIEncrypter) Decrypt(key Key, ciphertext []byte) ([]byte, error) {
+	answer, err := e.run(key, "decrypt", ciphertext)
+	if err != nil {
+		return nil, kmsError(err, key, "decrypt the secrets")
+	}
+	return answer, nil
+}
+
+// run runs the encrypt or decrypt command of the CLI of the provider of the key passing the input via a temp file
+func (e *CLIEncrypter) run(key Key, action string, input []byte) ([]byte, error) {
+	tmpDir, err := ioutil.TempDir("", "helmboot-kms-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	inFile := filepath.Join(tmpDir, "input")
+	err = ioutil.WriteFile(inFile, input, util.DefaultFileWritePermissions)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to save temp file %s", inFile)
+	}
+
+	switch key.Provider {
+	case ProviderGoogle:
+		outFile := filepath.Join(tmpDir, "output")
+		inFlag, outFlag := "--plaintext-file", "--ciphertext-file"
+		if action == "decrypt" {
+			inFlag, outFlag = "--ciphertext-file", "--plaintext-file"
+		}
+		c := util.Command{
+			Name: "gcloud",
+			Args: []string{"kms", action, "--key", key.Name, inFlag, inFile, outFlag, outFile, "-q"},
+		}
+		log.Logger().Debugf("running gcloud %s", strings.Join(c.Args, " "))
+		_, err = c.RunWithoutRetry()
+		if err != nil {
+			return nil, err
+		}
+		return ioutil.ReadFile(outFile)
+
+	case ProviderAWS:
+		args := []string{"kms", action, "--output", "text"}
+		if action == "decrypt" {
+			args = append(args, "--ciphertext-blob", "fileb://"+inFile, "--query", "Plaintext")
+		} else {
+			args = append(args, "--plaintext", "fileb://"+inFile, "--query", "CiphertextBlob")
+		}
+		args = append(args, "--key-id", key.Name)
+		if key.Region != "" {
+			args = append(args, "--region", key.Region)
+		}
+		c := util.Command{
+			Name: "aws",
+			Args: args,
+		}
+		log.Logger().Debugf("running aws %s", strings.Join(c.Args, " "))
+		text, err := c.RunWithoutRetry()
+		if err != nil {
+			return nil, err
+		}
+		// the aws CLI outputs the binary fields base64 encoded
+		answer, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode the output of aws kms %s", action)
+		}
+		return answer, nil
+
+	default:
+		return nil, errors.Errorf("unsupported KMS provider %s", key.Provider)
+	}
+}
+
+// kmsError returns a clear error if the current identity is missing the KMS permissions otherwise wraps the error
+func kmsError(err error, key Key, action string) error {
+	text := err.Error()
+	if strings.Contains(text, "PERMISSION_DENIED") || strings.Contains(text, "AccessDenied") {
+		permission := "kms:Encrypt"
+		if key.Provider == ProviderGoogle {
+			permission = "cloudkms.cryptoKeyVersions.useToEncrypt"
+		}
+		if strings.HasPrefix(action, "decrypt") {
+			permission = "kms:Decrypt"
+			if key.Provider == ProviderGoogle {
+				permission = "cloudkms.cryptoKeyVersions.useToDecrypt"
+			}
+		}
+		return errors.Errorf("the current identity is not allowed to %s with the %s. Please grant it the %s permission such as via the roles/cloudkms.cryptoKeyEncrypterDecrypter role on GCP or a key policy on AWS", action, key.String(), permission)
+	}
+	return errors.Wrapf(err, "failed to %s with the %s", action, key.String())
+}
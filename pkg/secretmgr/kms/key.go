@@ -0,0 +1,89 @@
+package kms
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/jenkins-x/jx/pkg/cloud"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProviderGoogle for a Google Cloud KMS key
+	ProviderGoogle = "gcp"
+
+	// ProviderAWS for an AWS KMS key
+	ProviderAWS = "aws"
+
+	// googleKeyLocation the location of the key rings created for vault by jx
+	googleKeyLocation = "global"
+)
+
+var (
+	googleKeyRegex = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+	awsKeyARNRegex = regexp.MustCompile(`^arn:aws[a-z\-]*:kms:([a-z0-9\-]+):[0-9]+:(key|alias)/.+$`)
+	awsKeyIDRegex  = regexp.MustCompile(`^([0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}|mrk-[0-9a-f]+|alias/.+)$`)
+)
+
+// Key identifies the KMS key used to encrypt the secrets
+type Key struct {
+	// Provider the KMS provider of the key such as gcp or aws
+	Provider string `json:"provider"`
+
+	// Name the resource name of a Google key or the ID, alias or ARN of an AWS key
+	Name string `json:"key"`
+
+	// Region the optional region of an AWS key
+	Region string `json:"region,omitempty"`
+}
+
+// String returns the description of the key
+func (k Key) String() string {
+	if k.Region != "" {
+		return fmt.Sprintf("%s KMS key %s in region %s", k.Provider, k.Name, k.Region)
+	}
+	return fmt.Sprintf("%s KMS key %s", k.Provider, k.Name)
+}
+
+// ParseKey parses the key name detecting the provider from its format. Google keys use the resource name of the form
+// projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key> and AWS keys use a key ID, alias or ARN
+func ParseKey(name string, region string) (Key, error) {
+	switch {
+	case googleKeyRegex.MatchString(name):
+		return Key{Provider: ProviderGoogle, Name: name}, nil
+	case awsKeyARNRegex.MatchString(name):
+		if region == "" {
+			region = awsKeyARNRegex.FindStringSubmatch(name)[1]
+		}
+		return Key{Provider: ProviderAWS, Name: name, Region: region}, nil
+	case awsKeyIDRegex.MatchString(name):
+		return Key{Provider: ProviderAWS, Name: name, Region: region}, nil
+	default:
+		return Key{}, errors.Errorf("unknown KMS key %s. Expected a Google key of the form projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key> or an AWS key ID, alias or ARN", name)
+	}
+}
+
+// KeyFromRequirements returns the KMS key configured for vault in the requirements which is a key ring and key
+// in the project on GKE or the KMS key ID on EKS
+func KeyFromRequirements(requirements *config.RequirementsConfig) (Key, error) {
+	if requirements != nil {
+		vault := requirements.Vault
+		switch requirements.Cluster.Provider {
+		case cloud.GKE:
+			if vault.Keyring != "" && vault.Key != "" && requirements.Cluster.ProjectID != "" {
+				name := fmt.Sprintf("projects/%s/locations/%s/keyRings/%s/cryptoKeys/%s", requirements.Cluster.ProjectID, googleKeyLocation, vault.Keyring, vault.Key)
+				return ParseKey(name, "")
+			}
+		case cloud.EKS, cloud.AWS:
+			if vault.AWSConfig != nil && vault.AWSConfig.KMSKeyID != "" {
+				region := vault.AWSConfig.KMSRegion
+				if region == "" {
+					region = requirements.Cluster.Region
+				}
+				return ParseKey(vault.AWSConfig.KMSKeyID, region)
+			}
+		}
+	}
+	return Key{}, errors.Errorf("no KMS key could be found in the vault configuration of the requirements so please specify one via --kms-key")
+}
@@ -0,0 +1,163 @@
+package kms
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// accessProbe the plain text encrypted and decrypted to check the access to the KMS key
+const accessProbe = "helmboot-kms-probe"
+
+// Envelope the YAML document stored instead of the secrets YAML when they are encrypted with a KMS key
+type Envelope struct {
+	KMS *EnvelopeKey `json:"kms,omitempty"`
+}
+
+// EnvelopeKey the KMS key and the base64 encoded cipher text of the secrets YAML
+type EnvelopeKey struct {
+	Key
+	Ciphertext string `json:"ciphertext"`
+}
+
+// KMSSecretManager encrypts the secrets YAML with a KMS key before storing it in another secret manager such as
+// the local Kubernetes Secret and decrypts it on read. Any unencrypted secrets are encrypted when next saved
+type KMSSecretManager struct {
+	SecretManager secretmgr.SecretManager
+	Key           Key
+	Encrypter     Encrypter
+}
+
+// NewKMSSecretManager encrypts the secrets of the secret manager with the given KMS key
+func NewKMSSecretManager(sm secretmgr.SecretManager, key Key, encrypter Encrypter) secretmgr.SecretManager {
+	if encrypter == nil {
+		encrypter = NewCLIEncrypter()
+	}
+	return &KMSSecretManager{SecretManager: sm, Key: key, Encrypter: encrypter}
+}
+
+// UpsertSecrets upserts the secrets decrypting and encrypting them with the KMS key
+func (f *KMSSecretManager) UpsertSecrets(callback secretmgr.SecretCallback, defaultYaml string) error {
+	kmsCallback := func(secretYaml string) (string, error) {
+		plainYaml, _, err := DecryptSecretsYAML(secretYaml, f.Encrypter)
+		if err != nil {
+			return "", err
+		}
+		updatedYaml, err := callback(plainYaml)
+		if err != nil {
+			return "", err
+		}
+		if updatedYaml == plainYaml {
+			return secretYaml, nil
+		}
+		return EncryptSecretsYAML(updatedYaml, f.Key, f.Encrypter)
+	}
+	return f.SecretManager.UpsertSecrets(kmsCallback, defaultYaml)
+}
+
+// CheckAccess checks the access of the underlying secret manager and that the KMS key can be used to encrypt and
+// decrypt a probe value
+func (f *KMSSecretManager) CheckAccess() (secretmgr.Access, error) {
+	access, err := secretmgr.CheckAccess(f.SecretManager)
+	if err != nil {
+		return access, err
+	}
+	ciphertext, err := f.Encrypter.Encrypt(f.Key, []byte(accessProbe))
+	if err != nil {
+		access.Read = secretmgr.CombineAccess(access.Read, secretmgr.AccessUnknown)
+		access.Write = secretmgr.AccessDenied
+		access.Details = append(access.Details, strings.TrimSpace(err.Error()))
+		return access, nil
+	}
+	_, err = f.Encrypter.Decrypt(f.Key, ciphertext)
+	if err != nil {
+		access.Read = secretmgr.AccessDenied
+		access.Details = append(access.Details, strings.TrimSpace(err.Error()))
+	}
+	return access, nil
+}
+
+// Kind returns the kind of the underlying secret manager as encryption does not change where the secrets are stored
+func (f *KMSSecretManager) Kind() string {
+	return f.SecretManager.Kind()
+}
+
+// String returns the description of the underlying secret manager and the KMS key
+func (f *KMSSecretManager) String() string {
+	return fmt.Sprintf("%s encrypted with %s", f.SecretManager.String(), f.Key.String())
+}
+
+// ParseEnvelope returns the envelope if the secrets YAML is encrypted with a KMS key otherwise nil
+func ParseEnvelope(secretYaml string) *Envelope {
+	if !strings.Contains(secretYaml, "kms:") {
+		return nil
+	}
+	envelope := &Envelope{}
+	err := yaml.Unmarshal([]byte(secretYaml), envelope)
+	if err != nil || envelope.KMS == nil || envelope.KMS.Ciphertext == "" {
+		return nil
+	}
+	return envelope
+}
+
+// DecryptSecretsYAML decrypts the secrets YAML if it is encrypted with a KMS key returning whether it was encrypted.
+// Unencrypted secrets YAML is returned unchanged
+func DecryptSecretsYAML(secretYaml string, encrypter Encrypter) (string, bool, error) {
+	envelope := ParseEnvelope(secretYaml)
+	if envelope == nil {
+		return secretYaml, false, nil
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.KMS.Ciphertext)
+	if err != nil {
+		return "", true, errors.Wrap(err, "failed to decode the KMS encrypted secrets")
+	}
+	if encrypter == nil {
+		encrypter = NewCLIEncrypter()
+	}
+	plaintext, err := encrypter.Decrypt(envelope.KMS.Key, ciphertext)
+	if err != nil {
+		return "", true, err
+	}
+	return string(plaintext), true, nil
+}
+
+// EncryptSecretsYAML encrypts the secrets YAML with the KMS key returning the YAML of the envelope
+func EncryptSecretsYAML(secretYaml string, key Key, encrypter Encrypter) (string, error) {
+	ciphertext, err := encrypter.Encrypt(key, []byte(secretYaml))
+	if err != nil {
+		return "", err
+	}
+	envelope := &Envelope{
+		KMS: &EnvelopeKey{
+			Key:        key,
+			Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+		},
+	}
+	data, err := yaml.Marshal(envelope)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal the KMS envelope")
+	}
+	return string(data), nil
+}
+
+// DecryptSecretData decrypts the secrets YAML in the data of the local Secret if it is encrypted with a KMS key
+func DecryptSecretData(data map[string][]byte, encrypter Encrypter) (map[string][]byte, error) {
+	value := data[secretmgr.LocalSecretKey]
+	if value == nil {
+		return data, nil
+	}
+	plainYaml, encrypted, err := DecryptSecretsYAML(string(value), encrypter)
+	if err != nil || !encrypted {
+		return data, err
+	}
+	answer := map[string][]byte{}
+	for k, v := range data {
+		answer[k] = v
+	}
+	answer[secretmgr.LocalSecretKey] = []byte(plainYaml)
+	return answer, nil
+}
@@ -0,0 +1,141 @@
+package kms_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/fake"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/kms"
+	"github.com/jenkins-x/jx/pkg/cloud"
+	"github.com/jenkins-x/jx/pkg/config"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEncrypter reverses the data prefixed with the key name and denies access to keys named denied
+type fakeEncrypter struct {
+}
+
+func (e *fakeEncrypter) Encrypt(key kms.Key, plaintext []byte) ([]byte, error) {
+	if key.Name == "alias/denied" {
+		return nil, errors.Errorf("the current identity is not allowed to encrypt the secrets with the %s", key.String())
+	}
+	return []byte(key.Name + ":" + reverse(string(plaintext))), nil
+}
+
+func (e *fakeEncrypter) Decrypt(key kms.Key, ciphertext []byte) ([]byte, error) {
+	text := strings.TrimPrefix(string(ciphertext), key.Name+":")
+	if text == string(ciphertext) {
+		return nil, errors.Errorf("encrypted with a different key than %s", key.Name)
+	}
+	return []byte(reverse(text)), nil
+}
+
+func reverse(text string) string {
+	runes := []rune(text)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func TestKMSSecretManager(t *testing.T) {
+	plainYaml := "secrets:\n  hmacToken: plain-token\n"
+	fakeSM := &fake.FakeSecretManager{SecretsYAML: plainYaml}
+	key := kms.Key{Provider: kms.ProviderAWS, Name: "alias/helmboot", Region: "us-east-1"}
+	encrypter := &fakeEncrypter{}
+	sm := kms.NewKMSSecretManager(fakeSM, key, encrypter)
+	assert.Equal(t, "fake encrypted with aws KMS key alias/helmboot in region us-east-1", sm.String(), "description")
+
+	// reading the unencrypted secrets leaves them untouched
+	err := sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		assert.Equal(t, plainYaml, secretsYaml, "should have read the unencrypted secrets")
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to read the secrets")
+	assert.Equal(t, plainYaml, fakeSM.SecretsYAML, "stored secrets")
+
+	updatedYaml := "secrets:\n  hmacToken: new-token\n"
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		return updatedYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to write the secrets")
+	assert.NotContains(t, fakeSM.SecretsYAML, "new-token", "should have encrypted the stored secrets")
+	envelope := kms.ParseEnvelope(fakeSM.SecretsYAML)
+	require.NotNil(t, envelope, "should have stored a KMS envelope")
+	assert.Equal(t, key, envelope.KMS.Key, "envelope key")
+
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		assert.Equal(t, updatedYaml, secretsYaml, "should have decrypted the secrets")
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to read the encrypted secrets")
+
+	data, err := kms.DecryptSecretData(map[string][]byte{
+		secretmgr.LocalSecretKey: []byte(fakeSM.SecretsYAML),
+	}, encrypter)
+	require.NoError(t, err, "failed to decrypt the Secret data")
+	assert.Equal(t, updatedYaml, string(data[secretmgr.LocalSecretKey]), "decrypted Secret data")
+
+	access, err := secretmgr.CheckAccess(sm)
+	require.NoError(t, err, "failed to check the access")
+	assert.Equal(t, secretmgr.AccessAllowed, access.Read, "read access")
+
+	denied := kms.NewKMSSecretManager(fakeSM, kms.Key{Provider: kms.ProviderAWS, Name: "alias/denied"}, encrypter)
+	err = denied.UpsertSecrets(func(secretsYaml string) (string, error) {
+		return "secrets:\n  hmacToken: other-token\n", nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.Error(t, err, "should have failed to encrypt without permission")
+	assert.Contains(t, err.Error(), "not allowed to encrypt", "error message")
+
+	access, err = secretmgr.CheckAccess(denied)
+	require.NoError(t, err, "failed to check the access")
+	assert.Equal(t, secretmgr.AccessDenied, access.Write, "write access")
+}
+
+func TestParseKey(t *testing.T) {
+	testCases := []struct {
+		name     string
+		region   string
+		expected kms.Key
+	}{
+		{
+			name:     "projects/myproject/locations/global/keyRings/myring/cryptoKeys/mykey",
+			expected: kms.Key{Provider: kms.ProviderGoogle, Name: "projects/myproject/locations/global/keyRings/myring/cryptoKeys/mykey"},
+		},
+		{
+			name:     "arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			expected: kms.Key{Provider: kms.ProviderAWS, Name: "arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab", Region: "eu-west-1"},
+		},
+		{
+			name:     "alias/helmboot",
+			region:   "us-east-1",
+			expected: kms.Key{Provider: kms.ProviderAWS, Name: "alias/helmboot", Region: "us-east-1"},
+		},
+	}
+	for _, tc := range testCases {
+		key, err := kms.ParseKey(tc.name, tc.region)
+		require.NoError(t, err, "failed to parse key %s", tc.name)
+		assert.Equal(t, tc.expected, key, "key %s", tc.name)
+	}
+
+	_, err := kms.ParseKey("mykey", "")
+	assert.Error(t, err, "should have failed to parse an unknown key")
+}
+
+func TestKeyFromRequirements(t *testing.T) {
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.Provider = cloud.GKE
+	requirements.Cluster.ProjectID = "myproject"
+
+	_, err := kms.KeyFromRequirements(requirements)
+	require.Error(t, err, "should have failed without a vault key")
+
+	requirements.Vault.Keyring = "myring"
+	requirements.Vault.Key = "mykey"
+	key, err := kms.KeyFromRequirements(requirements)
+	require.NoError(t, err, "failed to find the key")
+	assert.Equal(t, "projects/myproject/locations/global/keyRings/myring/cryptoKeys/mykey", key.Name, "key name")
+}
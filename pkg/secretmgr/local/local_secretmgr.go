@@ -3,6 +3,7 @@ package local
 import (
 	"fmt"
 
+	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
 	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
 	"github.com/jenkins-x/jx/pkg/jxfactory"
 	"github.com/jenkins-x/jx/pkg/kube"
@@ -59,6 +60,31 @@ func (f *LocalSecretManager) UpsertSecrets(callback secretmgr.SecretCallback, de
 	return nil
 }
 
+// CheckAccess checks whether the current identity can read and write the Secret via access reviews
+func (f *LocalSecretManager) CheckAccess() (secretmgr.Access, error) {
+	access := secretmgr.Access{}
+	read := clienthelpers.ResourcePermission{Verb: "get", Resource: "secrets"}
+	write := []clienthelpers.ResourcePermission{
+		{Verb: "create", Resource: "secrets"},
+		{Verb: "update", Resource: "secrets"},
+	}
+	missing, err := clienthelpers.MissingPermissions(f.KubeClient, f.Namespace, append([]clienthelpers.ResourcePermission{read}, write...))
+	if err != nil {
+		return access, err
+	}
+	access.Read = secretmgr.AccessAllowed
+	access.Write = secretmgr.AccessAllowed
+	for _, p := range missing {
+		if p == read {
+			access.Read = secretmgr.AccessDenied
+		} else {
+			access.Write = secretmgr.AccessDenied
+		}
+		access.Details = append(access.Details, fmt.Sprintf("cannot %s in namespace %s", p.String(), f.Namespace))
+	}
+	return access, nil
+}
+
 func (f *LocalSecretManager) Kind() string {
 	return secretmgr.KindLocal
 }
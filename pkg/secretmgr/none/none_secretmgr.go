@@ -0,0 +1,38 @@
+package none
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+)
+
+// NoneSecretManager a secret manager which does not store any secrets for experimental boots
+// which do not require secrets
+type NoneSecretManager struct {
+}
+
+// NewNoneSecretManager creates a secret manager which does not store any secrets
+func NewNoneSecretManager() secretmgr.SecretManager {
+	return &NoneSecretManager{}
+}
+
+// UpsertSecrets always passes empty secrets to the callback and fails if the callback tries to modify them
+func (f *NoneSecretManager) UpsertSecrets(callback secretmgr.SecretCallback, defaultYaml string) error {
+	answer, err := callback("")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(answer) != "" {
+		return fmt.Errorf("cannot store secrets with the %s secret manager. Please specify a different secret manager kind", secretmgr.KindNone)
+	}
+	return nil
+}
+
+func (f *NoneSecretManager) Kind() string {
+	return secretmgr.KindNone
+}
+
+func (f *NoneSecretManager) String() string {
+	return f.Kind()
+}
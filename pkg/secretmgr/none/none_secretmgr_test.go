@@ -0,0 +1,26 @@
+package none_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/none"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoneSecretManager(t *testing.T) {
+	sm := none.NewNoneSecretManager()
+	assert.Equal(t, secretmgr.KindNone, sm.Kind(), "kind")
+
+	err := sm.UpsertSecrets(func(s string) (string, error) {
+		assert.Equal(t, "", s, "should have no secrets")
+		return s, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "should not fail when reading secrets")
+
+	err = sm.UpsertSecrets(func(s string) (string, error) {
+		return secretmgr.DefaultSecretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.Error(t, err, "should fail when writing secrets")
+}
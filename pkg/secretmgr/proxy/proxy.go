@@ -40,6 +40,23 @@ func (f *ProxySecretManager) UpsertSecrets(callback secretmgr.SecretCallback, de
 	return f.Second.UpsertSecrets(populateCallback, defaultYaml)
 }
 
+// CheckAccess checks the secrets can be read from the first secret manager and written to both
+func (f *ProxySecretManager) CheckAccess() (secretmgr.Access, error) {
+	first, err := secretmgr.CheckAccess(f.First)
+	if err != nil {
+		return first, err
+	}
+	second, err := secretmgr.CheckAccess(f.Second)
+	if err != nil {
+		return second, err
+	}
+	return secretmgr.Access{
+		Read:    first.Read,
+		Write:   secretmgr.CombineAccess(first.Write, second.Write),
+		Details: append(first.Details, second.Details...),
+	}, nil
+}
+
 func (f *ProxySecretManager) Kind() string {
 	return f.First.Kind()
 }
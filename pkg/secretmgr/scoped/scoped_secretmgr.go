@@ -0,0 +1,95 @@
+package scoped
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// ScopedSecretManager reads and writes the secrets of a named environment which are stored under
+// environments.<name>.secrets in the secrets YAML of another secret manager. Any other keys are left untouched
+type ScopedSecretManager struct {
+	SecretManager secretmgr.SecretManager
+	Environment   string
+}
+
+// NewScopedSecretManager scopes the secrets of the secret manager to the given environment. If there is no
+// environment the secret manager is returned unchanged
+func NewScopedSecretManager(sm secretmgr.SecretManager, env string) (secretmgr.SecretManager, error) {
+	if env == "" {
+		return sm, nil
+	}
+	err := secretmgr.ValidateEnvironmentName(env)
+	if err != nil {
+		return nil, err
+	}
+	return &ScopedSecretManager{SecretManager: sm, Environment: env}, nil
+}
+
+// UpsertSecrets upserts the secrets of the environment
+func (f *ScopedSecretManager) UpsertSecrets(callback secretmgr.SecretCallback, defaultYaml string) error {
+	scopedCallback := func(secretYaml string) (string, error) {
+		values := map[string]interface{}{}
+		if strings.TrimSpace(secretYaml) != "" {
+			err := yaml.Unmarshal([]byte(secretYaml), &values)
+			if err != nil {
+				return "", errors.Wrap(err, "failed to unmarshal the secrets YAML")
+			}
+		}
+		envYaml := defaultYaml
+		envSecrets := secretmgr.EnvironmentSecrets(values, f.Environment)
+		if envSecrets != nil {
+			data, err := yaml.Marshal(map[string]interface{}{
+				secretmgr.DefaultSecretsRootKey: envSecrets,
+			})
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to marshal the secrets of environment %s", f.Environment)
+			}
+			envYaml = string(data)
+		}
+
+		updatedYaml, err := callback(envYaml)
+		if err != nil {
+			return "", err
+		}
+		if updatedYaml == envYaml {
+			return secretYaml, nil
+		}
+
+		updated := map[string]interface{}{}
+		err = yaml.Unmarshal([]byte(updatedYaml), &updated)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to unmarshal the secrets YAML of environment %s", f.Environment)
+		}
+		environments, ok := values[secretmgr.EnvironmentsRootKey].(map[string]interface{})
+		if !ok {
+			environments = map[string]interface{}{}
+			values[secretmgr.EnvironmentsRootKey] = environments
+		}
+		environments[f.Environment] = map[string]interface{}{
+			secretmgr.DefaultSecretsRootKey: updated[secretmgr.DefaultSecretsRootKey],
+		}
+		data, err := yaml.Marshal(values)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to marshal the secrets YAML")
+		}
+		return string(data), nil
+	}
+	return f.SecretManager.UpsertSecrets(scopedCallback, defaultYaml)
+}
+
+// CheckAccess checks the access of the underlying secret manager
+func (f *ScopedSecretManager) CheckAccess() (secretmgr.Access, error) {
+	return secretmgr.CheckAccess(f.SecretManager)
+}
+
+func (f *ScopedSecretManager) Kind() string {
+	return f.SecretManager.Kind()
+}
+
+func (f *ScopedSecretManager) String() string {
+	return fmt.Sprintf("%s for environment %s", f.SecretManager.String(), f.Environment)
+}
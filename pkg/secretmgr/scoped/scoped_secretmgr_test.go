@@ -0,0 +1,72 @@
+package scoped_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/fake"
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr/scoped"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestScopedSecretManager(t *testing.T) {
+	fakeSM := &fake.FakeSecretManager{
+		SecretsYAML: "secrets:\n  hmacToken: default-token\n",
+	}
+
+	sm, err := scoped.NewScopedSecretManager(fakeSM, "")
+	require.NoError(t, err, "failed to create the unscoped secret manager")
+	assert.Equal(t, fakeSM, sm, "should not scope the secrets without an environment")
+
+	_, err = scoped.NewScopedSecretManager(fakeSM, "Staging_1")
+	require.Error(t, err, "should have rejected an invalid environment name")
+
+	sm, err = scoped.NewScopedSecretManager(fakeSM, "staging")
+	require.NoError(t, err, "failed to create the scoped secret manager")
+	assert.Equal(t, "fake for environment staging", sm.String(), "description")
+
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		assert.Equal(t, secretmgr.DefaultSecretsYaml, secretsYaml, "should have defaulted the environment secrets")
+		return "secrets:\n  hmacToken: staging-token\n", nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to write the environment secrets")
+
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(fakeSM.SecretsYAML), &values)
+	require.NoError(t, err, "failed to unmarshal the stored secrets YAML")
+	assert.Equal(t, map[string]interface{}{
+		"secrets": map[string]interface{}{
+			"hmacToken": "default-token",
+		},
+		"environments": map[string]interface{}{
+			"staging": map[string]interface{}{
+				"secrets": map[string]interface{}{
+					"hmacToken": "staging-token",
+				},
+			},
+		},
+	}, values, "stored secrets YAML")
+
+	err = sm.UpsertSecrets(func(secretsYaml string) (string, error) {
+		assert.Equal(t, "secrets:\n  hmacToken: staging-token\n", secretsYaml, "should have read the environment secrets")
+		return secretsYaml, nil
+	}, secretmgr.DefaultSecretsYaml)
+	require.NoError(t, err, "failed to read the environment secrets")
+}
+
+func TestEnvironmentSecretsYAML(t *testing.T) {
+	secretsYaml := []byte("secrets:\n  hmacToken: default-token\nenvironments:\n  staging:\n    secrets:\n      hmacToken: staging-token\n")
+
+	data, err := secretmgr.EnvironmentSecretsYAML(secretsYaml, "")
+	require.NoError(t, err)
+	assert.Equal(t, string(secretsYaml), string(data), "should not change the secrets without an environment")
+
+	data, err = secretmgr.EnvironmentSecretsYAML(secretsYaml, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, "secrets:\n  hmacToken: staging-token\n", string(data), "should have extracted the environment secrets")
+
+	_, err = secretmgr.EnvironmentSecretsYAML(secretsYaml, "production")
+	require.Error(t, err, "should fail for an environment without secrets")
+}
@@ -0,0 +1,283 @@
+package secretmgr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultSecretFileDelimiter the default separator of the keys and values of the lines of a secret file
+const DefaultSecretFileDelimiter = ":"
+
+// secretsYAMLRootRegex matches the top level 'secrets' key of a secrets YAML document
+var secretsYAMLRootRegex = regexp.MustCompile(`(?m)^secrets:`)
+
+// LoadSecretFile loads a secret file of lines of the form "foo: bar" or a secrets YAML document
+// decrypting it via sops if it is encrypted
+func LoadSecretFile(fileName string) (map[string][]byte, error) {
+	return LoadSecretFileWithDelimiter(fileName, DefaultSecretFileDelimiter)
+}
+
+// LoadSecretFileWithDelimiter loads a secret file of lines whose keys and values are separated by the delimiter such
+// as "foo=bar" or a secrets YAML document decrypting it via sops if it is encrypted
+func LoadSecretFileWithDelimiter(fileName string, delimiter string) (map[string][]byte, error) {
+	exists, err := util.FileExists(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if secret file %s exists", fileName)
+	}
+
+	if !exists {
+		return nil, errors.Errorf("secret file %s does not exist", fileName)
+	}
+
+	data, err := ReadSecretFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	secretsYAML, err := ExpandSecretsYAML(data, fileName)
+	if err != nil {
+		return nil, err
+	}
+	if secretsYAML != nil {
+		return map[string][]byte{LocalSecretKey: secretsYAML}, nil
+	}
+	return ParseSecretFileWithDelimiter(data, fileName, delimiter), nil
+}
+
+// ParseSecretFileDelimiter returns the delimiter for the given flag value which defaults to a colon. The escape
+// sequence \t or the word tab can be used for tab separated values
+func ParseSecretFileDelimiter(text string) (string, error) {
+	switch text {
+	case "":
+		return DefaultSecretFileDelimiter, nil
+	case `\t`, "tab":
+		return "\t", nil
+	case "#":
+		return "", util.InvalidOptionf("delimiter", text, "the delimiter cannot be # as that starts a comment")
+	}
+	if strings.TrimSpace(text) == "" && text != "\t" {
+		return "", util.InvalidOptionf("delimiter", text, "the delimiter cannot be blank. Use \\t or tab for tab separated values")
+	}
+	return text, nil
+}
+
+// ParseSecretJSON parses a JSON object into the secret data flattening nested objects into dot separated keys
+// such as 'adminUser.username'. An optional top level 'secrets' object is removed as it is added to the secrets YAML
+func ParseSecretJSON(data []byte) (map[string][]byte, error) {
+	values := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(&values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the secrets JSON which must be a JSON object")
+	}
+	if len(values) == 1 {
+		if secrets, ok := values["secrets"].(map[string]interface{}); ok {
+			values = secrets
+		}
+	}
+	answer := map[string][]byte{}
+	err = flattenSecretJSON(answer, "", values)
+	if err != nil {
+		return nil, err
+	}
+	return answer, nil
+}
+
+func flattenSecretJSON(answer map[string][]byte, prefix string, values map[string]interface{}) error {
+	for k, v := range values {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if _, ok := answer[key]; ok {
+			return errors.Errorf("the secret key %s is duplicated in the secrets JSON", key)
+		}
+		switch value := v.(type) {
+		case map[string]interface{}:
+			err := flattenSecretJSON(answer, key, value)
+			if err != nil {
+				return err
+			}
+		case []interface{}:
+			return errors.Errorf("unsupported array value for secret %s in the secrets JSON", key)
+		case nil:
+			answer[key] = []byte{}
+		default:
+			answer[key] = []byte(fmt.Sprintf("%v", value))
+		}
+	}
+	return nil
+}
+
+// ParseSecretFile parses the lines of the form "foo: bar" ignoring blank lines and comments
+func ParseSecretFile(data []byte, fileName string) map[string][]byte {
+	return ParseSecretFileWithDelimiter(data, fileName, DefaultSecretFileDelimiter)
+}
+
+// ParseSecretFileWithDelimiter parses the lines whose key and value are separated by the first occurrence of the
+// delimiter ignoring blank lines and comments. Any later occurrences of the delimiter are part of the value
+func ParseSecretFileWithDelimiter(data []byte, fileName string, delimiter string) map[string][]byte {
+	if delimiter == "" {
+		delimiter = DefaultSecretFileDelimiter
+	}
+	answer := map[string][]byte{}
+	for _, l := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(l)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := strings.SplitN(line, delimiter, 2)
+		if len(entry) == 2 {
+			key := strings.TrimSpace(entry[0])
+			if _, ok := answer[key]; ok {
+				log.Logger().Warnf("duplicate key %s in secret file %s so using the last value", key, fileName)
+			}
+			answer[key] = []byte(strings.TrimSpace(entry[1]))
+		}
+	}
+	return answer
+}
+
+// ValidateSecretKeys validates the dot separated secret keys can be expanded into the nested secrets without
+// overwriting each other. Returns an error naming the keys if any are duplicated, have a blank path segment or one
+// key would be both a value and a parent of another key such as 'a.b' and 'a.b.c'
+func ValidateSecretKeys(keys []string) error {
+	var messages []string
+	found := map[string]bool{}
+	for _, k := range keys {
+		if found[k] {
+			messages = append(messages, fmt.Sprintf("the secret key %s is duplicated", k))
+		}
+		found[k] = true
+		if util.StringArrayIndex(strings.Split(k, "."), "") >= 0 {
+			messages = append(messages, fmt.Sprintf("the secret key %s has a blank path segment", k))
+		}
+	}
+	for _, k := range keys {
+		segments := strings.Split(k, ".")
+		for i := 1; i < len(segments); i++ {
+			parent := strings.Join(segments[:i], ".")
+			if found[parent] {
+				messages = append(messages, fmt.Sprintf("the secret keys %s and %s conflict as %s cannot be both a value and contain other secrets", parent, k, parent))
+			}
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	sort.Strings(messages)
+	return errors.Errorf("invalid secret keys: %s", strings.Join(messages, ", "))
+}
+
+// SecretDataToYAML converts the secret data into the secrets YAML.
+//
+// The YAML is marshalled via encoding/json which sorts map keys so the output is identical
+// for the same input data making it safe to check into git. As overlapping keys are rejected the order the keys
+// are expanded in does not change the output
+func SecretDataToYAML(secretData map[string][]byte) ([]byte, error) {
+	data := secretData[LocalSecretKey]
+	if len(data) > 0 {
+		return data, nil
+	}
+
+	keys := make([]string, 0, len(secretData))
+	for k := range secretData {
+		keys = append(keys, k)
+	}
+	err := ValidateSecretKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]interface{}{}
+	for _, k := range keys {
+		util.SetMapValueViaPath(secrets, k, string(secretData[k]))
+	}
+	values := map[string]interface{}{
+		"secrets": secrets,
+	}
+
+	data, err = yaml.Marshal(values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal data to YAML")
+	}
+	return data, nil
+}
+
+// SecretDataToYAMLWithRootKey converts the secret data into the secrets YAML with the secrets nested under the given
+// dot separated root key such as 'jxRequirements.secrets' rather than the default 'secrets'
+func SecretDataToYAMLWithRootKey(secretData map[string][]byte, rootKey string) ([]byte, error) {
+	data, err := SecretDataToYAML(secretData)
+	if err != nil || rootKey == "" || rootKey == DefaultSecretsRootKey {
+		return data, err
+	}
+	values := map[string]interface{}{}
+	err = yaml.Unmarshal(data, &values)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal secrets YAML")
+	}
+	answer := map[string]interface{}{}
+	util.SetMapValueViaPath(answer, rootKey, values[DefaultSecretsRootKey])
+	data, err = yaml.Marshal(answer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal data to YAML")
+	}
+	return data, nil
+}
+
+// ValidateRootKey validates the root key is a dot separated path without any empty segments
+func ValidateRootKey(rootKey string) error {
+	for _, segment := range strings.Split(rootKey, ".") {
+		if strings.TrimSpace(segment) == "" {
+			return util.InvalidOptionf("root-key", rootKey, "the root key must be a dot separated path such as jxRequirements.secrets")
+		}
+	}
+	return nil
+}
+
+// ExpandSecretsYAML returns the given secrets YAML document with any YAML anchors and aliases resolved
+// so that every consumer sees the complete values. Returns nil if the data is not a secrets YAML document
+// or an error if an alias references an undefined anchor
+func ExpandSecretsYAML(data []byte, fileName string) ([]byte, error) {
+	if !secretsYAMLRootRegex.Match(data) {
+		return nil, nil
+	}
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal(data, &values)
+	if err != nil {
+		if strings.Contains(err.Error(), "unknown anchor") {
+			return nil, errors.Wrapf(err, "secret file %s has a YAML alias which references an undefined anchor", fileName)
+		}
+		// lets treat it as a file of "foo.bar: value" lines
+		return nil, nil
+	}
+	if _, ok := values["secrets"]; !ok {
+		return nil, nil
+	}
+	answer, err := yaml.Marshal(values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal secret file %s to YAML", fileName)
+	}
+	return answer, nil
+}
+
+// IsSecretsYAML returns true if the given data is a secrets YAML document with a top level 'secrets' key
+// rather than a file of lines of the form "foo.bar: value"
+func IsSecretsYAML(data []byte) bool {
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal(data, &values)
+	if err != nil {
+		return false
+	}
+	_, ok := values["secrets"]
+	return ok
+}
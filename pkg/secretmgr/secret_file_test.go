@@ -0,0 +1,148 @@
+package secretmgr_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/jenkins-x-labs/helmboot/pkg/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandSecretsYAML(t *testing.T) {
+	anchorsYAML := `common: &pipelineUser
+  username: mybot
+  token: mytoken
+secrets:
+  pipelineUser: *pipelineUser
+  adminUser:
+    <<: *pipelineUser
+    password: dummypwd
+`
+	expectedYAML := `common:
+  username: mybot
+  token: mytoken
+secrets:
+  pipelineUser:
+    username: mybot
+    token: mytoken
+  adminUser:
+    username: mybot
+    token: mytoken
+    password: dummypwd
+`
+	actual, err := secretmgr.ExpandSecretsYAML([]byte(anchorsYAML), "secrets.yaml")
+	require.NoError(t, err, "failed to expand the secrets YAML")
+	testhelpers.AssertYamlEqual(t, expectedYAML, string(actual), "should have expanded the aliases")
+
+	_, err = secretmgr.ExpandSecretsYAML([]byte("secrets:\n  pipelineUser: *doesNotExist\n"), "secrets.yaml")
+	require.Error(t, err, "should have failed for an undefined anchor")
+	assert.Contains(t, err.Error(), "undefined anchor", "error message")
+
+	actual, err = secretmgr.ExpandSecretsYAML([]byte("adminUser.password: *notAnAlias\n"), "secrets.txt")
+	require.NoError(t, err, "should not fail for a flat secrets file")
+	assert.Nil(t, actual, "should not treat a flat secrets file as secrets YAML")
+}
+
+func TestParseSecretJSON(t *testing.T) {
+	data, err := secretmgr.ParseSecretJSON([]byte(`{"secrets": {"adminUser": {"username": "admin", "password": "dummypwd"}, "hmacToken": 1234567890123, "enabled": true}}`))
+	require.NoError(t, err, "failed to parse secrets JSON")
+	assert.Equal(t, map[string][]byte{
+		"adminUser.username": []byte("admin"),
+		"adminUser.password": []byte("dummypwd"),
+		"hmacToken":          []byte("1234567890123"),
+		"enabled":            []byte("true"),
+	}, data)
+
+	for _, text := range []string{`{"adminUser": `, `["admin"]`, `{"users": ["admin"]}`} {
+		_, err = secretmgr.ParseSecretJSON([]byte(text))
+		assert.Error(t, err, "should have failed to parse %s", text)
+	}
+}
+
+func TestSecretDataToYAMLWithRootKey(t *testing.T) {
+	secretData := map[string][]byte{
+		"adminUser.username": []byte("admin"),
+		"hmacToken":          []byte("abc"),
+	}
+	actual, err := secretmgr.SecretDataToYAMLWithRootKey(secretData, "jxRequirements.secrets")
+	require.NoError(t, err, "failed to generate secrets YAML")
+	testhelpers.AssertYamlEqual(t, `jxRequirements:
+  secrets:
+    adminUser:
+      username: admin
+    hmacToken: abc
+`, string(actual), "secrets YAML with root key")
+
+	actual, err = secretmgr.SecretDataToYAMLWithRootKey(secretData, secretmgr.DefaultSecretsRootKey)
+	require.NoError(t, err, "failed to generate secrets YAML")
+	expected, err := secretmgr.SecretDataToYAML(secretData)
+	require.NoError(t, err, "failed to generate secrets YAML")
+	assert.Equal(t, string(expected), string(actual), "should use the default layout")
+
+	assert.NoError(t, secretmgr.ValidateRootKey("jxRequirements.secrets"))
+	assert.Error(t, secretmgr.ValidateRootKey("jxRequirements..secrets"))
+	assert.Error(t, secretmgr.ValidateRootKey(""))
+}
+
+func TestValidateSecretKeys(t *testing.T) {
+	testCases := []struct {
+		name     string
+		keys     []string
+		expected []string
+	}{
+		{name: "valid", keys: []string{"adminUser.username", "adminUser.password", "hmacToken", "adminUser-old"}},
+		{name: "scalar and map", keys: []string{"adminUser", "adminUser.password"}, expected: []string{"adminUser and adminUser.password conflict"}},
+		{name: "nested scalar and map", keys: []string{"a.b.c.d", "a.b"}, expected: []string{"a.b and a.b.c.d conflict"}},
+		{name: "duplicate", keys: []string{"hmacToken", "adminUser.password", "hmacToken"}, expected: []string{"hmacToken is duplicated"}},
+		{name: "blank segment", keys: []string{"adminUser..password"}, expected: []string{"adminUser..password has a blank path segment"}},
+	}
+	for _, tc := range testCases {
+		err := secretmgr.ValidateSecretKeys(tc.keys)
+		if len(tc.expected) == 0 {
+			assert.NoError(t, err, "for %s", tc.name)
+			continue
+		}
+		require.Error(t, err, "for %s", tc.name)
+		for _, e := range tc.expected {
+			assert.Contains(t, err.Error(), e, "error message for %s", tc.name)
+		}
+	}
+
+	_, err := secretmgr.SecretDataToYAML(map[string][]byte{
+		"pipelineUser":       []byte("someuser"),
+		"pipelineUser.token": []byte("dummytoken"),
+	})
+	require.Error(t, err, "should not silently overwrite the pipelineUser value")
+	assert.Contains(t, err.Error(), "pipelineUser and pipelineUser.token conflict", "error message")
+
+	_, err = secretmgr.ParseSecretJSON([]byte(`{"adminUser.password": "a", "adminUser": {"password": "b"}}`))
+	require.Error(t, err, "should have failed with a duplicate key")
+	assert.Contains(t, err.Error(), "adminUser.password is duplicated", "error message")
+}
+
+func TestParseSecretFileWithDelimiter(t *testing.T) {
+	testCases := map[string]string{
+		":":  "# a comment\n\nadminUser.username: admin\npipelineUser.token: a:b:c\n",
+		"=":  "# a comment\n\nadminUser.username=admin\npipelineUser.token = a=b=c\n",
+		"\t": "# a comment\n\nadminUser.username\tadmin\npipelineUser.token\ta\tb\tc\n",
+	}
+	for delimiter, text := range testCases {
+		data := secretmgr.ParseSecretFileWithDelimiter([]byte(text), "secrets.txt", delimiter)
+		assert.Len(t, data, 2, "secrets for delimiter %q", delimiter)
+		assert.Equal(t, "admin", string(data["adminUser.username"]), "username for delimiter %q", delimiter)
+		expectedToken := strings.Join([]string{"a", "b", "c"}, delimiter)
+		assert.Equal(t, expectedToken, string(data["pipelineUser.token"]), "token for delimiter %q", delimiter)
+	}
+
+	for text, expected := range map[string]string{"": ":", "=": "=", `\t`: "\t", "tab": "\t", "\t": "\t"} {
+		delimiter, err := secretmgr.ParseSecretFileDelimiter(text)
+		require.NoError(t, err, "failed to parse delimiter %q", text)
+		assert.Equal(t, expected, delimiter, "delimiter %q", text)
+	}
+	for _, text := range []string{"#", " "} {
+		_, err := secretmgr.ParseSecretFileDelimiter(text)
+		assert.Error(t, err, "should have rejected delimiter %q", text)
+	}
+}
@@ -0,0 +1,60 @@
+package secretmgr
+
+import (
+	"io/ioutil"
+	"os/exec"
+
+	"github.com/jenkins-x/jx/pkg/log"
+	"github.com/jenkins-x/jx/pkg/util"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// SopsBinary the name of the sops binary used to decrypt encrypted secret files
+const SopsBinary = "sops"
+
+// ReadSecretFile reads the given secret file decrypting it via sops if it is encrypted
+func ReadSecretFile(fileName string) ([]byte, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load secret file %s", fileName)
+	}
+	if !IsSopsEncrypted(data) {
+		return data, nil
+	}
+	return DecryptSopsFile(fileName)
+}
+
+// IsSopsEncrypted returns true if the given data is a sops encrypted YAML or JSON document which
+// are recognised by the top level 'sops' metadata containing the message authentication code
+func IsSopsEncrypted(data []byte) bool {
+	values := map[string]interface{}{}
+	err := yaml.Unmarshal(data, &values)
+	if err != nil {
+		return false
+	}
+	metadata, ok := values["sops"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = metadata["mac"]
+	return ok
+}
+
+// DecryptSopsFile decrypts the given sops encrypted file returning the plain text
+func DecryptSopsFile(fileName string) ([]byte, error) {
+	_, err := exec.LookPath(SopsBinary)
+	if err != nil {
+		return nil, errors.Errorf("the secret file %s is encrypted with sops but the %s binary could not be found on the $PATH", fileName, SopsBinary)
+	}
+	c := util.Command{
+		Name: SopsBinary,
+		Args: []string{"--decrypt", fileName},
+	}
+	log.Logger().Debugf("decrypting secret file %s with %s", fileName, SopsBinary)
+	text, err := c.RunWithoutRetry()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt secret file %s with %s. Please check the key material used to encrypt it is available", fileName, SopsBinary)
+	}
+	return []byte(text), nil
+}
@@ -0,0 +1,21 @@
+package secretmgr_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x-labs/helmboot/pkg/secretmgr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSopsEncrypted(t *testing.T) {
+	testCases := map[string]bool{
+		"adminUser.username: admin\n":  false,
+		"secrets:\n  hmacToken: abc\n": false,
+		"secrets:\n  hmacToken: ENC[AES256_GCM,data:abc,type:str]\nsops:\n  mac: ENC[AES256_GCM,data:def,type:str]\n  version: 3.5.0\n": true,
+		`{"hmacToken": "ENC[AES256_GCM,data:abc,type:str]", "sops": {"mac": "ENC[AES256_GCM,data:def,type:str]"}}`:                      true,
+		"not: [valid yaml": false,
+	}
+	for text, expected := range testCases {
+		assert.Equal(t, expected, secretmgr.IsSopsEncrypted([]byte(text)), "for %s", text)
+	}
+}
@@ -3,11 +3,13 @@ package client
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"path/filepath"
 
 	vaultapi "github.com/hashicorp/vault/api"
 	"github.com/jenkins-x-labs/helmboot/pkg/clienthelpers"
+	"github.com/jenkins-x-labs/helmboot/pkg/common"
 	"github.com/jenkins-x/jx/pkg/jxfactory"
 	"github.com/jenkins-x/jx/pkg/log"
 	"github.com/jenkins-x/jx/pkg/util"
@@ -97,7 +99,14 @@ func (f *Factory) NewClient() (*vaultapi.Client, error) {
 	if config == nil {
 		return nil, fmt.Errorf("no default config created")
 	}
-	return vaultapi.NewClient(config)
+	client, err := vaultapi.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	client.SetHeaders(http.Header{
+		"User-Agent": []string{common.UserAgent()},
+	})
+	return client, nil
 }
 
 func (f *Factory) loadVaultToken() (string, error) {
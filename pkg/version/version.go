@@ -0,0 +1,19 @@
+package version
+
+// The values are populated at build time via -ldflags by the Makefile and goreleaser
+var (
+	// Version the version of helmboot
+	Version = "dev"
+
+	// Revision the git commit helmboot was built from
+	Revision string
+
+	// Branch the git branch helmboot was built from
+	Branch string
+
+	// BuildDate the date helmboot was built
+	BuildDate string
+
+	// GoVersion the version of Go helmboot was built with
+	GoVersion string
+)